@@ -4,6 +4,7 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"nstudio/app/cache"
@@ -12,11 +13,14 @@ import (
 	"nstudio/app/common/issue"
 	"nstudio/app/common/process"
 	"nstudio/app/common/response"
+	scriptParser "nstudio/app/common/script"
+	"nstudio/app/common/ssml"
 	"nstudio/app/common/status"
 	"nstudio/app/common/util"
 	"nstudio/app/common/util/fileIndex"
 	"nstudio/app/config"
 	"nstudio/app/enums/OutputType"
+	"nstudio/app/project"
 	"nstudio/app/tts"
 	"nstudio/app/tts/engine/piper"
 	"nstudio/app/tts/engine/piper/native"
@@ -25,7 +29,6 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
 	"runtime"
 	"strings"
 	"time"
@@ -50,38 +53,30 @@ func (app *App) Play(
 		profileID = "default"
 	}
 
-	lines := strings.Split(script, "\n")
-	var messages []util.CharacterMessage
+	entries, ok := parseScript(script)
+	if !ok {
+		status.Set(status.Ready, "")
+		return
+	}
 
-	regex := regexp.MustCompile(`^([^:]+):\s*(.*)$`)
-	for _, line := range lines {
-		if ttsLine := regex.FindStringSubmatch(line); ttsLine != nil {
-			var character string
-			if overrideVoices != "" {
-				character = overrideVoices
-			} else {
-				character = ttsLine[1]
-			}
-			text := ttsLine[2]
-			messages = append(messages, util.CharacterMessage{
-				Character: character,
-				Text:      text,
-				Save:      saveNewCharacters,
-			})
-			response.Debug(util.MessageData{
-				Summary: "added message by character: " + character,
-				Detail:  text,
-			})
+	for _, entry := range entries {
+		if overrideVoices != "" {
+			entry.Character = overrideVoices
 		}
+		entry.Save = saveNewCharacters
+		response.Debug(util.MessageData{
+			Summary: "added message by character: " + entry.Character,
+			Detail:  entry.Text,
+		})
 	}
 
+	ctx, done := tts.Track(app.context, "")
+	defer done()
+
 	fileIndex.Reset()
-	err := tts.GenerateSpeech(messages, false, profileID)
+	err := tts.GenerateScript(ctx, entries, false, profileID)
 	if err != nil {
-		response.Error(util.MessageData{
-			Summary: "Failed to play script",
-			Detail:  err.Error(),
-		})
+		reportGenerationError(ctx, "Failed to play script", err)
 	} else {
 		response.Success(util.MessageData{
 			Summary: "Success",
@@ -92,6 +87,30 @@ func (app *App) Play(
 	status.Set(status.Ready, "")
 }
 
+// CancelGeneration stops whatever is being played, processed, imported or rendered. The lines saved before it
+// stopped are kept.
+func (app *App) CancelGeneration() {
+	if tts.CancelAll() > 0 {
+		status.Set(status.Loading, "Cancelling")
+	}
+}
+
+// reportGenerationError tells a cancelled generation apart from a failed one
+func reportGenerationError(ctx context.Context, summary string, err error) {
+	if ctx.Err() != nil {
+		response.Warning(util.MessageData{
+			Summary: "Generation cancelled",
+			Detail:  "Stopped before every line was generated",
+		})
+		return
+	}
+
+	response.Error(util.MessageData{
+		Summary: summary,
+		Detail:  err.Error(),
+	})
+}
+
 //</editor-fold>
 
 // <editor-fold desc="Script Editor">
@@ -105,21 +124,110 @@ func (app *App) ProcessScript(script string, profileID string) {
 		profileID = "default"
 	}
 
-	lines := strings.Split(script, "\n")
-	var messages []util.CharacterMessage
+	entries, ok := parseScript(script)
+	if !ok {
+		return
+	}
 
-	regex := regexp.MustCompile(`^([^:]+):\s*(.*)$`)
-	for _, line := range lines {
-		if ttsLine := regex.FindStringSubmatch(line); ttsLine != nil {
-			character := strings.TrimSpace(ttsLine[1])
-			text := strings.TrimSpace(ttsLine[2])
-
-			messages = append(messages, util.CharacterMessage{
-				Character: character,
-				Text:      text,
-				Save:      true,
-			})
-		}
+	ctx, done := tts.Track(app.context, "")
+	defer done()
+
+	if plan, ok := renderEntries(ctx, entries, profileID); ok {
+		response.Success(util.MessageData{
+			Summary: "Success",
+			Detail:  fmt.Sprintf("Script processed successfully: %d lines generated, %d reused", plan.Generated, plan.Reused),
+		})
+	}
+}
+
+// PlanScript is a dry run of ProcessScript: it returns which lines would be generated and which would reuse the
+// audio of an earlier render, without generating anything
+func (app *App) PlanScript(script string, profileID string) string {
+	if profileID == "" {
+		profileID = "default"
+	}
+
+	entries, ok := parseScript(script)
+	if !ok {
+		return ""
+	}
+
+	plan, err := tts.PlanScript(entries, profileID)
+	if err != nil {
+		response.Error(util.MessageData{
+			Summary: "Failed to plan script",
+			Detail:  err.Error(),
+		})
+		return ""
+	}
+
+	planJSON, _ := json.Marshal(plan)
+	return string(planJSON)
+}
+
+// ImportScript renders a Fountain, SRT, WebVTT or CSV file like ProcessScript, naming the files after its cues
+func (app *App) ImportScript(path string, profileID string) {
+	clearConsole()
+	status.Set(status.Loading, "Importing Script")
+	defer status.Set(status.Ready, "")
+
+	if profileID == "" {
+		profileID = "default"
+	}
+
+	err, expandedPath := util.ExpandPath(path)
+	if err != nil {
+		response.Error(util.MessageData{
+			Summary: "Failed to expand path",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	data, err := os.ReadFile(expandedPath)
+	if err != nil {
+		response.Error(util.MessageData{
+			Summary: "Failed to read script",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	messages, err := scriptParser.Import(filepath.Base(expandedPath), data, scriptParser.ImportOptions{})
+	if err != nil {
+		response.Error(util.MessageData{
+			Summary: "Failed to import " + filepath.Base(expandedPath),
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	entries := make([]*scriptParser.Entry, len(messages))
+	for index, message := range messages {
+		entries[index] = &scriptParser.Entry{CharacterMessage: message, Line: index + 1, EndLine: index + 1}
+	}
+
+	ctx, done := tts.Track(app.context, "")
+	defer done()
+
+	if plan, ok := renderEntries(ctx, entries, profileID); ok {
+		response.Success(util.MessageData{
+			Summary: "Success",
+			Detail:  fmt.Sprintf("Imported and processed %d lines: %d generated, %d reused", len(entries), plan.Generated, plan.Reused),
+		})
+	}
+}
+
+const (
+	combinedFilename = "combined.wav"
+	combinedPause    = time.Second
+)
+
+// renderEntries saves every entry and, depending on the output type, combines them into one file. Entries that
+// didn't change since they were last rendered reuse their audio, the others are generated in parallel.
+func renderEntries(ctx context.Context, entries []*scriptParser.Entry, profileID string) (*tts.RenderPlan, bool) {
+	for _, entry := range entries {
+		entry.Save = true
 	}
 
 	response.Debug(util.MessageData{
@@ -128,13 +236,10 @@ func (app *App) ProcessScript(script string, profileID string) {
 
 	status.Set(status.Generating, "")
 	fileIndex.Reset()
-	err := tts.GenerateSpeech(messages, true, profileID)
+	plan, err := tts.RenderScript(ctx, entries, profileID)
 	if err != nil {
-		response.Error(util.MessageData{
-			Summary: "Failed to process script",
-			Detail:  err.Error(),
-		})
-		return
+		reportGenerationError(ctx, "Failed to process script", err)
+		return nil, false
 	}
 
 	outputType := config.GetSettings().OutputType
@@ -149,7 +254,7 @@ func (app *App) ProcessScript(script string, profileID string) {
 				Summary: "Failed to expand path",
 				Detail:  err.Error(),
 			})
-			return
+			return nil, false
 		}
 
 		outputPath := filepath.Join(
@@ -158,51 +263,329 @@ func (app *App) ProcessScript(script string, profileID string) {
 			fileIndex.Timestamp(),
 		)
 
-		err = audio.CombineWAVFiles(
-			outputPath,
-			"combined.wav",
-			time.Second,
-			48000,
-			1,
-			16,
-		)
+		var clips []audio.Clip
+		var overruns []audio.Overrun
+
+		if alignment := config.GetSettings().CueAlignment; alignment.Enabled && hasCueTimes(entries) {
+			clips, overruns, err = alignEntries(entries, outputPath, alignment.MaxSpeedup)
+		} else {
+			clips, err = audio.CombineWAVFiles(
+				outputPath,
+				combinedFilename,
+				combinedPause,
+				48000,
+				1,
+				16,
+			)
+		}
 		if err != nil {
 			response.Error(util.MessageData{
 				Summary: "Failed to combine wav files",
 				Detail:  err.Error(),
 			})
-			return
+			return nil, false
+		}
+
+		lines := timedLines(entries, clips, overruns, outputType == OutputType.Both)
+		reportOverruns(lines)
+		err = scriptParser.WriteCueFiles(outputPath, combinedFilename, combinedPause, lines)
+		if err != nil {
+			response.Error(util.MessageData{
+				Summary: "Failed to write subtitles",
+				Detail:  err.Error(),
+			})
+			return nil, false
 		}
 
 		if outputType == OutputType.CombinedFile {
+			keep := append(scriptParser.CueFilenames(combinedFilename), combinedFilename)
+
 			files, err := os.ReadDir(outputPath)
 			if err != nil {
 				response.Error(util.MessageData{
 					Summary: "Failed to read directory",
 					Detail:  err.Error(),
 				})
-				return
+				return nil, false
 			}
 
 			for _, file := range files {
-				if !file.IsDir() && file.Name() != "combined.wav" {
+				if !file.IsDir() && !util.InArray(file.Name(), keep) {
 					err = os.Remove(filepath.Join(outputPath, file.Name()))
 					if err != nil {
 						response.Error(util.MessageData{
 							Summary: "Failed to delete file",
 							Detail:  err.Error(),
 						})
-						return
+						return nil, false
 					}
 				}
 			}
 		}
 	}
 
+	return plan, true
+}
+
+// hasCueTimes reports whether the entries were imported with cue times, from subtitles or a timed CSV
+func hasCueTimes(entries []*scriptParser.Entry) bool {
+	for _, entry := range entries {
+		if entry.Cue.HasTiming() {
+			return true
+		}
+	}
+	return false
+}
+
+// alignEntries combines the generated files so every line starts at its cue time
+func alignEntries(entries []*scriptParser.Entry, outputPath string, maxSpeedup float64) ([]audio.Clip, []audio.Overrun, error) {
+	files, err := audio.ListWAVFiles(outputPath, combinedFilename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	slots := make([]audio.Slot, len(files))
+	for position, path := range files {
+		slots[position] = audio.Slot{Path: path}
+		if entry := entryForFile(entries, path, position); entry != nil && entry.Cue.HasTiming() {
+			slots[position].Start = entry.Cue.Start
+			slots[position].End = entry.Cue.End
+		}
+	}
+
+	return audio.AlignWAVFiles(outputPath, combinedFilename, slots, combinedPause, maxSpeedup, 48000, 1, 16)
+}
+
+// entryForFile finds the entry a generated file belongs to. Files are named after fileIndex, which counts from 0
+// in the order of the entries.
+func entryForFile(entries []*scriptParser.Entry, path string, position int) *scriptParser.Entry {
+	index := position
+	if _, err := fmt.Sscanf(filepath.Base(path), "%d)", &index); err != nil {
+		index = position
+	}
+	if index < 0 || index >= len(entries) {
+		return nil
+	}
+	return entries[index]
+}
+
+// reportOverruns warns about the lines that run past the end of their cue
+func reportOverruns(lines []scriptParser.TimedLine) {
+	var details []string
+	for _, line := range lines {
+		if line.Overrun > 0 {
+			details = append(details, fmt.Sprintf("%s %s: %.2fs too long (played at %.2fx)",
+				line.ID, line.Character, line.Overrun.Seconds(), line.Tempo))
+		}
+	}
+
+	if len(details) > 0 {
+		response.Warning(util.MessageData{
+			Summary: fmt.Sprintf("%d lines run past their cue", len(details)),
+			Detail:  strings.Join(details, "\n"),
+		})
+	}
+}
+
+// timedLines pairs the combined clips with the entries they were generated from
+func timedLines(entries []*scriptParser.Entry, clips []audio.Clip, overruns []audio.Overrun, keepFiles bool) []scriptParser.TimedLine {
+	lines := make([]scriptParser.TimedLine, 0, len(clips))
+
+	for position, clip := range clips {
+		line := scriptParser.TimedLine{Start: clip.Start, End: clip.End}
+		if keepFiles {
+			line.File = filepath.Base(clip.Path)
+		}
+		for _, overrun := range overruns {
+			if overrun.Path == clip.Path {
+				line.Overrun = overrun.Excess()
+				line.Tempo = overrun.Tempo
+			}
+		}
+
+		if entry := entryForFile(entries, clip.Path, position); entry != nil {
+			line.Character = entry.Character
+			line.Text = entry.Text
+			if ssml.IsSSML(entry.Text) {
+				if text, err := ssml.Strip(entry.Text); err == nil {
+					line.Text = text
+				}
+			}
+			if entry.Cue != nil {
+				line.ID = entry.Cue.ID
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// parseScript reads the editor's script, reporting every line that could not be parsed
+func parseScript(script string) ([]*scriptParser.Entry, bool) {
+	parsed, err := scriptParser.Parse(script)
+	if err != nil {
+		response.Error(util.MessageData{
+			Summary: "Failed to parse script",
+			Detail:  err.Error(),
+		})
+		return nil, false
+	}
+
+	entries := parsed.Entries()
+	if len(entries) == 0 {
+		response.Error(util.MessageData{
+			Summary: "Nothing to generate",
+			Detail:  "The script has no \"Character: text\" lines",
+		})
+		return nil, false
+	}
+
+	return entries, true
+}
+
+//</editor-fold>
+
+// <editor-fold desc="Projects">
+
+func (app *App) CreateProject(path string, name string, profileID string) string {
+	err, expandedPath := util.ExpandPath(path)
+	if err != nil {
+		response.Error(util.MessageData{
+			Summary: "Failed to expand path",
+			Detail:  err.Error(),
+		})
+		return ""
+	}
+
+	created, err := project.Create(expandedPath, name, profileID)
+	if err != nil {
+		response.Error(util.MessageData{
+			Summary: "Failed to create project",
+			Detail:  err.Error(),
+		})
+		return ""
+	}
+
 	response.Success(util.MessageData{
-		Summary: "Success",
-		Detail:  "Script processed successfully",
+		Summary: "Project created",
+		Detail:  fmt.Sprintf("Project '%s' has been created", created.Manifest.Name),
+	})
+
+	projectJSON, _ := json.Marshal(created)
+	return string(projectJSON)
+}
+
+func (app *App) OpenProject(path string) string {
+	opened, ok := openProject(path)
+	if !ok {
+		return ""
+	}
+
+	projectJSON, _ := json.Marshal(opened)
+	return string(projectJSON)
+}
+
+// SaveProject stores the editor's script and the chosen profile in the project
+func (app *App) SaveProject(path string, script string, profileID string) string {
+	opened, ok := openProject(path)
+	if !ok {
+		return ""
+	}
+
+	opened.Script = script
+	if profileID != "" {
+		opened.Manifest.Profile = profileID
+	}
+
+	if err := opened.Save(); err != nil {
+		response.Error(util.MessageData{
+			Summary: "Failed to save project",
+			Detail:  err.Error(),
+		})
+		return ""
+	}
+
+	response.Success(util.MessageData{
+		Summary: "Project saved",
 	})
+
+	projectJSON, _ := json.Marshal(opened)
+	return string(projectJSON)
+}
+
+// RenderProject generates the lines that changed since the last render, plus a new take of the retake lines, and
+// rebuilds the project's combined file. A dry run only reports what would be generated.
+func (app *App) RenderProject(path string, retake []string, force bool, dryRun bool) string {
+	clearConsole()
+	status.Set(status.Loading, "Rendering Project")
+	defer status.Set(status.Ready, "")
+
+	opened, ok := openProject(path)
+	if !ok {
+		return ""
+	}
+
+	ctx, done := tts.Track(app.context, "")
+	defer done()
+
+	result, err := opened.Render(ctx, project.RenderOptions{Retake: retake, Force: force, DryRun: dryRun})
+	if err != nil {
+		reportGenerationError(ctx, "Failed to render project", err)
+		return ""
+	}
+
+	if dryRun {
+		resultJSON, _ := json.Marshal(result)
+		return string(resultJSON)
+	}
+
+	response.Success(util.MessageData{
+		Summary: "Project rendered",
+		Detail:  fmt.Sprintf("%d lines generated, %d reused", result.Generated, result.Reused),
+	})
+
+	resultJSON, _ := json.Marshal(result)
+	return string(resultJSON)
+}
+
+func (app *App) SelectProjectTake(path string, lineID string, take int) string {
+	opened, ok := openProject(path)
+	if !ok {
+		return ""
+	}
+
+	if err := opened.SelectTake(lineID, take); err != nil {
+		response.Error(util.MessageData{
+			Summary: "Failed to select take",
+			Detail:  err.Error(),
+		})
+		return ""
+	}
+
+	projectJSON, _ := json.Marshal(opened)
+	return string(projectJSON)
+}
+
+func openProject(path string) (*project.Project, bool) {
+	err, expandedPath := util.ExpandPath(path)
+	if err != nil {
+		response.Error(util.MessageData{
+			Summary: "Failed to expand path",
+			Detail:  err.Error(),
+		})
+		return nil, false
+	}
+
+	opened, err := project.Open(expandedPath)
+	if err != nil {
+		response.Error(util.MessageData{
+			Summary: "Failed to open project",
+			Detail:  err.Error(),
+		})
+		return nil, false
+	}
+	return opened, true
 }
 
 //</editor-fold>
@@ -0,0 +1,337 @@
+package cache
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"nstudio/app/common/audio"
+	"nstudio/app/common/response"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// A bundle carries cached lines from one install to another as a zip file:
+//
+//	manifest.json     the profile and the characters' references, see bundleManifest
+//	store/<key>.wav   the audio of every key, once
+//	store/<key>.json  its record
+//
+// Importing checks every line against its key and checksum before it goes into the store, so a damaged or edited
+// bundle can't put the wrong audio behind a line. The lines are found again on the other install when its profile
+// gives the characters the same voices.
+
+const (
+	bundleVersion  = 1
+	bundleManifest = "manifest.json"
+	bundleStore    = "store"
+)
+
+type bundleManifestFile struct {
+	Version    int                 `json:"version"`
+	Profile    string              `json:"profile"`
+	Created    time.Time           `json:"created"`
+	Characters map[string][]string `json:"characters"` // the keys each character references
+}
+
+// BundleSummary describes an exported or imported bundle
+type BundleSummary struct {
+	Profile    string `json:"profile"`
+	Characters int    `json:"characters"`
+	Entries    int    `json:"entries"`    // audio files in the bundle
+	References int    `json:"references"` // character references to them
+	Bytes      int64  `json:"bytes"`
+	Skipped    int    `json:"skipped,omitempty"` // references whose audio was missing or didn't match its key
+}
+
+// ExportBundle writes the lines a profile references to writer as a bundle. With keys, only the lines of those keys
+// are exported, otherwise all of them.
+func (cacheManager *CacheManager) ExportBundle(writer io.Writer, profileID string, keys []string) (*BundleSummary, error) {
+	if !cacheManager.IsEnabled() {
+		return nil, ErrDisabled
+	}
+
+	profileCache, err := cacheManager.loadProfileCache(profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := bundleManifestFile{
+		Version:    bundleVersion,
+		Profile:    profileID,
+		Created:    time.Now(),
+		Characters: map[string][]string{},
+	}
+	selected := map[string]bool{}
+	for _, key := range keys {
+		selected[key] = true
+	}
+
+	profileCache.mutex.RLock()
+	for character, characterCache := range profileCache.Characters {
+		for key := range characterCache.Lines {
+			if keys == nil || selected[key] {
+				manifest.Characters[character] = append(manifest.Characters[character], key)
+			}
+		}
+	}
+	profileCache.mutex.RUnlock()
+
+	summary := &BundleSummary{Profile: profileID}
+	archive := zip.NewWriter(writer)
+	written := map[string]bool{}
+
+	for character, characterKeys := range manifest.Characters {
+		slices.Sort(characterKeys)
+		exported := characterKeys[:0]
+		for _, key := range characterKeys {
+			if !written[key] {
+				size, err := cacheManager.exportStored(archive, key)
+				if errors.Is(err, errArchive) {
+					return nil, response.Err(err)
+				}
+				if err != nil {
+					response.Warn("Skipping cached line: %v\n", err)
+					summary.Skipped++
+					continue
+				}
+				written[key] = true
+				summary.Entries++
+				summary.Bytes += size
+			}
+			exported = append(exported, key)
+		}
+
+		if len(exported) == 0 {
+			delete(manifest.Characters, character)
+			continue
+		}
+		manifest.Characters[character] = exported
+		summary.Characters++
+		summary.References += len(exported)
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, response.Err(err)
+	}
+	manifestWriter, err := archive.Create(bundleManifest)
+	if err != nil {
+		return nil, response.Err(err)
+	}
+	if _, err := manifestWriter.Write(manifestData); err != nil {
+		return nil, response.Err(err)
+	}
+	if err := archive.Close(); err != nil {
+		return nil, response.Err(err)
+	}
+
+	response.LogInfo(fmt.Sprintf("Exported %d cached lines of profile '%s'\n", summary.Entries, profileID))
+	return summary, nil
+}
+
+// errArchive wraps errors writing the bundle itself, which end the export rather than skip a line
+var errArchive = errors.New("failed to write the bundle")
+
+// exportStored adds the audio of key and its record to the bundle once they match, returning the size of the audio.
+// Only reading them holds the store lock, so a slow writer doesn't hold up caching and eviction.
+func (cacheManager *CacheManager) exportStored(archive *zip.Writer, key string) (int64, error) {
+	wavData, recordData, err := cacheManager.readExported(key)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{{key + ".wav", wavData}, {key + ".json", recordData}} {
+		fileWriter, err := archive.Create(path.Join(bundleStore, file.name))
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", errArchive, err)
+		}
+		if _, err := fileWriter.Write(file.data); err != nil {
+			return 0, fmt.Errorf("%w: %w", errArchive, err)
+		}
+	}
+	return int64(len(wavData)), nil
+}
+
+// readExported checks the audio of key against its record and reads both under the store lock, so eviction can't
+// remove the audio in between
+func (cacheManager *CacheManager) readExported(key string) ([]byte, []byte, error) {
+	cacheManager.storeMutex.Lock()
+	defer cacheManager.storeMutex.Unlock()
+
+	if detail := cacheManager.checkStored(key, true); detail != "" {
+		return nil, nil, fmt.Errorf("%s: %s", key, detail)
+	}
+
+	wavData, err := os.ReadFile(cacheManager.getStorePath(key))
+	if err != nil {
+		return nil, nil, err
+	}
+	recordData, err := os.ReadFile(cacheManager.getRecordPath(key))
+	if err != nil {
+		return nil, nil, err
+	}
+	return wavData, recordData, nil
+}
+
+// ImportBundle adds the lines of a bundle to the store and references them from a profile: profileID, or the
+// profile the bundle was exported from when it is empty
+func (cacheManager *CacheManager) ImportBundle(reader io.ReaderAt, size int64, profileID string) (*BundleSummary, error) {
+	if !cacheManager.IsEnabled() {
+		return nil, ErrDisabled
+	}
+
+	archive, err := zip.NewReader(reader, size)
+	if err != nil {
+		return nil, fmt.Errorf("not a cache bundle: %w", err)
+	}
+
+	files := map[string]*zip.File{}
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	manifestFile, exists := files[bundleManifest]
+	if !exists {
+		return nil, fmt.Errorf("not a cache bundle: %s is missing", bundleManifest)
+	}
+	manifestData, err := readZipFile(manifestFile)
+	if err != nil {
+		return nil, err
+	}
+	var manifest bundleManifestFile
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", bundleManifest, err)
+	}
+	if manifest.Version != bundleVersion {
+		return nil, fmt.Errorf("unsupported cache bundle version %d", manifest.Version)
+	}
+
+	if profileID == "" {
+		profileID = manifest.Profile
+	}
+	if profileID == "" || strings.ContainsAny(profileID, `/\`) || profileID == "." || profileID == ".." {
+		return nil, errors.New("the bundle doesn't name a valid profile")
+	}
+
+	profileCache, err := cacheManager.loadProfileCache(profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &BundleSummary{Profile: profileID}
+	imported := map[string]*storeRecord{} // nil when the key couldn't be imported
+	now := time.Now()
+
+	cacheManager.storeMutex.Lock()
+	for character, keys := range manifest.Characters {
+		references := 0
+		for _, key := range keys {
+			record, done := imported[key]
+			if !done {
+				record, err = cacheManager.importStored(files, key)
+				if err != nil {
+					response.Warn("Skipping bundled line: %v\n", err)
+				} else {
+					summary.Entries++
+					summary.Bytes += record.Size
+				}
+				imported[key] = record
+			}
+			if record == nil {
+				summary.Skipped++
+				continue
+			}
+
+			profileCache.mutex.Lock()
+			line, _ := profileCache.lookup(character, key)
+			*line = CachedLine{
+				Voice:      record.Voice,
+				SampleRate: record.SampleRate,
+				Channels:   record.Channels,
+				BitDepth:   record.BitDepth,
+				Size:       record.Size,
+				Created:    now,
+				LastAccess: now,
+			}
+			profileCache.mutex.Unlock()
+			references++
+		}
+
+		if references > 0 {
+			summary.Characters++
+			summary.References += references
+		}
+	}
+	cacheManager.storeMutex.Unlock()
+
+	if summary.References > 0 {
+		if err := cacheManager.saveProfileCache(profileID, profileCache); err != nil {
+			return summary, err
+		}
+	}
+	if _, err := cacheManager.Evict(); err != nil {
+		response.Warn("Failed to evict cached audio: %v\n", err)
+	}
+
+	response.LogInfo(fmt.Sprintf("Imported %d cached lines into profile '%s'\n", summary.Entries, profileID))
+	return summary, nil
+}
+
+// importStored checks the bundled audio of key against its record and key and writes it to the store. The store
+// must be locked.
+func (cacheManager *CacheManager) importStored(files map[string]*zip.File, key string) (*storeRecord, error) {
+	if !isKey(key) {
+		return nil, fmt.Errorf("invalid key %q", key)
+	}
+
+	audioFile, hasAudio := files[path.Join(bundleStore, key+".wav")]
+	recordFile, hasRecord := files[path.Join(bundleStore, key+".json")]
+	if !hasAudio || !hasRecord {
+		return nil, fmt.Errorf("%s isn't in the bundle", key)
+	}
+
+	recordData, err := readZipFile(recordFile)
+	if err != nil {
+		return nil, err
+	}
+	var record storeRecord
+	if err := json.Unmarshal(recordData, &record); err != nil {
+		return nil, fmt.Errorf("invalid record of %s: %w", key, err)
+	}
+	if storeKey(record.Voice, record.Parameters, record.Text) != key {
+		return nil, fmt.Errorf("%s holds \"%s\" in %s (%s), which has another key", key, record.Text, record.Voice, record.Parameters)
+	}
+
+	wavData, err := readZipFile(audioFile)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(wavData)) != record.Size || checksum(wavData) != record.Checksum {
+		return nil, fmt.Errorf("%s doesn't match its checksum", key)
+	}
+	if _, err := audio.NewAudioFromWAV(wavData); err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+
+	if err := cacheManager.writeStored(key, &record, wavData); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
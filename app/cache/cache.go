@@ -3,11 +3,13 @@ package cache
 import (
 	"encoding/json"
 	"fmt"
+	"nstudio/app/common/audio"
 	"nstudio/app/common/util"
 	"nstudio/app/tts/profile"
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"nstudio/app/common/response"
 	"nstudio/app/config"
@@ -18,6 +20,10 @@ var (
 	mutex         sync.RWMutex
 )
 
+// accessSaveInterval is how stale the last access time saved for a line may get before a cache hit saves it again.
+// Hits in between only update it in memory, so reading a line doesn't rewrite characters.json every time.
+const accessSaveInterval = 10 * time.Minute
+
 func Initialize() error {
 	mutex.Lock()
 	defer mutex.Unlock()
@@ -55,10 +61,16 @@ func Initialize() error {
 		response.LogInfo(fmt.Sprintf("Cache manager initialized with directory: %s\n", cacheDir))
 	} else {
 		globalManager.enabled = true
+		globalManager.storeMutex.Lock()
 		globalManager.cacheDir = cacheDir
+		globalManager.storeCounted = false
+		globalManager.storeMutex.Unlock()
 		response.LogInfo(fmt.Sprintf("Cache manager updated with directory: %s\n", cacheDir))
 	}
 
+	// The limits may have changed, or been exceeded by a cache that was copied in
+	go globalManager.Evict()
+
 	return nil
 }
 
@@ -76,6 +88,22 @@ func (cacheManager *CacheManager) IsEnabled() bool {
 	return cacheManager.enabled
 }
 
+// IsEnabledFor reports whether audio generated for a profile goes through the cache: the cache is enabled and the
+// profile doesn't turn it off. Profiles without a cacheEnabled setting follow the global one.
+func (cacheManager *CacheManager) IsEnabledFor(profileID string) bool {
+	if !cacheManager.IsEnabled() {
+		return false
+	}
+
+	selectedProfile, err := profile.GetManager().GetProfile(profileID)
+	if err != nil {
+		return true
+	}
+
+	settings := selectedProfile.GetSettings()
+	return settings == nil || settings.CacheEnabled == nil || *settings.CacheEnabled
+}
+
 // <editor-fold desc="Profile Cache">
 func (cacheManager *CacheManager) loadProfileCache(profileID string) (*ProfileCache, error) {
 	cacheManager.mutex.RLock()
@@ -99,11 +127,19 @@ func (cacheManager *CacheManager) loadProfileCache(profileID string) (*ProfileCa
 		if err := json.Unmarshal(data, &cache.Characters); err != nil {
 			return nil, response.Err(fmt.Errorf("Failed to parse cache file: %v", err))
 		}
+		if cacheManager.dropLegacyLines(profileID, cache) {
+			if err := cacheManager.saveProfileCache(profileID, cache); err != nil {
+				response.Warn("Failed to save cache index: %v\n", err)
+			}
+		}
 	}
 
 	cacheManager.mutex.Lock()
+	defer cacheManager.mutex.Unlock()
+	if loaded, exists := cacheManager.profiles[profileID]; exists {
+		return loaded, nil
+	}
 	cacheManager.profiles[profileID] = cache
-	cacheManager.mutex.Unlock()
 	return cache, nil
 }
 
@@ -113,6 +149,10 @@ func (cacheManager *CacheManager) saveProfileCache(profileID string, profileCach
 		return response.Err(err)
 	}
 
+	// Lines are cached from several goroutines, saves go one at a time so the file is never written twice at once
+	profileCache.saveMutex.Lock()
+	defer profileCache.saveMutex.Unlock()
+
 	profileCache.mutex.RLock()
 	data, err := json.MarshalIndent(profileCache.Characters, "", "  ")
 	profileCache.mutex.RUnlock()
@@ -121,19 +161,51 @@ func (cacheManager *CacheManager) saveProfileCache(profileID string, profileCach
 		return response.Err(err)
 	}
 
-	cacheFile := cacheManager.getCharacterCacheFile(profileID)
-	if err := os.WriteFile(cacheFile, data, 0644); err != nil {
-		return response.Err(err)
+	return writeFile(cacheManager.getCharacterCacheFile(profileID), data)
+}
+
+// dropLegacyLines removes the lines cached in the character directories before lines were stored by key. Their
+// index doesn't record their text, so they can't be moved into the store. It reports whether there were any.
+func (cacheManager *CacheManager) dropLegacyLines(profileID string, profileCache *ProfileCache) bool {
+	dropped := false
+	for character, characterCache := range profileCache.Characters {
+		if characterCache.Lines == nil {
+			characterCache.Lines = make(map[string]*CachedLine)
+		}
+
+		for key, line := range characterCache.Lines {
+			if line.legacyFile == "" && isKey(key) {
+				continue
+			}
+
+			if line.legacyFile != "" {
+				os.Remove(filepath.Join(cacheManager.getCharacterAudioDir(profileID, character), line.legacyFile))
+			}
+			delete(characterCache.Lines, key)
+			dropped = true
+		}
+
+		if dropped {
+			os.Remove(cacheManager.getCharacterAudioDir(profileID, character)) // only removed once it's empty
+		}
 	}
 
-	return nil
+	if dropped {
+		response.LogInfo(fmt.Sprintf("Dropped lines of profile '%s' cached in the old layout\n", profileID))
+	}
+	return dropped
 }
 
+// ClearProfileCache removes a profile's index along with the audio no other profile uses
 func (cacheManager *CacheManager) ClearProfileCache(profileID string) error {
 	if !cacheManager.enabled {
 		return nil
 	}
 
+	if _, err := cacheManager.Clear(ClearOptions{Profile: profileID}); err != nil {
+		return err
+	}
+
 	cacheManager.mutex.Lock()
 	delete(cacheManager.profiles, profileID)
 	cacheManager.mutex.Unlock()
@@ -150,142 +222,159 @@ func (cacheManager *CacheManager) getProfileCacheDirectory(profileID string) str
 	return filepath.Join(cacheManager.cacheDir, "profiles", profileID)
 }
 
-func (cacheManager *CacheManager) getProfileAudioDir(profileID string) string {
-	return filepath.Join(cacheManager.getProfileCacheDirectory(profileID), "audio")
-}
-
 //</editor-fold>
 
 // <editor-fold desc="Character Cache">
-func (cacheManager *CacheManager) GetCachedAudio(profileID, character, text string) ([]byte, bool) {
+
+// GetCachedAudio returns the audio cached for a line spoken by voiceKey, in the format it was generated in, and counts
+// the lookup as a hit or a miss in the profile's statistics. Audio another profile or character stored with the same
+// voice and text is found too, and referenced from the profile from then on. Callers pass the voice they generate
+// with on a miss, so the lookup and CacheAudio use the same key.
+func (cacheManager *CacheManager) GetCachedAudio(profileID, character, text, voiceKey string) (*audio.Audio, bool) {
 	profileCache, err := cacheManager.loadProfileCache(profileID)
 	if err != nil {
 		response.Warn("Failed to load profile cache: %v\n", err)
 		return nil, false
 	}
 
-	profileCache.mutex.RLock()
-
-	charCache, exists := profileCache.Characters[character]
-	profileCache.mutex.RUnlock()
-
-	if !exists {
-		return nil, false
-	}
-	profileManager := profile.GetManager()
-	voice, err := profileManager.GetOrAllocateVoice(profileID, character)
-	if err != nil {
-		response.Warn("Failed to get voice: %v\n", err)
-		return nil, false
+	audioObject, found := cacheManager.readCachedAudio(profileID, profileCache, character, text, voiceKey)
+	if found {
+		profileCache.hits.Add(1)
+	} else {
+		profileCache.misses.Add(1)
 	}
+	return audioObject, found
+}
 
-	if charCache.Voice != voice.Key() {
-		response.NewWarn(fmt.Sprintf("Voice mismatch for '%s'. Expected: %s, Got: %s\n", character, voice.Key(), charCache.Voice))
+func (cacheManager *CacheManager) readCachedAudio(profileID string, profileCache *ProfileCache, character, text, voiceKey string) (*audio.Audio, bool) {
+	parameters := Parameters(voiceKey)
+	key := storeKey(voiceKey, parameters, text)
+	audioObject, err := cacheManager.readStored(key, voiceKey, parameters, text)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			response.Warn("Failed to read cached audio: %v\n", err)
+		}
 		return nil, false
 	}
-
-	textHash := util.HashText(text)[:8]
-	filename, exists := charCache.Lines[textHash]
-	if !exists {
+	if len(audioObject.Data) == 0 {
 		return nil, false
 	}
 
-	audioPath := filepath.Join(cacheManager.getCharacterAudioDir(profileID, character), filename)
-	audioData, err := os.ReadFile(audioPath)
+	now := time.Now()
+	cacheManager.storeMutex.Lock()
+	info, err := os.Stat(cacheManager.getStorePath(key))
 	if err != nil {
-		response.Warn("Failed to read audio file: %v\n", err)
+		// Removed from the store since it was read
+		cacheManager.storeMutex.Unlock()
 		return nil, false
 	}
 
-	response.LogInfo(fmt.Sprintf("Get Cached Audio: Successfully loaded %d bytes from cache for '%s'\n", len(audioData), character))
-	return audioData, true
-}
-
-func (cacheManager *CacheManager) CacheAudio(profileID, characterName, text, voiceKey string, rawAudio []byte) error {
-	profileCache, err := cacheManager.loadProfileCache(profileID)
-	if err != nil {
-		return response.Err(err)
-	}
-
 	profileCache.mutex.Lock()
-	characterCache, exists := profileCache.Characters[characterName]
-	if !exists {
-		characterCache = &CharacterCache{
-			Voice: voiceKey,
-			Lines: make(map[string]string),
-		}
-		profileCache.Characters[characterName] = characterCache
-	} else if characterCache.Voice != voiceKey {
-		characterCache.Voice = voiceKey
-		characterCache.Lines = make(map[string]string)
-	}
+	line, referenced := profileCache.lookup(character, key)
+	lastAccess := line.LastAccess
+	if !referenced {
+		line.Voice = voiceKey
+		line.SampleRate = audioObject.Metadata.SampleRate
+		line.Channels = audioObject.Metadata.Channels
+		line.BitDepth = audioObject.Metadata.BitDepth
+		line.Size = info.Size()
+		line.Created = now
+	}
+	line.LastAccess = now
 	profileCache.mutex.Unlock()
+	cacheManager.storeMutex.Unlock()
 
-	textHash := util.HashText(text)[:8]
-	sanitizedFilename := util.SanitizeFilename(text)
-	if sanitizedFilename == "" {
-		sanitizedFilename = "audio"
+	if !referenced || now.Sub(lastAccess) > accessSaveInterval {
+		if err := cacheManager.saveProfileCache(profileID, profileCache); err != nil {
+			response.Warn("Failed to save cache index: %v\n", err)
+		}
 	}
 
-	filename := fmt.Sprintf("%s_%s.wav", sanitizedFilename, textHash)
+	response.LogInfo(fmt.Sprintf("Get Cached Audio: Successfully loaded %d bytes from cache for '%s'\n", len(audioObject.Data), character))
+	return audioObject, true
+}
 
-	audioDir := cacheManager.getCharacterAudioDir(profileID, characterName)
-	if err := os.MkdirAll(audioDir, 0755); err != nil {
+// CacheAudio stores a line's audio along with its exact text, the engine settings it was generated with, its sample
+// rate, channel count and bit depth, and references it from the profile
+func (cacheManager *CacheManager) CacheAudio(profileID, characterName, text, voiceKey string, audioObject *audio.Audio) error {
+	wavData, err := audioObject.ToWAV()
+	if err != nil {
 		return response.Err(err)
 	}
+	metadata := audioObject.Metadata
 
-	audioPath := filepath.Join(audioDir, filename)
-	outputFile, err := os.Create(audioPath)
+	profileCache, err := cacheManager.loadProfileCache(profileID)
 	if err != nil {
-		fmt.Printf("[Cache] CacheAudio: Error creating file: %v\n", err)
 		return response.Err(err)
 	}
-	defer outputFile.Close()
 
-	_, err = outputFile.Write(rawAudio)
-	if err != nil {
-		return response.Err(err)
+	parameters := Parameters(voiceKey)
+	key := storeKey(voiceKey, parameters, text)
+	now := time.Now()
+
+	cacheManager.storeMutex.Lock()
+	record := &storeRecord{
+		Voice:      voiceKey,
+		Parameters: parameters,
+		Text:       text,
+		SampleRate: metadata.SampleRate,
+		Channels:   metadata.Channels,
+		BitDepth:   metadata.BitDepth,
+	}
+	if err := cacheManager.writeStored(key, record, wavData); err != nil {
+		cacheManager.storeMutex.Unlock()
+		return err
 	}
 
 	profileCache.mutex.Lock()
-	characterCache.Lines[textHash] = filename
+	line, _ := profileCache.lookup(characterName, key)
+	*line = CachedLine{
+		Voice:      voiceKey,
+		SampleRate: metadata.SampleRate,
+		Channels:   metadata.Channels,
+		BitDepth:   metadata.BitDepth,
+		Size:       int64(len(wavData)),
+		Created:    now,
+		LastAccess: now,
+	}
 	profileCache.mutex.Unlock()
+	cacheManager.storeMutex.Unlock()
 
 	if err := cacheManager.saveProfileCache(profileID, profileCache); err != nil {
 		return response.Alert("CacheAudio: Failed to save cache metadata: %v\n", err)
 	}
 
+	cacheManager.scheduleEvict()
 	return nil
 }
 
+// ClearCharacterCache removes a character's references along with the audio no other profile or character uses
 func (cacheManager *CacheManager) ClearCharacterCache(profileID, character string) error {
 	if !cacheManager.enabled {
 		return nil
 	}
 
-	profileCache, err := cacheManager.loadProfileCache(profileID)
-	if err != nil {
-		return response.Err(err)
-	}
+	_, err := cacheManager.Clear(ClearOptions{Profile: profileID, Character: character})
+	return err
+}
 
-	profileCache.mutex.Lock()
-	_, characterIsCached := profileCache.Characters[character]
-	if !characterIsCached {
-		profileCache.mutex.Unlock()
-		return nil
+// lookup returns the reference of a character to key, adding an empty one when there is none. The profile cache
+// must be locked.
+func (profileCache *ProfileCache) lookup(character, key string) (*CachedLine, bool) {
+	characterCache, exists := profileCache.Characters[character]
+	if !exists {
+		characterCache = &CharacterCache{
+			Lines: make(map[string]*CachedLine),
+		}
+		profileCache.Characters[character] = characterCache
 	}
-	profileCache.mutex.Unlock()
 
-	characterDir := cacheManager.getCharacterAudioDir(profileID, character)
-	if err := os.RemoveAll(characterDir); err != nil && !os.IsNotExist(err) {
-		response.Warn("failed to remove character directory: %v", err)
+	line, exists := characterCache.Lines[key]
+	if !exists {
+		line = &CachedLine{}
+		characterCache.Lines[key] = line
 	}
-
-	profileCache.mutex.Lock()
-	delete(profileCache.Characters, character)
-	profileCache.mutex.Unlock()
-
-	return cacheManager.saveProfileCache(profileID, profileCache)
+	return line, exists
 }
 
 func (cacheManager *CacheManager) getCharacterAudioDir(profileID, character string) string {
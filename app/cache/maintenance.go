@@ -0,0 +1,347 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"nstudio/app/common/response"
+	"nstudio/app/config"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrDisabled = errors.New("the audio cache is disabled")
+
+const (
+	// sizeEvictInterval is how often writes may start an eviction for the maximum size, so writes arriving while
+	// one runs don't each scan the cache again
+	sizeEvictInterval = time.Minute
+
+	// ageEvictInterval is how often writes apply the maximum age, which is counted in days
+	ageEvictInterval = time.Hour
+)
+
+// Removed counts the references taken out of the profiles' indexes and the bytes of audio that were removed from
+// the store because no profile used them anymore
+type Removed struct {
+	Entries int   `json:"entries"`
+	Bytes   int64 `json:"bytes"`
+}
+
+// ClearOptions select the lines Clear removes, empty fields match every line
+type ClearOptions struct {
+	Profile   string
+	Character string
+	OlderThan time.Duration // lines cached longer ago than this
+}
+
+// Statistics describe the whole cache. Entries and Bytes count the audio in the store once, however many
+// profiles use it.
+type Statistics struct {
+	Enabled    bool                 `json:"enabled"`
+	MaxSizeMB  int                  `json:"maxSizeMB"`
+	MaxAgeDays int                  `json:"maxAgeDays"`
+	Entries    int                  `json:"entries"`
+	Bytes      int64                `json:"bytes"`
+	References int                  `json:"references"`
+	Hits       int64                `json:"hits"`
+	Misses     int64                `json:"misses"`
+	HitRatio   float64              `json:"hitRatio"`
+	Profiles   []*ProfileStatistics `json:"profiles"`
+}
+
+// ProfileStatistics describe the lines one profile references, including audio it shares with other profiles.
+// Hits and misses are counted since the profile's cache was loaded.
+type ProfileStatistics struct {
+	Profile    string  `json:"profile"`
+	Characters int     `json:"characters"`
+	Entries    int     `json:"entries"`
+	Bytes      int64   `json:"bytes"`
+	Hits       int64   `json:"hits"`
+	Misses     int64   `json:"misses"`
+	HitRatio   float64 `json:"hitRatio"`
+}
+
+// Stats returns the statistics of a profile's cache, or of every profile when profileID is empty
+func (cacheManager *CacheManager) Stats(profileID string) (*Statistics, error) {
+	settings := config.GetSettings().AudioCache
+	statistics := &Statistics{
+		Enabled:    cacheManager.IsEnabled(),
+		MaxSizeMB:  settings.MaxSizeMB,
+		MaxAgeDays: settings.MaxAgeDays,
+		Profiles:   []*ProfileStatistics{},
+	}
+	if !statistics.Enabled {
+		return statistics, nil
+	}
+
+	profiles, err := cacheManager.selectProfiles(profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := map[string]int64{}
+	for id, profileCache := range profiles {
+		profileStatistics := &ProfileStatistics{
+			Profile: id,
+			Hits:    profileCache.hits.Load(),
+			Misses:  profileCache.misses.Load(),
+		}
+
+		profileCache.mutex.RLock()
+		for _, characterCache := range profileCache.Characters {
+			if len(characterCache.Lines) > 0 {
+				profileStatistics.Characters++
+			}
+			for key, line := range characterCache.Lines {
+				profileStatistics.Entries++
+				profileStatistics.Bytes += line.Size
+				stored[key] = line.Size
+			}
+		}
+		profileCache.mutex.RUnlock()
+
+		profileStatistics.HitRatio = hitRatio(profileStatistics.Hits, profileStatistics.Misses)
+		statistics.References += profileStatistics.Entries
+		statistics.Hits += profileStatistics.Hits
+		statistics.Misses += profileStatistics.Misses
+		statistics.Profiles = append(statistics.Profiles, profileStatistics)
+	}
+
+	statistics.Entries = len(stored)
+	for _, size := range stored {
+		statistics.Bytes += size
+	}
+	statistics.HitRatio = hitRatio(statistics.Hits, statistics.Misses)
+	slices.SortFunc(statistics.Profiles, func(first, second *ProfileStatistics) int {
+		return strings.Compare(first.Profile, second.Profile)
+	})
+	return statistics, nil
+}
+
+// Clear removes the cached lines matching options
+func (cacheManager *CacheManager) Clear(options ClearOptions) (Removed, error) {
+	if !cacheManager.IsEnabled() {
+		return Removed{}, ErrDisabled
+	}
+
+	cacheManager.evictMutex.Lock()
+	defer cacheManager.evictMutex.Unlock()
+
+	profiles, err := cacheManager.selectProfiles(options.Profile)
+	if err != nil {
+		return Removed{}, err
+	}
+
+	cutoff := time.Now().Add(-options.OlderThan)
+	return cacheManager.removeLines(profiles, func(character, _ string, line *CachedLine) bool {
+		return (options.Character == "" || character == options.Character) &&
+			(options.OlderThan <= 0 || line.Created.Before(cutoff))
+	})
+}
+
+// Evict applies the maximum age and size of the cache settings: audio no profile references is removed, then lines
+// older than the maximum age, then the least recently used audio until the store fits in the maximum size.
+func (cacheManager *CacheManager) Evict() (Removed, error) {
+	settings := config.GetSettings().AudioCache
+	if !cacheManager.IsEnabled() || settings.MaxSizeMB <= 0 && settings.MaxAgeDays <= 0 {
+		return Removed{}, nil
+	}
+
+	cacheManager.evictMutex.Lock()
+	defer cacheManager.evictMutex.Unlock()
+	defer cacheManager.lastEvict.Store(time.Now().UnixNano())
+
+	profiles, err := cacheManager.selectProfiles("")
+	if err != nil {
+		return Removed{}, err
+	}
+
+	// Unreferenced audio counts towards the size of the store but isn't in any index, so it goes first
+	var removed Removed
+	removed.Bytes, err = cacheManager.pruneUnreferenced()
+	if err != nil {
+		return removed, err
+	}
+
+	if settings.MaxAgeDays > 0 {
+		cutoff := time.Now().AddDate(0, 0, -settings.MaxAgeDays)
+		aged, err := cacheManager.removeLines(profiles, func(_, _ string, line *CachedLine) bool {
+			return line.Created.Before(cutoff)
+		})
+		removed.Entries += aged.Entries
+		removed.Bytes += aged.Bytes
+		if err != nil {
+			return removed, err
+		}
+	}
+
+	if settings.MaxSizeMB > 0 {
+		// Audio shared by several profiles is as recent as its latest use
+		type storedAudio struct {
+			key        string
+			size       int64
+			lastAccess time.Time
+		}
+
+		stored := map[string]*storedAudio{}
+		for _, profileCache := range profiles {
+			profileCache.mutex.RLock()
+			for _, characterCache := range profileCache.Characters {
+				for key, line := range characterCache.Lines {
+					entry, exists := stored[key]
+					if !exists {
+						entry = &storedAudio{key: key}
+						stored[key] = entry
+					}
+					entry.size = max(entry.size, line.Size)
+					if line.LastAccess.After(entry.lastAccess) {
+						entry.lastAccess = line.LastAccess
+					}
+				}
+			}
+			profileCache.mutex.RUnlock()
+		}
+
+		var total int64
+		candidates := make([]*storedAudio, 0, len(stored))
+		for _, entry := range stored {
+			candidates = append(candidates, entry)
+			total += entry.size
+		}
+
+		maxBytes := int64(settings.MaxSizeMB) << 20
+		if total > maxBytes {
+			slices.SortFunc(candidates, func(first, second *storedAudio) int {
+				return first.lastAccess.Compare(second.lastAccess)
+			})
+
+			evicted := map[string]bool{}
+			for _, entry := range candidates {
+				if total <= maxBytes {
+					break
+				}
+				evicted[entry.key] = true
+				total -= entry.size
+			}
+
+			sizeRemoved, err := cacheManager.removeLines(profiles, func(_, key string, _ *CachedLine) bool {
+				return evicted[key]
+			})
+			removed.Entries += sizeRemoved.Entries
+			removed.Bytes += sizeRemoved.Bytes
+			if err != nil {
+				return removed, err
+			}
+		}
+	}
+
+	if removed.Entries > 0 || removed.Bytes > 0 {
+		response.LogInfo(fmt.Sprintf("Evicted %d cached lines, freeing %d bytes\n", removed.Entries, removed.Bytes))
+	}
+	return removed, nil
+}
+
+// scheduleEvict starts Evict in the background once the store grew past the maximum size, or when the maximum age
+// wasn't applied for ageEvictInterval. Writes call it after every line, it only compares the size the store keeps
+// track of until then.
+func (cacheManager *CacheManager) scheduleEvict() {
+	settings := config.GetSettings().AudioCache
+	sinceEvict := time.Since(time.Unix(0, cacheManager.lastEvict.Load()))
+
+	cacheManager.storeMutex.Lock()
+	oversized := settings.MaxSizeMB > 0 && cacheManager.storeBytes > int64(settings.MaxSizeMB)<<20
+	cacheManager.storeMutex.Unlock()
+
+	due := (oversized && sinceEvict >= sizeEvictInterval) || (settings.MaxAgeDays > 0 && sinceEvict >= ageEvictInterval)
+	if !due || !cacheManager.evicting.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer cacheManager.evicting.Store(false)
+		if _, err := cacheManager.Evict(); err != nil {
+			response.Warn("Failed to evict cached audio: %v\n", err)
+		}
+	}()
+}
+
+// removeLines takes the lines matching match out of the profiles' indexes, then removes the audio no profile
+// references anymore from the store
+func (cacheManager *CacheManager) removeLines(profiles map[string]*ProfileCache, match func(character, key string, line *CachedLine) bool) (Removed, error) {
+	var removed Removed
+	keys := map[string]bool{}
+
+	for id, profileCache := range profiles {
+		count := 0
+		profileCache.mutex.Lock()
+		for character, characterCache := range profileCache.Characters {
+			for key, line := range characterCache.Lines {
+				if !match(character, key, line) {
+					continue
+				}
+				delete(characterCache.Lines, key)
+				keys[key] = true
+				count++
+			}
+		}
+		profileCache.mutex.Unlock()
+
+		if count == 0 {
+			continue
+		}
+		removed.Entries += count
+		if err := cacheManager.saveProfileCache(id, profileCache); err != nil {
+			return removed, err
+		}
+	}
+
+	freed, err := cacheManager.pruneStore(keys)
+	removed.Bytes = freed
+	return removed, err
+}
+
+// selectProfiles loads the cache of a profile, or of every profile with a cache directory when profileID is empty
+func (cacheManager *CacheManager) selectProfiles(profileID string) (map[string]*ProfileCache, error) {
+	var ids []string
+	if profileID != "" {
+		ids = []string{profileID}
+	} else {
+		entries, err := os.ReadDir(filepath.Join(cacheManager.cacheDir, "profiles"))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, response.Err(err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				ids = append(ids, entry.Name())
+			}
+		}
+
+		cacheManager.mutex.RLock()
+		for id := range cacheManager.profiles {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+		cacheManager.mutex.RUnlock()
+	}
+
+	profiles := make(map[string]*ProfileCache, len(ids))
+	for _, id := range ids {
+		profileCache, err := cacheManager.loadProfileCache(id)
+		if err != nil {
+			return nil, err
+		}
+		profiles[id] = profileCache
+	}
+	return profiles, nil
+}
+
+func hitRatio(hits, misses int64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
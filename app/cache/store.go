@@ -0,0 +1,339 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"nstudio/app/common/audio"
+	"nstudio/app/common/response"
+	"nstudio/app/common/util"
+	"nstudio/app/config"
+	"nstudio/app/enums/Engines"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// The store keeps every cached line once, as a WAV file named after its key and a record of what it holds:
+//
+//	store/<first two characters of the key>/<key>.wav
+//	store/<first two characters of the key>/<key>.json
+//
+// The key only depends on the voice, the engine settings it is synthesized with and the text, so profiles that give
+// characters the same voice share the audio, and a renamed character still finds its lines. Profiles reference the
+// audio they use in their characters.json, audio that no profile references anymore is removed.
+
+// storeRecord is written next to the audio of a line. Reads compare the voice, parameters and text with the ones
+// asked for, so a key collision can't return the wrong line, and the checksum with the audio, so a damaged file
+// isn't played.
+type storeRecord struct {
+	Voice      string `json:"voice"`
+	Parameters string `json:"parameters"` // Parameters of the voice when it was generated
+	Text       string `json:"text"`       // exactly as it was generated
+	SampleRate int    `json:"sampleRate"`
+	Channels   int    `json:"channels"`
+	BitDepth   int    `json:"bitDepth"`
+	Size       int64  `json:"size"`
+	Checksum   string `json:"checksum"` // SHA-256 of the WAV file
+}
+
+// matches reports whether the record holds the line of voiceKey and exactly text, generated with parameters. Texts
+// that only differ in whitespace share a key, the store holds the one cached last and the other one misses.
+func (record *storeRecord) matches(voiceKey, parameters, text string) bool {
+	return record.Voice == voiceKey && record.Parameters == parameters && record.Text == text
+}
+
+// Key returns the store key of a line spoken by voiceKey with the current engine settings
+func Key(voiceKey, text string) string {
+	return storeKey(voiceKey, Parameters(voiceKey), text)
+}
+
+// storeKey is the SHA-256 of a line's voice key, which names the engine, model and voice it is synthesized with,
+// its parameters and its text with runs of whitespace collapsed
+func storeKey(voiceKey, parameters, text string) string {
+	hash := sha256.New()
+	hash.Write([]byte(voiceKey))
+	hash.Write([]byte{0})
+	hash.Write([]byte(parameters))
+	hash.Write([]byte{0})
+	hash.Write([]byte(util.NormalizeText(text)))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Parameters returns the engine settings that change how the voice of voiceKey sounds, encoded as a sorted query
+// string, so the same settings always give the same key. The speaking rate isn't one of them: lines are cached at
+// the normal rate.
+func Parameters(voiceKey string) string {
+	engine, _, _ := strings.Cut(voiceKey, ":")
+	values := url.Values{}
+
+	switch engine {
+	case string(Engines.MsSapi4):
+		settings := config.GetEngine().Local.MsSapi4
+		values.Set("pitch", strconv.Itoa(settings.Pitch))
+		values.Set("speed", strconv.Itoa(settings.Speed))
+	case string(Engines.MsSapi5):
+		settings := config.GetEngine().Local.MsSapi5
+		values.Set("rate", strconv.Itoa(settings.Rate))
+		values.Set("volume", strconv.Itoa(settings.Volume))
+	}
+	return values.Encode()
+}
+
+// isKey reports whether key can be a store key, so a damaged index can't point outside the store
+func isKey(key string) bool {
+	if len(key) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(key)
+	return err == nil
+}
+
+func (cacheManager *CacheManager) getStoreDirectory() string {
+	return filepath.Join(cacheManager.cacheDir, "store")
+}
+
+func (cacheManager *CacheManager) getStorePath(key string) string {
+	return filepath.Join(cacheManager.getStoreDirectory(), key[:2], key+".wav")
+}
+
+func (cacheManager *CacheManager) getRecordPath(key string) string {
+	return filepath.Join(cacheManager.getStoreDirectory(), key[:2], key+".json")
+}
+
+// readStored returns the audio stored under key as PCM, once its record matches voiceKey, parameters and text and its
+// checksum matches the file. Audio that isn't stored returns an os.ErrNotExist error.
+func (cacheManager *CacheManager) readStored(key, voiceKey, parameters, text string) (*audio.Audio, error) {
+	record, err := cacheManager.readRecord(key)
+	if err != nil {
+		return nil, err
+	}
+	if !record.matches(voiceKey, parameters, text) {
+		return nil, fmt.Errorf("%s holds \"%s\" in %s, not the line asked for", key, record.Text, record.Voice)
+	}
+
+	wavData, err := os.ReadFile(cacheManager.getStorePath(key))
+	if err != nil {
+		return nil, err
+	}
+	if checksum(wavData) != record.Checksum {
+		return nil, fmt.Errorf("%s doesn't match its checksum", key)
+	}
+
+	stored, err := audio.NewAudioFromWAV(wavData)
+	if err != nil {
+		return nil, err
+	}
+	pcmData, err := stored.ToPCM()
+	if err != nil {
+		return nil, err
+	}
+	return audio.NewAudioFromPCM(pcmData, stored.Metadata.SampleRate, stored.Metadata.Channels, stored.Metadata.BitDepth), nil
+}
+
+func (cacheManager *CacheManager) readRecord(key string) (*storeRecord, error) {
+	data, err := os.ReadFile(cacheManager.getRecordPath(key))
+	if err != nil {
+		return nil, err
+	}
+
+	var record storeRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("invalid record of %s: %w", key, err)
+	}
+	return &record, nil
+}
+
+// writeStored writes a line's audio and then its record to the store, each through a temporary file. The store
+// must be locked.
+func (cacheManager *CacheManager) writeStored(key string, record *storeRecord, wavData []byte) error {
+	cacheManager.countStore()
+
+	path := cacheManager.getStorePath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return response.Err(err)
+	}
+	var replaced int64
+	if info, err := os.Stat(path); err == nil {
+		replaced = info.Size()
+	}
+
+	record.Size = int64(len(wavData))
+	record.Checksum = checksum(wavData)
+	recordData, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return response.Err(err)
+	}
+
+	if err := writeFile(path, wavData); err != nil {
+		return err
+	}
+	cacheManager.storeBytes += record.Size - replaced
+	return writeFile(cacheManager.getRecordPath(key), recordData)
+}
+
+// removeStored removes the audio of key and its record, returning the size of the audio. The store must be locked.
+func (cacheManager *CacheManager) removeStored(key string) (int64, error) {
+	cacheManager.countStore()
+
+	path := cacheManager.getStorePath(key)
+	var size int64
+	if info, err := os.Stat(path); err == nil {
+		size = info.Size()
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+	cacheManager.storeBytes -= size
+	if err := os.Remove(cacheManager.getRecordPath(key)); err != nil && !os.IsNotExist(err) {
+		return size, err
+	}
+	os.Remove(filepath.Dir(path)) // only removed once it's empty
+	return size, nil
+}
+
+// pruneUnreferenced removes the audio no profile references, like audio left behind by an interrupted removal, and
+// sets storeBytes to the size of the audio that stays. It returns how many bytes it freed.
+func (cacheManager *CacheManager) pruneUnreferenced() (int64, error) {
+	cacheManager.storeMutex.Lock()
+	defer cacheManager.storeMutex.Unlock()
+
+	profiles, err := cacheManager.selectProfiles("")
+	if err != nil {
+		return 0, err
+	}
+
+	referenced := map[string]bool{}
+	for _, profileCache := range profiles {
+		profileCache.mutex.RLock()
+		for _, characterCache := range profileCache.Characters {
+			for key := range characterCache.Lines {
+				referenced[key] = true
+			}
+		}
+		profileCache.mutex.RUnlock()
+	}
+
+	var total int64
+	var unreferenced []string
+	err = filepath.WalkDir(cacheManager.getStoreDirectory(), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(path) != ".wav" {
+			return nil
+		}
+
+		key := strings.TrimSuffix(entry.Name(), ".wav")
+		if isKey(key) && !referenced[key] {
+			unreferenced = append(unreferenced, key)
+			return nil
+		}
+		if info, err := entry.Info(); err == nil {
+			total += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, response.Err(err)
+	}
+
+	var freed int64
+	for _, key := range unreferenced {
+		size, err := cacheManager.removeStored(key)
+		if err != nil {
+			response.Warn("Failed to remove cached audio: %v\n", err)
+		}
+		freed += size
+	}
+
+	cacheManager.storeBytes = total
+	cacheManager.storeCounted = true
+	return freed, nil
+}
+
+// countStore adds up the size of the audio in the store the first time it's needed, writes and removals keep it
+// up to date from then on. The store must be locked.
+func (cacheManager *CacheManager) countStore() {
+	if cacheManager.storeCounted {
+		return
+	}
+
+	var total int64
+	filepath.WalkDir(cacheManager.getStoreDirectory(), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || filepath.Ext(path) != ".wav" {
+			return nil
+		}
+		if info, err := entry.Info(); err == nil {
+			total += info.Size()
+		}
+		return nil
+	})
+
+	cacheManager.storeBytes = total
+	cacheManager.storeCounted = true
+}
+
+func checksum(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func writeFile(path string, data []byte) error {
+	temporaryFile := path + ".tmp"
+	if err := os.WriteFile(temporaryFile, data, 0644); err != nil {
+		return response.Err(err)
+	}
+	if err := os.Rename(temporaryFile, path); err != nil {
+		os.Remove(temporaryFile)
+		return response.Err(err)
+	}
+	return nil
+}
+
+// pruneStore removes the audio of keys that no profile references anymore and returns how many bytes it freed
+func (cacheManager *CacheManager) pruneStore(keys map[string]bool) (int64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	cacheManager.storeMutex.Lock()
+	defer cacheManager.storeMutex.Unlock()
+
+	profiles, err := cacheManager.selectProfiles("")
+	if err != nil {
+		return 0, err
+	}
+
+	unreferenced := make(map[string]bool, len(keys))
+	for key := range keys {
+		unreferenced[key] = true
+	}
+	for _, profileCache := range profiles {
+		profileCache.mutex.RLock()
+		for _, characterCache := range profileCache.Characters {
+			for key := range characterCache.Lines {
+				delete(unreferenced, key)
+			}
+		}
+		profileCache.mutex.RUnlock()
+	}
+
+	var freed int64
+	for key := range unreferenced {
+		size, err := cacheManager.removeStored(key)
+		if err != nil {
+			response.Warn("Failed to remove cached audio: %v\n", err)
+		}
+		freed += size
+	}
+	return freed, nil
+}
@@ -1,15 +1,63 @@
 package cache
 
-import "sync"
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
+// CharacterCache lists the lines of a character that are in the store, by their store key
 type CharacterCache struct {
-	Voice string            `json:"voice"` // Format: "engine:model:voiceID"
-	Lines map[string]string `json:"lines"`
+	Lines map[string]*CachedLine `json:"lines"`
+}
+
+// CachedLine is a profile's reference to audio in the store. Size, Created and LastAccess drive eviction: lines
+// older than the maximum age go first, then the least recently used audio.
+type CachedLine struct {
+	Voice      string    `json:"voice"` // Format: "engine:model:voiceID"
+	SampleRate int       `json:"sampleRate"`
+	Channels   int       `json:"channels"`
+	BitDepth   int       `json:"bitDepth"`
+	Size       int64     `json:"size"`
+	Created    time.Time `json:"created"`
+	LastAccess time.Time `json:"lastAccess"`
+
+	// legacyFile is the audio file of a line cached in the character's directory, before lines were stored by key
+	legacyFile string
+}
+
+// UnmarshalJSON also reads the lines of the older per-character layout, a file name or an object with a "file",
+// and keeps their file in legacyFile
+func (line *CachedLine) UnmarshalJSON(data []byte) error {
+	var file string
+	if err := json.Unmarshal(data, &file); err == nil {
+		*line = CachedLine{legacyFile: file}
+		return nil
+	}
+
+	type Alias CachedLine
+	target := &struct {
+		*Alias
+		File string `json:"file"`
+	}{
+		Alias: (*Alias)(line),
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return err
+	}
+	line.legacyFile = target.File
+	return nil
 }
 
 type ProfileCache struct {
 	Characters map[string]*CharacterCache `json:"characters"`
 	mutex      sync.RWMutex
+	saveMutex  sync.Mutex
+
+	// Lookups since the profile's cache was loaded
+	hits   atomic.Int64
+	misses atomic.Int64
 }
 
 type CacheManager struct {
@@ -17,4 +65,20 @@ type CacheManager struct {
 	cacheDir string
 	profiles map[string]*ProfileCache
 	mutex    sync.RWMutex
+
+	// evictMutex keeps eviction and clearing from running twice at once
+	evictMutex sync.Mutex
+
+	// storeMutex is held while audio is written to or removed from the store, so audio isn't removed while a
+	// reference to it is being added
+	storeMutex sync.Mutex
+
+	// storeBytes is the size of the audio in the store, kept up to date by writes and removals once storeCounted.
+	// Both are guarded by storeMutex.
+	storeBytes   int64
+	storeCounted bool
+
+	// evicting is set while a background eviction runs, lastEvict is when the last eviction ran in UnixNano
+	evicting  atomic.Bool
+	lastEvict atomic.Int64
 }
@@ -0,0 +1,217 @@
+package cache
+
+import (
+	"fmt"
+	"nstudio/app/common/audio"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+type ProblemKind string
+
+const (
+	// Orphaned files aren't used by the cache: audio no profile references, leftover temporary files and files
+	// of older cache layouts
+	Orphaned ProblemKind = "orphaned"
+	// Missing audio is referenced by a profile but isn't in the store
+	Missing ProblemKind = "missing"
+	// Corrupt audio doesn't match its record or checksum, or isn't a valid WAV file
+	Corrupt ProblemKind = "corrupt"
+)
+
+type Problem struct {
+	Kind      ProblemKind `json:"kind"`
+	Path      string      `json:"path,omitempty"`
+	Key       string      `json:"key,omitempty"`
+	Profile   string      `json:"profile,omitempty"`
+	Character string      `json:"character,omitempty"`
+	Detail    string      `json:"detail"`
+}
+
+type VerifyReport struct {
+	Checked    int       `json:"checked"` // audio files in the store
+	References int       `json:"references"`
+	Problems   []Problem `json:"problems"`
+	Repaired   bool      `json:"repaired"`
+}
+
+// Verify checks every file of the cache against the profiles' indexes. With repair, orphaned and corrupt files are
+// removed along with the references to missing and corrupt audio.
+func (cacheManager *CacheManager) Verify(repair bool) (*VerifyReport, error) {
+	if !cacheManager.IsEnabled() {
+		return nil, ErrDisabled
+	}
+
+	cacheManager.evictMutex.Lock()
+	defer cacheManager.evictMutex.Unlock()
+	cacheManager.storeMutex.Lock()
+	defer cacheManager.storeMutex.Unlock()
+
+	report := &VerifyReport{Problems: []Problem{}, Repaired: repair}
+	var orphans []string
+
+	// <editor-fold desc="Store">
+	storeDirectory := cacheManager.getStoreDirectory()
+	stored := map[string]bool{} // false when the audio is corrupt
+	audioFiles := map[string]bool{}
+	recordFiles := map[string]bool{}
+
+	err := filepath.WalkDir(storeDirectory, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == storeDirectory {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		name := entry.Name()
+		key := strings.TrimSuffix(strings.TrimSuffix(name, ".wav"), ".json")
+		if !isKey(key) || filepath.Base(filepath.Dir(path)) != key[:2] {
+			orphans = append(orphans, path)
+			report.Problems = append(report.Problems, Problem{Kind: Orphaned, Path: path, Detail: "not a file of the store"})
+			return nil
+		}
+
+		if strings.HasSuffix(name, ".wav") {
+			audioFiles[key] = true
+		} else {
+			recordFiles[key] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for key := range audioFiles {
+		report.Checked++
+		if detail := cacheManager.checkStored(key, recordFiles[key]); detail != "" {
+			stored[key] = false
+			report.Problems = append(report.Problems, Problem{Kind: Corrupt, Path: cacheManager.getStorePath(key), Key: key, Detail: detail})
+			continue
+		}
+		stored[key] = true
+	}
+	for key := range recordFiles {
+		if !audioFiles[key] {
+			orphans = append(orphans, cacheManager.getRecordPath(key))
+			report.Problems = append(report.Problems, Problem{Kind: Orphaned, Path: cacheManager.getRecordPath(key), Key: key, Detail: "record without audio"})
+		}
+	}
+	//</editor-fold>
+
+	// <editor-fold desc="Indexes">
+	profiles, err := cacheManager.selectProfiles("")
+	if err != nil {
+		return nil, err
+	}
+
+	referenced := map[string]bool{}
+	for id, profileCache := range profiles {
+		dropped := false
+
+		profileCache.mutex.Lock()
+		for character, characterCache := range profileCache.Characters {
+			for key := range characterCache.Lines {
+				report.References++
+				valid, exists := stored[key]
+				switch {
+				case !exists:
+					report.Problems = append(report.Problems, Problem{Kind: Missing, Key: key, Profile: id, Character: character, Detail: "referenced audio isn't in the store"})
+				case !valid:
+					report.Problems = append(report.Problems, Problem{Kind: Corrupt, Key: key, Profile: id, Character: character, Detail: "references corrupt audio"})
+				default:
+					referenced[key] = true
+					continue
+				}
+
+				if repair {
+					delete(characterCache.Lines, key)
+					dropped = true
+				}
+			}
+		}
+		profileCache.mutex.Unlock()
+
+		if dropped {
+			if err := cacheManager.saveProfileCache(id, profileCache); err != nil {
+				return nil, err
+			}
+		}
+
+		entries, err := os.ReadDir(cacheManager.getProfileCacheDirectory(id))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.Name() == filepath.Base(cacheManager.getCharacterCacheFile(id)) {
+				continue
+			}
+			path := filepath.Join(cacheManager.getProfileCacheDirectory(id), entry.Name())
+			orphans = append(orphans, path)
+			report.Problems = append(report.Problems, Problem{Kind: Orphaned, Path: path, Profile: id, Detail: "not a file of the profile's index"})
+		}
+	}
+
+	for key, valid := range stored {
+		if valid && !referenced[key] {
+			report.Problems = append(report.Problems, Problem{Kind: Orphaned, Path: cacheManager.getStorePath(key), Key: key, Detail: "audio no profile references"})
+		}
+	}
+	//</editor-fold>
+
+	slices.SortStableFunc(report.Problems, func(first, second Problem) int {
+		return strings.Compare(string(first.Kind), string(second.Kind))
+	})
+
+	if !repair {
+		return report, nil
+	}
+
+	for key, valid := range stored {
+		if !valid || !referenced[key] {
+			if _, err := cacheManager.removeStored(key); err != nil {
+				return report, err
+			}
+		}
+	}
+	for _, path := range orphans {
+		if err := os.RemoveAll(path); err != nil {
+			return report, err
+		}
+		os.Remove(filepath.Dir(path)) // only removed once it's empty
+	}
+	return report, nil
+}
+
+// checkStored checks the audio of key against its record, returning what is wrong with it or an empty string
+func (cacheManager *CacheManager) checkStored(key string, hasRecord bool) string {
+	if !hasRecord {
+		return "audio without a record"
+	}
+
+	record, err := cacheManager.readRecord(key)
+	if err != nil {
+		return err.Error()
+	}
+	if storeKey(record.Voice, record.Parameters, record.Text) != key {
+		return fmt.Sprintf("the record holds \"%s\" in %s (%s), which has another key", record.Text, record.Voice, record.Parameters)
+	}
+
+	wavData, err := os.ReadFile(cacheManager.getStorePath(key))
+	if err != nil {
+		return err.Error()
+	}
+	if int64(len(wavData)) != record.Size || checksum(wavData) != record.Checksum {
+		return "the audio doesn't match its checksum"
+	}
+	if _, err := audio.NewAudioFromWAV(wavData); err != nil {
+		return err.Error()
+	}
+	return ""
+}
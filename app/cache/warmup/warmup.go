@@ -0,0 +1,366 @@
+// Package warmup fills the audio cache with lines that are known ahead of time, like every line of a game, so
+// they play from the cache later on.
+//
+// A warm-up takes a script or a list of character and text pairs for a profile and generates every line that
+// isn't cached yet, one at a time. Lines with directives are skipped: they are rendered in pieces that don't go
+// through the cache. Warm-ups run in the background, Get reports their progress until the manager forgets them.
+package warmup
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"nstudio/app/cache"
+	"nstudio/app/common/eventManager"
+	"nstudio/app/common/response"
+	"nstudio/app/common/script"
+	"nstudio/app/tts"
+	"nstudio/app/tts/profile"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProgressEvent is emitted with a copy of the Warmup after every line
+const ProgressEvent = "cache.warmup.progress"
+
+// finishedKept is how many finished warm-ups the manager keeps for Get
+const finishedKept = 32
+
+type State string
+
+const (
+	Running   State = "running"
+	Completed State = "completed"
+	Failed    State = "failed"
+	Cancelled State = "cancelled"
+)
+
+// Line is a character's line to warm
+type Line struct {
+	Character string `json:"character"`
+	Text      string `json:"text"`
+}
+
+// Request is what to warm: the lines of Script, or Lines when there is no script
+type Request struct {
+	Profile string `json:"profile"`
+	Script  string `json:"script,omitempty"`
+	Lines   []Line `json:"lines,omitempty"`
+}
+
+// LineError is a line that failed to generate. The warm-up carries on with the next one.
+type LineError struct {
+	Index     int    `json:"index"`
+	Character string `json:"character"`
+	Error     string `json:"error"`
+}
+
+type Warmup struct {
+	ID        string      `json:"id"`
+	Profile   string      `json:"profile"`
+	State     State       `json:"state"`
+	Error     string      `json:"error,omitempty"`
+	Started   time.Time   `json:"started"`
+	Finished  *time.Time  `json:"finished,omitempty"`
+	Done      int         `json:"done"`
+	Total     int         `json:"total"`
+	Cached    int         `json:"cached"`    // lines that were cached already
+	Generated int         `json:"generated"` // lines generated and cached
+	Skipped   int         `json:"skipped"`   // script entries with directives
+	Errors    []LineError `json:"errors,omitempty"`
+
+	// keys are the store keys of the lines in the cache, for ExportBundle
+	keys []string
+}
+
+// Active reports whether the warm-up is still running
+func (warmup *Warmup) Active() bool {
+	return warmup.State == Running
+}
+
+func (warmup *Warmup) copy() *Warmup {
+	copied := *warmup
+	copied.Errors = slices.Clone(warmup.Errors)
+	copied.keys = nil
+	return &copied
+}
+
+// ParseRequest returns the lines of a request, along with the number of script entries that were skipped
+func ParseRequest(request Request) ([]Line, int, error) {
+	if request.Profile == "" {
+		return nil, 0, errors.New("a profile is required")
+	}
+	if _, err := profile.GetManager().GetProfile(request.Profile); err != nil {
+		return nil, 0, err
+	}
+
+	if strings.TrimSpace(request.Script) == "" {
+		lines := make([]Line, 0, len(request.Lines))
+		for index, line := range request.Lines {
+			if strings.TrimSpace(line.Character) == "" || strings.TrimSpace(line.Text) == "" {
+				return nil, 0, fmt.Errorf("line %d needs a character and a text", index+1)
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) == 0 {
+			return nil, 0, errors.New("a script or a list of lines is required")
+		}
+		return lines, 0, nil
+	}
+
+	parsed, err := script.Parse(request.Script)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var lines []Line
+	skipped := 0
+	for _, entry := range parsed.Entries() {
+		if entry.HasDirectives() {
+			skipped++
+			continue
+		}
+		lines = append(lines, Line{Character: entry.Character, Text: entry.Text})
+	}
+	return lines, skipped, nil
+}
+
+type Manager struct {
+	mutex   sync.Mutex
+	warmups map[string]*Warmup
+	order   []string // IDs in the order the warm-ups started
+}
+
+var (
+	manager     *Manager
+	managerOnce sync.Once
+)
+
+func GetManager() *Manager {
+	managerOnce.Do(func() {
+		manager = &Manager{warmups: map[string]*Warmup{}}
+	})
+	return manager
+}
+
+// Start checks a request and warms its lines in the background. progress, when not nil, is called with a copy of
+// the warm-up after every line and once it finished.
+func (manager *Manager) Start(request Request, progress func(*Warmup)) (*Warmup, error) {
+	if !cache.GetManager().IsEnabledFor(request.Profile) {
+		return nil, cache.ErrDisabled
+	}
+
+	lines, skipped, err := ParseRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, response.Err(err)
+	}
+
+	warmup := &Warmup{
+		ID:      id,
+		Profile: request.Profile,
+		State:   Running,
+		Started: time.Now(),
+		Total:   len(lines),
+		Skipped: skipped,
+	}
+
+	manager.mutex.Lock()
+	manager.warmups[id] = warmup
+	manager.order = append(manager.order, id)
+	manager.forget()
+	started := warmup.copy()
+	manager.mutex.Unlock()
+
+	// Tracked under the warm-up's ID before it starts, so Cancel stops it from the moment Start returns
+	ctx, done := tts.Track(context.Background(), tts.WarmupPrefix+id)
+	go func() {
+		defer done()
+		manager.run(ctx, warmup, lines, progress)
+	}()
+	return started, nil
+}
+
+// Run warms the lines of a request and waits until it is done, calling progress like Start
+func (manager *Manager) Run(request Request, progress func(*Warmup)) (*Warmup, error) {
+	done := make(chan *Warmup, 1)
+	_, err := manager.Start(request, func(warmup *Warmup) {
+		if progress != nil {
+			progress(warmup)
+		}
+		if !warmup.Active() {
+			done <- warmup
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return <-done, nil
+}
+
+func (manager *Manager) Get(id string) *Warmup {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	warmup, exists := manager.warmups[id]
+	if !exists {
+		return nil
+	}
+	return warmup.copy()
+}
+
+// List returns every warm-up the manager knows, the most recent first
+func (manager *Manager) List() []*Warmup {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	list := make([]*Warmup, 0, len(manager.order))
+	for index := len(manager.order) - 1; index >= 0; index-- {
+		list = append(list, manager.warmups[manager.order[index]].copy())
+	}
+	return list
+}
+
+// Cancel stops a running warm-up after the line being generated. It returns nil when there is no such warm-up.
+func (manager *Manager) Cancel(id string) *Warmup {
+	manager.mutex.Lock()
+	warmup, exists := manager.warmups[id]
+	if !exists {
+		manager.mutex.Unlock()
+		return nil
+	}
+	active := warmup.Active()
+	manager.mutex.Unlock()
+
+	if active {
+		tts.Cancel(tts.WarmupPrefix + id)
+	}
+	return manager.Get(id)
+}
+
+// ExportBundle writes the lines a warm-up put in the cache, or found there, to writer as a cache bundle
+func (manager *Manager) ExportBundle(id string, writer io.Writer) (*cache.BundleSummary, error) {
+	manager.mutex.Lock()
+	warmup, exists := manager.warmups[id]
+	if !exists {
+		manager.mutex.Unlock()
+		return nil, fmt.Errorf("warm-up %s not found", id)
+	}
+	if warmup.Active() {
+		manager.mutex.Unlock()
+		return nil, fmt.Errorf("warm-up %s is still running", id)
+	}
+	profileID := warmup.Profile
+	keys := slices.Clone(warmup.keys)
+	manager.mutex.Unlock()
+
+	if keys == nil {
+		keys = []string{}
+	}
+	return cache.GetManager().ExportBundle(writer, profileID, keys)
+}
+
+func (manager *Manager) run(ctx context.Context, warmup *Warmup, lines []Line, progress func(*Warmup)) {
+	response.LogInfo(fmt.Sprintf("Warming the cache of profile '%s' with %d lines\n", warmup.Profile, len(lines)))
+
+	for index, line := range lines {
+		if ctx.Err() != nil {
+			break
+		}
+
+		key, cached, err := warmLine(ctx, warmup.Profile, line)
+		if ctx.Err() != nil {
+			break
+		}
+
+		manager.mutex.Lock()
+		warmup.Done++
+		switch {
+		case err != nil:
+			warmup.Errors = append(warmup.Errors, LineError{Index: index, Character: line.Character, Error: err.Error()})
+		case cached:
+			warmup.Cached++
+			warmup.keys = append(warmup.keys, key)
+		default:
+			warmup.Generated++
+			warmup.keys = append(warmup.keys, key)
+		}
+		snapshot := warmup.copy()
+		manager.mutex.Unlock()
+
+		manager.report(snapshot, progress)
+	}
+
+	manager.mutex.Lock()
+	now := time.Now()
+	warmup.Finished = &now
+	switch {
+	case ctx.Err() != nil:
+		warmup.State = Cancelled
+	case len(warmup.Errors) > 0 && len(warmup.Errors) == warmup.Total:
+		warmup.State = Failed
+		warmup.Error = "no line could be generated"
+	default:
+		warmup.State = Completed
+	}
+	snapshot := warmup.copy()
+	manager.mutex.Unlock()
+
+	response.LogInfo(fmt.Sprintf("Warm-up %s %s: %d generated, %d cached already, %d failed\n",
+		snapshot.ID, snapshot.State, snapshot.Generated, snapshot.Cached, len(snapshot.Errors)))
+	manager.report(snapshot, progress)
+}
+
+// warmLine makes sure a line is in the cache and returns its store key. cached reports whether it was there already.
+func warmLine(ctx context.Context, profileID string, line Line) (key string, cached bool, err error) {
+	voice, err := profile.GetManager().GetOrAllocateVoice(profileID, line.Character)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get voice allocation: %w", err)
+	}
+
+	_, cached, err = tts.GenerateCachedAudio(ctx, profileID, line.Character, voice, line.Text)
+	if err != nil {
+		return "", false, err
+	}
+	return cache.Key(voice.Key(), line.Text), cached, nil
+}
+
+func (manager *Manager) report(snapshot *Warmup, progress func(*Warmup)) {
+	eventManager.GetInstance().EmitEvent(ProgressEvent, snapshot)
+	if progress != nil {
+		progress(snapshot)
+	}
+}
+
+// forget drops the oldest finished warm-ups beyond finishedKept. The manager must be locked.
+func (manager *Manager) forget() {
+	finished := 0
+	for index := len(manager.order) - 1; index >= 0; index-- {
+		id := manager.order[index]
+		if manager.warmups[id].Active() {
+			continue
+		}
+		finished++
+		if finished > finishedKept {
+			delete(manager.warmups, id)
+			manager.order = slices.Delete(manager.order, index, index+1)
+		}
+	}
+}
+
+func newID() (string, error) {
+	bytes := make([]byte, 8)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,162 @@
+package audio
+
+import (
+	"fmt"
+	"nstudio/app/common/response"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/go-audio/audio"
+	"github.com/go-audio/wav"
+)
+
+// overrunTolerance is how far a line may run past its slot before it's reported, tempo changes aren't exact
+const overrunTolerance = 20 * time.Millisecond
+
+// Slot is where a line should be placed in an aligned track. A slot without a length (End <= Start) has no cue
+// time, the line follows the one before it after a pause.
+type Slot struct {
+	Path  string
+	Start time.Duration
+	End   time.Duration
+}
+
+// Overrun is a line that is longer than its slot, even after speeding it up as far as allowed
+type Overrun struct {
+	Path   string
+	Start  time.Duration
+	Slot   time.Duration
+	Length time.Duration // after the tempo change
+	Tempo  float64
+}
+
+// Excess is how far the line runs past the end of its slot
+func (overrun Overrun) Excess() time.Duration {
+	return overrun.Length - overrun.Slot
+}
+
+// ListWAVFiles returns the WAV files of a directory in the order of their "N)" index prefix, leaving out exclude
+func ListWAVFiles(dirPath, exclude string) ([]string, error) {
+	wavFiles, err := filepath.Glob(filepath.Join(dirPath, "*.wav"))
+	if err != nil {
+		return nil, response.Err(fmt.Errorf("Failed to list WAV files: %v", err))
+	}
+
+	wavFiles = slices.DeleteFunc(wavFiles, func(path string) bool { return filepath.Base(path) == exclude })
+	sortByIndex(wavFiles)
+	return wavFiles, nil
+}
+
+// AlignWAVFiles builds a track where every line starts at its slot, for dubbing over existing video. Lines longer
+// than their slot are sped up without changing their pitch, by at most maxSpeedup (1 or less never changes the
+// tempo); the ones that still don't fit are returned as overruns and overlap the next line.
+func AlignWAVFiles(dirPath, outputFilename string, slots []Slot, pauseDuration time.Duration, maxSpeedup float64, sampleRate, channelCount, bitDepth int) ([]Clip, []Overrun, error) {
+	if len(slots) == 0 {
+		return nil, nil, response.Err(fmt.Errorf("No WAV files to align"))
+	}
+
+	limit := 1<<(bitDepth-1) - 1
+	var mixed []int
+	clips := make([]Clip, 0, len(slots))
+	var overruns []Overrun
+	var previousEnd time.Duration
+
+	for index, slot := range slots {
+		wavData, err := os.ReadFile(slot.Path)
+		if err != nil {
+			return nil, nil, response.Err(err)
+		}
+
+		line, err := NewAudioFromWAV(wavData)
+		if err != nil {
+			return nil, nil, response.Err(fmt.Errorf("Invalid WAV file %s: %v", slot.Path, err))
+		}
+		if err := line.Resample(sampleRate); err != nil {
+			return nil, nil, err
+		}
+		if err := line.ChangeChannels(channelCount); err != nil {
+			return nil, nil, err
+		}
+
+		buffer, err := line.intBuffer()
+		if err != nil {
+			return nil, nil, err
+		}
+		length := samplesDuration(len(buffer.Data), channelCount, sampleRate)
+
+		start := slot.Start
+		timed := slot.End > slot.Start
+		if !timed {
+			start = previousEnd
+			if index > 0 {
+				start += pauseDuration
+			}
+		}
+
+		tempo := 1.0
+		if timed && length > slot.End-slot.Start && maxSpeedup > 1 {
+			tempo = min(float64(length)/float64(slot.End-slot.Start), maxSpeedup)
+			if err := line.ChangeTempo(tempo); err != nil {
+				return nil, nil, err
+			}
+			if buffer, err = line.intBuffer(); err != nil {
+				return nil, nil, err
+			}
+			length = samplesDuration(len(buffer.Data), channelCount, sampleRate)
+		}
+
+		if buffer.SourceBitDepth != bitDepth {
+			if buffer, err = ChangeBitDepth(buffer, bitDepth); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if timed && length > slot.End-slot.Start+overrunTolerance {
+			overruns = append(overruns, Overrun{
+				Path:   slot.Path,
+				Start:  start,
+				Slot:   slot.End - slot.Start,
+				Length: length,
+				Tempo:  tempo,
+			})
+		}
+
+		// Lines that overrun overlap the next one, so they are mixed in rather than appended
+		offset := int(start.Seconds()*float64(sampleRate)) * channelCount
+		if grow := offset + len(buffer.Data) - len(mixed); grow > 0 {
+			mixed = append(mixed, make([]int, grow)...)
+		}
+		for index, sample := range buffer.Data {
+			mixed[offset+index] = min(max(mixed[offset+index]+sample, -limit-1), limit)
+		}
+
+		end := samplesDuration(offset+len(buffer.Data), channelCount, sampleRate)
+		clips = append(clips, Clip{Path: slot.Path, Start: start, End: end})
+		previousEnd = max(previousEnd, end)
+	}
+
+	outputPath := filepath.Join(dirPath, outputFilename)
+	combinedFile, err := os.Create(outputPath)
+	if err != nil {
+		return nil, nil, response.Err(err)
+	}
+	defer combinedFile.Close()
+
+	encoder := wav.NewEncoder(combinedFile, sampleRate, bitDepth, channelCount, 1)
+	err = encoder.Write(&audio.IntBuffer{
+		Data:           mixed,
+		Format:         &audio.Format{NumChannels: channelCount, SampleRate: sampleRate},
+		SourceBitDepth: bitDepth,
+	})
+	if err != nil {
+		return nil, nil, response.Err(err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, nil, response.Err(err)
+	}
+
+	return clips, overruns, nil
+}
@@ -7,23 +7,46 @@ import (
 	"nstudio/app/common/response"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"time"
 
 	"github.com/go-audio/audio"
 	"github.com/go-audio/wav"
 )
 
-func CombineWAVFiles(dirPath, outputFilename string, pauseDuration time.Duration, sampleRate, channelCount, bitDepth int) error {
-	wavFiles, err := filepath.Glob(filepath.Join(dirPath, "*.wav"))
+var fileIndexRegex = regexp.MustCompile(`^(\d+)\)`)
+
+// Clip is where one of the combined files landed in the output
+type Clip struct {
+	Path  string
+	Start time.Duration
+	End   time.Duration
+}
+
+// CombineWAVFiles joins the WAV files of a directory with a pause between them, in the order of their "N)" index
+// prefix. It returns where every file starts and ends in the combined file.
+func CombineWAVFiles(dirPath, outputFilename string, pauseDuration time.Duration, sampleRate, channelCount, bitDepth int) ([]Clip, error) {
+	// A previous combined file is not one of the lines
+	wavFiles, err := ListWAVFiles(dirPath, outputFilename)
 	if err != nil {
-		return response.Err(fmt.Errorf("Failed to list WAV files: %v", err))
+		return nil, err
 	}
 	if len(wavFiles) == 0 {
-		return response.Err(fmt.Errorf("No WAV files found in the directory"))
+		return nil, response.Err(fmt.Errorf("No WAV files found in the directory"))
 	}
 
-	sort.Strings(wavFiles)
+	return JoinWAVFiles(wavFiles, filepath.Join(dirPath, outputFilename), pauseDuration, sampleRate, channelCount, bitDepth)
+}
+
+// JoinWAVFiles writes the files one after the other to outputPath, with a pause between them
+func JoinWAVFiles(wavFiles []string, outputPath string, pauseDuration time.Duration, sampleRate, channelCount, bitDepth int) ([]Clip, error) {
+	if len(wavFiles) == 0 {
+		return nil, response.Err(fmt.Errorf("No WAV files to join"))
+	}
+
+	clips := make([]Clip, 0, len(wavFiles))
 
 	var combinedBuffer *audio.IntBuffer
 
@@ -42,17 +65,17 @@ func CombineWAVFiles(dirPath, outputFilename string, pauseDuration time.Duration
 		file, err := os.Open(wavPath)
 		defer file.Close()
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		decoder := wav.NewDecoder(file)
 		if !decoder.IsValidFile() {
-			return response.Err(fmt.Errorf("Invalid WAV file: " + wavPath))
+			return nil, response.Err(fmt.Errorf("Invalid WAV file: %s", wavPath))
 		}
 
 		pcmBuffer, err := decoder.FullPCMBuffer()
 		if err != nil {
-			return response.Err(err)
+			return nil, response.Err(err)
 		}
 
 		if pcmBuffer.Format.SampleRate != sampleRate {
@@ -84,14 +107,19 @@ func CombineWAVFiles(dirPath, outputFilename string, pauseDuration time.Duration
 			}
 		}
 
+		start := samplesDuration(len(combinedBuffer.Data), channelCount, sampleRate)
 		combinedBuffer.Data = append(combinedBuffer.Data, pcmBuffer.Data...)
+		clips = append(clips, Clip{
+			Path:  wavPath,
+			Start: start,
+			End:   samplesDuration(len(combinedBuffer.Data), channelCount, sampleRate),
+		})
 
 		if index < len(wavFiles)-1 {
 			combinedBuffer.Data = append(combinedBuffer.Data, silenceBuffer.Data...)
 		}
 	}
 
-	outputPath := filepath.Join(dirPath, outputFilename)
 	combinedFile, err := os.Create(outputPath)
 	if err != nil {
 		response.Err(err)
@@ -109,7 +137,32 @@ func CombineWAVFiles(dirPath, outputFilename string, pauseDuration time.Duration
 		response.Err(err)
 	}
 
-	return nil
+	return clips, nil
+}
+
+// sortByIndex orders generated files by their "N)" prefix, so line 10 comes after line 9 rather than after line 1
+func sortByIndex(paths []string) {
+	index := func(path string) int {
+		match := fileIndexRegex.FindStringSubmatch(filepath.Base(path))
+		if match == nil {
+			return -1
+		}
+		number, _ := strconv.Atoi(match[1])
+		return number
+	}
+
+	sort.SliceStable(paths, func(i, j int) bool {
+		left, right := index(paths[i]), index(paths[j])
+		if left != right {
+			return left < right
+		}
+		return paths[i] < paths[j]
+	})
+}
+
+// samplesDuration converts a count of interleaved samples to a duration
+func samplesDuration(samples, channelCount, sampleRate int) time.Duration {
+	return time.Duration(float64(samples) / float64(channelCount*sampleRate) * float64(time.Second))
 }
 
 func ResampleBuffer(buffer *audio.IntBuffer, targetSampleRate int) (*audio.IntBuffer, error) {
@@ -250,6 +303,56 @@ func SaveWAVFile(audioClip []byte, filename string) error {
 	return nil
 }
 
+// SaveMP3 writes audioClip as an MP3 file. MP3 input is written as-is, WAV input is encoded,
+// and anything else is treated as raw PCM in the same format SaveWAVFile assumes.
 func SaveMP3(audioClip []byte, filename string) error {
-	return os.WriteFile(filename, audioClip, 0644)
+	var mp3Data []byte
+	var err error
+
+	switch {
+	case isMP3Data(audioClip):
+		mp3Data = audioClip
+	case len(audioClip) >= 12 && string(audioClip[0:4]) == "RIFF" && string(audioClip[8:12]) == "WAVE":
+		var wavAudio *Audio
+		wavAudio, err = NewAudioFromWAV(audioClip)
+		if err != nil {
+			return err
+		}
+		mp3Data, err = wavAudio.ToMP3()
+	default:
+		mp3Data, err = NewAudioFromPCM(audioClip, 24000, 1, 16).ToMP3()
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, mp3Data, 0644)
+}
+
+// isMP3Data checks for an ID3 tag or an MPEG audio frame header followed by a second frame
+func isMP3Data(data []byte) bool {
+	if len(data) >= 3 && string(data[0:3]) == "ID3" {
+		return true
+	}
+	if len(data) < 4 || data[0] != 0xFF || data[1]&0xE0 != 0xE0 {
+		return false
+	}
+
+	version := (data[1] >> 3) & 0x03
+	layer := (data[1] >> 1) & 0x03
+	bitrateIndex := int(data[2] >> 4)
+	rateIndex := int(data[2]>>2) & 0x03
+	if version == 1 || layer == 0 || bitrateIndex == 0 || bitrateIndex == 15 || rateIndex == 3 {
+		return false
+	}
+	if version != 3 || layer != 1 {
+		return true
+	}
+
+	// MPEG-1 Layer III: the next frame must start where this one ends
+	size := 144*mp3Bitrates[bitrateIndex]*1000/mp3SampleRates[rateIndex] + int(data[2]>>1)&0x01
+	if len(data) < size+2 {
+		return true
+	}
+	return data[size] == 0xFF && data[size+1]&0xE0 == 0xE0
 }
\ No newline at end of file
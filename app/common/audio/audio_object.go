@@ -35,6 +35,58 @@ type Audio struct {
 	Metadata AudioMetadata
 }
 
+// EncodeOptions holds the settings for the compressed output formats
+type EncodeOptions struct {
+	CompressionLevel int     // FLAC compression level, 0-8
+	Quality          float64 // Ogg Vorbis and VBR MP3 quality, 0-10
+	Bitrate          int     // Target bitrate in kbps for lossy formats, 0 uses Quality (DefaultMP3Bitrate for CBR MP3)
+	VBR              bool    // MP3 bitrate mode, Ogg Vorbis is always variable
+}
+
+func DefaultEncodeOptions() EncodeOptions {
+	return EncodeOptions{
+		CompressionLevel: DefaultFLACCompressionLevel,
+		Quality:          DefaultOGGQuality,
+	}
+}
+
+// ParseEncodeOptions reads "quality", "bitrate", "bitrate_mode" ("cbr" or "vbr") and "compression_level"
+// from a decoded JSON object, starting from the defaults
+func ParseEncodeOptions(values map[string]interface{}) (EncodeOptions, error) {
+	options := DefaultEncodeOptions()
+
+	if quality, ok := values["quality"].(float64); ok {
+		if quality < MinOGGQuality || quality > MaxOGGQuality {
+			return options, fmt.Errorf("quality must be between %g and %g", MinOGGQuality, MaxOGGQuality)
+		}
+		options.Quality = quality
+	}
+	if bitrate, ok := values["bitrate"].(float64); ok {
+		if bitrate < 0 {
+			return options, fmt.Errorf("bitrate must be positive")
+		}
+		options.Bitrate = int(bitrate)
+	}
+	if mode, ok := values["bitrate_mode"].(string); ok {
+		switch strings.ToLower(mode) {
+		case "cbr":
+			options.VBR = false
+		case "vbr":
+			options.VBR = true
+		default:
+			return options, fmt.Errorf("bitrate_mode must be cbr or vbr")
+		}
+	}
+	if compressionLevel, ok := values["compression_level"].(float64); ok {
+		if compressionLevel < MinFLACCompressionLevel || compressionLevel > MaxFLACCompressionLevel {
+			return options, fmt.Errorf("compression_level must be between %d and %d", MinFLACCompressionLevel, MaxFLACCompressionLevel)
+		}
+		options.CompressionLevel = int(compressionLevel)
+	}
+
+	return options, nil
+}
+
 func NewAudioFromPCM(pcmData []byte, sampleRate, channels, bitDepth int) *Audio {
 	return &Audio{
 		Data: pcmData,
@@ -138,18 +190,69 @@ func (a *Audio) ToFLAC() ([]byte, error) {
 		return a.Data, nil
 	}
 
-	return a.ToWAV()
+	return a.ToFLACLevel(DefaultFLACCompressionLevel)
+}
+
+// ToFLACLevel encodes the audio as FLAC using the given compression level (0-8)
+func (a *Audio) ToFLACLevel(compressionLevel int) ([]byte, error) {
+	pcmData, err := a.ToPCM()
+	if err != nil {
+		return nil, err
+	}
+
+	flacData, err := EncodeFLAC(pcmData, a.Metadata.SampleRate, a.Metadata.Channels, a.Metadata.BitDepth, compressionLevel)
+	if err != nil {
+		return nil, response.Err(err)
+	}
+
+	return flacData, nil
 }
 
 func (a *Audio) ToOGG() ([]byte, error) {
-	return a.ToWAV()
+	return a.ToOGGWithOptions(DefaultEncodeOptions())
+}
+
+func (a *Audio) ToOGGWithOptions(options EncodeOptions) ([]byte, error) {
+	pcmData, err := a.ToPCM()
+	if err != nil {
+		return nil, err
+	}
+
+	oggData, err := EncodeOGG(pcmData, a.Metadata.SampleRate, a.Metadata.Channels, a.Metadata.BitDepth, options.Quality, options.Bitrate)
+	if err != nil {
+		return nil, response.Err(err)
+	}
+
+	return oggData, nil
 }
 
 func (a *Audio) ToMP3() ([]byte, error) {
-	return a.ToWAV()
+	if a.Metadata.Format == FormatMP3 {
+		return a.Data, nil
+	}
+
+	return a.ToMP3WithOptions(DefaultEncodeOptions())
+}
+
+func (a *Audio) ToMP3WithOptions(options EncodeOptions) ([]byte, error) {
+	pcmData, err := a.ToPCM()
+	if err != nil {
+		return nil, err
+	}
+
+	mp3Data, err := EncodeMP3(pcmData, a.Metadata.SampleRate, a.Metadata.Channels, a.Metadata.BitDepth, options.Quality, options.Bitrate, options.VBR)
+	if err != nil {
+		return nil, response.Err(err)
+	}
+
+	return mp3Data, nil
 }
 
 func (a *Audio) ToFormat(format string) ([]byte, error) {
+	return a.ToFormatWithOptions(format, DefaultEncodeOptions())
+}
+
+func (a *Audio) ToFormatWithOptions(format string, options EncodeOptions) ([]byte, error) {
 	normalizedFormat := strings.ToLower(format)
 
 	switch normalizedFormat {
@@ -158,11 +261,17 @@ func (a *Audio) ToFormat(format string) ([]byte, error) {
 	case "wav":
 		return a.ToWAV()
 	case "flac":
-		return a.ToFLAC()
+		if a.Metadata.Format == FormatFLAC {
+			return a.Data, nil
+		}
+		return a.ToFLACLevel(options.CompressionLevel)
 	case "ogg":
-		return a.ToOGG()
+		return a.ToOGGWithOptions(options)
 	case "mp3":
-		return a.ToMP3()
+		if a.Metadata.Format == FormatMP3 {
+			return a.Data, nil
+		}
+		return a.ToMP3WithOptions(options)
 	default:
 		return nil, response.Err(fmt.Errorf("unsupported output format: %s", format))
 	}
@@ -273,12 +382,22 @@ func decodeFLACToPCM(flacData []byte) ([]byte, int, int, int, error) {
 
 		// FLAC stores samples interleaved by subframe (channel), we need to interleave them
 		// For stereo: subframes[0].Samples[i] is left channel, subframes[1].Samples[i] is right channel
+		// Samples are written at the stream's bit depth, 8-bit as unsigned like WAV
 		numSamples := len(frame.Subframes[0].Samples)
 
 		for i := 0; i < numSamples; i++ {
 			for _, subframe := range frame.Subframes {
-				sample := int16(subframe.Samples[i])
-				binary.Write(&pcmBuffer, binary.LittleEndian, sample)
+				sample := subframe.Samples[i]
+				switch bitDepth {
+				case 8:
+					pcmBuffer.WriteByte(byte(sample + 128))
+				case 24:
+					pcmBuffer.Write([]byte{byte(sample), byte(sample >> 8), byte(sample >> 16)})
+				case 32:
+					binary.Write(&pcmBuffer, binary.LittleEndian, sample)
+				default:
+					binary.Write(&pcmBuffer, binary.LittleEndian, int16(sample))
+				}
 			}
 		}
 	}
@@ -324,12 +443,18 @@ func extractPCMFromWAV(wavData []byte) ([]byte, error) {
 	return wavData[44:], nil
 }
 
+// pcmBytesToIntBuffer reads little-endian PCM of the metadata's bit depth, 8-bit samples being unsigned. Audio
+// without a bit depth is read as 16-bit.
 func pcmBytesToIntBuffer(pcmData []byte, metadata AudioMetadata) (*audio.IntBuffer, error) {
-	bytesPerSample := metadata.BitDepth / 8
-	if bytesPerSample == 0 {
-		bytesPerSample = 2
+	bitDepth := metadata.BitDepth
+	if bitDepth == 0 {
+		bitDepth = 16
+	}
+	if bitDepth != 8 && bitDepth != 16 && bitDepth != 24 && bitDepth != 32 {
+		return nil, response.Err(fmt.Errorf("unsupported bit depth: %d", bitDepth))
 	}
 
+	bytesPerSample := bitDepth / 8
 	sampleCount := len(pcmData) / bytesPerSample
 
 	buffer := &audio.IntBuffer{
@@ -338,33 +463,49 @@ func pcmBytesToIntBuffer(pcmData []byte, metadata AudioMetadata) (*audio.IntBuff
 			NumChannels: metadata.Channels,
 		},
 		Data:           make([]int, sampleCount),
-		SourceBitDepth: metadata.BitDepth,
+		SourceBitDepth: bitDepth,
 	}
 
-	reader := bytes.NewReader(pcmData)
 	for i := 0; i < sampleCount; i++ {
-		var sample int16
-		if err := binary.Read(reader, binary.LittleEndian, &sample); err != nil {
-			if err == io.EOF {
-				break
-			}
-			return nil, response.Err(err)
+		sample := pcmData[i*bytesPerSample:]
+		switch bitDepth {
+		case 8:
+			buffer.Data[i] = int(sample[0]) - 128
+		case 16:
+			buffer.Data[i] = int(int16(binary.LittleEndian.Uint16(sample)))
+		case 24:
+			buffer.Data[i] = int(int32(uint32(sample[0])<<8|uint32(sample[1])<<16|uint32(sample[2])<<24) >> 8)
+		case 32:
+			buffer.Data[i] = int(int32(binary.LittleEndian.Uint32(sample)))
 		}
-		buffer.Data[i] = int(sample)
 	}
 
 	return buffer, nil
 }
 
+// intBufferToPCMBytes writes the buffer as little-endian PCM of its SourceBitDepth, clipping samples to the range of
+// that bit depth. Buffers of another bit depth are written as 16-bit.
 func intBufferToPCMBytes(buffer *audio.IntBuffer) []byte {
-	var buf bytes.Buffer
+	bitDepth := buffer.SourceBitDepth
+	if bitDepth != 8 && bitDepth != 24 && bitDepth != 32 {
+		bitDepth = 16
+	}
+	maxValue := 1<<(bitDepth-1) - 1
+	minValue := -maxValue - 1
+
+	pcmData := make([]byte, 0, len(buffer.Data)*bitDepth/8)
 	for _, sample := range buffer.Data {
-		if sample > 32767 {
-			sample = 32767
-		} else if sample < -32768 {
-			sample = -32768
+		sample = min(max(sample, minValue), maxValue)
+		switch bitDepth {
+		case 8:
+			pcmData = append(pcmData, byte(sample+128))
+		case 16:
+			pcmData = binary.LittleEndian.AppendUint16(pcmData, uint16(sample))
+		case 24:
+			pcmData = append(pcmData, byte(sample), byte(sample>>8), byte(sample>>16))
+		case 32:
+			pcmData = binary.LittleEndian.AppendUint32(pcmData, uint32(sample))
 		}
-		binary.Write(&buf, binary.LittleEndian, int16(sample))
 	}
-	return buf.Bytes()
+	return pcmData
 }
\ No newline at end of file
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
-	"os"
 	"strings"
 )
 
@@ -17,6 +16,8 @@ func ConvertRawToFormat(rawAudio []byte, format string) ([]byte, error) {
 		return ConvertRawToFLAC(rawAudio)
 	case "ogg":
 		return ConvertRawToOGG(rawAudio)
+	case "mp3":
+		return ConvertRawToMP3(rawAudio)
 	default:
 		return nil, fmt.Errorf("unsupported format: %s", format)
 	}
@@ -55,47 +56,43 @@ func ConvertRawToWAV(rawAudio []byte) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// ConvertRawToFLAC converts raw audio bytes to FLAC format
-// Note: Currently returns WAV as fallback. Full FLAC encoding requires additional dependencies.
-func ConvertRawToFLAC(rawAudio []byte) ([]byte, error) {
-	wavData, err := ConvertRawToWAV(rawAudio)
-	if err != nil {
-		return nil, err
-	}
+// StreamingWAVHeader returns a WAV header for a stream whose length isn't known up front. The RIFF and data
+// sizes are set to the maximum, which players treat as "read until the end of the stream".
+func StreamingWAVHeader(sampleRate, channels, bitDepth int) []byte {
+	var buf bytes.Buffer
 
-	tempWavFile := "/tmp/temp_audio.wav"
-	tempFlacFile := "/tmp/temp_audio.flac"
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(0xFFFFFFFF))
+	buf.WriteString("WAVE")
 
-	err = os.WriteFile(tempWavFile, wavData, 0644)
-	if err != nil {
-		return nil, err
-	}
-	defer os.Remove(tempWavFile)
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint16(1))
+	binary.Write(&buf, binary.LittleEndian, uint16(channels))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate*channels*bitDepth/8))
+	binary.Write(&buf, binary.LittleEndian, uint16(channels*bitDepth/8))
+	binary.Write(&buf, binary.LittleEndian, uint16(bitDepth))
 
-	// Note: This requires flac command to be installed
-	// For production, you'd want to use a Go FLAC library instead
-	// For now, just return WAV format as fallback
-	defer os.Remove(tempFlacFile)
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(0xFFFFFFFF))
 
-	// Since we can't rely on external commands, return WAV as fallback
-	return wavData, nil
+	return buf.Bytes()
 }
 
-// ConvertRawToOGG converts raw audio bytes to OGG format
-// Note: Currently returns WAV as fallback. Full OGG encoding requires additional dependencies.
-func ConvertRawToOGG(rawAudio []byte) ([]byte, error) {
-	// For now, create a temporary WAV file and use system oggenc command
-	// This is a simple implementation - in production you'd want a proper OGG library
+// ConvertRawToFLAC converts raw audio bytes to FLAC format
+func ConvertRawToFLAC(rawAudio []byte) ([]byte, error) {
+	return EncodeFLAC(rawAudio, 22050, 1, 16, DefaultFLACCompressionLevel)
+}
 
-	// Create temporary WAV file
-	wavData, err := ConvertRawToWAV(rawAudio)
-	if err != nil {
-		return nil, err
-	}
+// ConvertRawToOGG converts raw audio bytes to Ogg Vorbis format
+func ConvertRawToOGG(rawAudio []byte) ([]byte, error) {
+	return EncodeOGG(rawAudio, 22050, 1, 16, DefaultOGGQuality, 0)
+}
 
-	// Since we can't rely on external commands in this environment,
-	// return WAV format as fallback for now
-	return wavData, nil
+// ConvertRawToMP3 converts raw audio bytes to constant bitrate MP3
+func ConvertRawToMP3(rawAudio []byte) ([]byte, error) {
+	return EncodeMP3(rawAudio, 22050, 1, 16, DefaultMP3Quality, DefaultMP3Bitrate, false)
 }
 
 // GetContentType returns the MIME content type for the given audio format
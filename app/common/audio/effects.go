@@ -0,0 +1,196 @@
+package audio
+
+import (
+	"fmt"
+	"math"
+	"nstudio/app/common/response"
+	"time"
+
+	"github.com/go-audio/audio"
+)
+
+// NewSilence returns duration worth of silent PCM in the given format
+func NewSilence(duration time.Duration, sampleRate, channels, bitDepth int) *Audio {
+	frames := int(duration.Seconds() * float64(sampleRate))
+	if frames < 0 {
+		frames = 0
+	}
+	return NewAudioFromPCM(make([]byte, frames*channels*bitDepth/8), sampleRate, channels, bitDepth)
+}
+
+// Concatenate joins the parts into one PCM clip, converting every part to the sample rate and channel count of
+// the first one
+func Concatenate(parts ...*Audio) (*Audio, error) {
+	if len(parts) == 0 {
+		return nil, response.Err(fmt.Errorf("nothing to concatenate"))
+	}
+
+	first := parts[0]
+	if _, err := first.ToPCM(); err != nil {
+		return nil, err
+	}
+	metadata := first.Metadata
+
+	var combined []byte
+	for _, part := range parts {
+		if err := part.Resample(metadata.SampleRate); err != nil {
+			return nil, err
+		}
+		if err := part.ChangeChannels(metadata.Channels); err != nil {
+			return nil, err
+		}
+
+		pcmData, err := part.ToPCM()
+		if err != nil {
+			return nil, err
+		}
+		combined = append(combined, pcmData...)
+	}
+
+	return NewAudioFromPCM(combined, metadata.SampleRate, metadata.Channels, metadata.BitDepth), nil
+}
+
+// ApplyGain changes the loudness by decibels, clipping samples to the range of the audio's bit depth, like
+// -32768 to 32767 for 16-bit audio
+func (a *Audio) ApplyGain(decibels float64) error {
+	if decibels == 0 {
+		return nil
+	}
+
+	buffer, err := a.intBuffer()
+	if err != nil {
+		return err
+	}
+
+	factor := math.Pow(10, decibels/20)
+	for index, sample := range buffer.Data {
+		buffer.Data[index] = int(math.Round(float64(sample) * factor))
+	}
+
+	a.Data = intBufferToPCMBytes(buffer)
+	a.Metadata.Format = FormatPCM
+	return nil
+}
+
+// ChangeTempo speeds the audio up (factor > 1) or slows it down (factor < 1) without changing its pitch.
+// It uses WSOLA: overlapping windows are copied from the input at the new pace, each one shifted slightly to
+// line up with the waveform already written so the seams don't click.
+func (a *Audio) ChangeTempo(factor float64) error {
+	if factor <= 0 {
+		return response.Err(fmt.Errorf("invalid tempo factor: %v", factor))
+	}
+	if factor == 1 {
+		return nil
+	}
+
+	buffer, err := a.intBuffer()
+	if err != nil {
+		return err
+	}
+
+	channels := max(a.Metadata.Channels, 1)
+	frames := len(buffer.Data) / channels
+
+	windowSize := max(a.Metadata.SampleRate*30/1000, 64)
+	overlap := windowSize / 2
+	seek := a.Metadata.SampleRate * 10 / 1000
+	outputHop := windowSize - overlap
+	inputHop := float64(outputHop) * factor
+
+	if frames < windowSize+seek {
+		return nil
+	}
+
+	// The search compares a mono mix, the copy is done for every channel with the same offset
+	mono := make([]float64, frames)
+	for frame := 0; frame < frames; frame++ {
+		sum := 0
+		for channel := 0; channel < channels; channel++ {
+			sum += buffer.Data[frame*channels+channel]
+		}
+		mono[frame] = float64(sum) / float64(channels)
+	}
+
+	window := make([]float64, windowSize)
+	for index := range window {
+		window[index] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(index)/float64(windowSize-1))
+	}
+
+	outputFrames := int(float64(frames)/factor) + windowSize
+	output := make([]float64, outputFrames*channels)
+	weights := make([]float64, outputFrames)
+
+	written := 0
+	previous := -1
+	for position := 0.0; ; position += inputHop {
+		start := int(position)
+		if start+windowSize+seek > frames || written+windowSize > outputFrames {
+			break
+		}
+
+		if previous >= 0 {
+			start = bestOverlap(mono, previous+outputHop, start, seek, overlap)
+		}
+
+		for index := 0; index < windowSize; index++ {
+			weight := window[index]
+			for channel := 0; channel < channels; channel++ {
+				output[(written+index)*channels+channel] += float64(buffer.Data[(start+index)*channels+channel]) * weight
+			}
+			weights[written+index] += weight
+		}
+
+		previous = start
+		written += outputHop
+	}
+
+	length := written + overlap
+	result := make([]int, length*channels)
+	for frame := 0; frame < length; frame++ {
+		weight := weights[frame]
+		if weight < 1e-3 {
+			continue
+		}
+		for channel := 0; channel < channels; channel++ {
+			result[frame*channels+channel] = int(math.Round(output[frame*channels+channel] / weight))
+		}
+	}
+
+	buffer.Data = result
+	a.Data = intBufferToPCMBytes(buffer)
+	a.Metadata.Format = FormatPCM
+	return nil
+}
+
+// bestOverlap looks within seek frames of nominal for the window start that best continues the audio at natural,
+// the frame the previous window would have carried on with
+func bestOverlap(mono []float64, natural, nominal, seek, overlap int) int {
+	best := nominal
+	bestScore := math.Inf(-1)
+
+	from := max(nominal-seek, 0)
+	to := min(nominal+seek, len(mono)-overlap)
+	if natural+overlap > len(mono) {
+		return nominal
+	}
+
+	for candidate := from; candidate <= to; candidate++ {
+		score := 0.0
+		for index := 0; index < overlap; index += 2 {
+			score += mono[natural+index] * mono[candidate+index]
+		}
+		if score > bestScore {
+			bestScore = score
+			best = candidate
+		}
+	}
+	return best
+}
+
+func (a *Audio) intBuffer() (*audio.IntBuffer, error) {
+	pcmData, err := a.ToPCM()
+	if err != nil {
+		return nil, err
+	}
+	return pcmBytesToIntBuffer(pcmData, a.Metadata)
+}
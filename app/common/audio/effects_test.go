@@ -0,0 +1,65 @@
+package audio
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestEffectsKeepBitDepth(t *testing.T) {
+	for _, bitDepth := range []int{8, 16, 24, 32} {
+		for _, channels := range []int{1, 2} {
+			t.Run(fmt.Sprintf("%dbit/%dch", bitDepth, channels), func(t *testing.T) {
+				frames := 22050
+				pcmData := testSignal("noise", frames, channels, min(bitDepth, 24))
+				if bitDepth == 32 {
+					// Widen the 24-bit noise to 32-bit samples
+					var widened bytes.Buffer
+					for offset := 0; offset < len(pcmData); offset += 3 {
+						widened.Write([]byte{0, pcmData[offset], pcmData[offset+1], pcmData[offset+2]})
+					}
+					pcmData = widened.Bytes()
+				}
+
+				buffer, err := pcmBytesToIntBuffer(pcmData, AudioMetadata{SampleRate: 44100, Channels: channels, BitDepth: bitDepth})
+				if err != nil {
+					t.Fatalf("pcmBytesToIntBuffer: %v", err)
+				}
+				if !bytes.Equal(intBufferToPCMBytes(buffer), pcmData) {
+					t.Fatal("PCM changed going through an IntBuffer")
+				}
+
+				gained := NewAudioFromPCM(pcmData, 44100, channels, bitDepth)
+				if err := gained.ApplyGain(-6); err != nil {
+					t.Fatalf("ApplyGain: %v", err)
+				}
+				if len(gained.Data) != len(pcmData) || gained.Metadata.BitDepth != bitDepth {
+					t.Fatalf("ApplyGain wrote %d bytes of %d-bit audio, want %d bytes of %d-bit", len(gained.Data), gained.Metadata.BitDepth, len(pcmData), bitDepth)
+				}
+				original, _ := pcmBytesToIntBuffer(pcmData, gained.Metadata)
+				quieter, _ := pcmBytesToIntBuffer(gained.Data, gained.Metadata)
+				if ratio := rms(quieter.Data) / rms(original.Data); math.Abs(ratio-math.Pow(10, -6.0/20)) > 0.02 {
+					t.Fatalf("ApplyGain(-6) scaled the audio by %.3f", ratio)
+				}
+
+				slower := NewAudioFromPCM(pcmData, 44100, channels, bitDepth)
+				if err := slower.ChangeTempo(0.8); err != nil {
+					t.Fatalf("ChangeTempo: %v", err)
+				}
+				frameSize := channels * bitDepth / 8
+				if length := len(slower.Data) / frameSize; len(slower.Data)%frameSize != 0 || length < frames*11/10 {
+					t.Fatalf("ChangeTempo(0.8) wrote %d bytes for %d frames", len(slower.Data), frames)
+				}
+			})
+		}
+	}
+}
+
+func rms(samples []int) float64 {
+	var sum float64
+	for _, sample := range samples {
+		sum += float64(sample) * float64(sample)
+	}
+	return math.Sqrt(sum / float64(len(samples)))
+}
@@ -0,0 +1,886 @@
+package audio
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+const (
+	DefaultFLACCompressionLevel = 5
+	MinFLACCompressionLevel     = 0
+	MaxFLACCompressionLevel     = 8
+)
+
+const (
+	flacSubframeConstant = iota
+	flacSubframeVerbatim
+	flacSubframeFixed
+	flacSubframeLPC
+)
+
+const (
+	flacChannelIndependent = iota
+	flacChannelLeftSide
+	flacChannelSideRight
+	flacChannelMidSide
+)
+
+const (
+	flacMaxFixedOrder     = 4
+	flacMaxRiceParam      = 14
+	flacMaxRiceParamExt   = 30
+	flacMaxQLPShift       = 15
+	flacMaxQLPPrecision   = 15
+	flacSubframeHeaderLen = 8
+)
+
+type flacCompressionLevel struct {
+	blockSize         int
+	maxLPCOrder       int
+	maxPartitionOrder int
+	stereo            bool
+	exhaustive        bool
+}
+
+// Roughly mirrors the reference encoder presets
+var flacCompressionLevels = [...]flacCompressionLevel{
+	{blockSize: 1152, maxLPCOrder: 0, maxPartitionOrder: 3, stereo: false},
+	{blockSize: 1152, maxLPCOrder: 0, maxPartitionOrder: 3, stereo: true},
+	{blockSize: 1152, maxLPCOrder: 0, maxPartitionOrder: 4, stereo: true},
+	{blockSize: 4096, maxLPCOrder: 6, maxPartitionOrder: 4, stereo: false},
+	{blockSize: 4096, maxLPCOrder: 8, maxPartitionOrder: 4, stereo: true},
+	{blockSize: 4096, maxLPCOrder: 8, maxPartitionOrder: 5, stereo: true},
+	{blockSize: 4096, maxLPCOrder: 8, maxPartitionOrder: 6, stereo: true},
+	{blockSize: 4096, maxLPCOrder: 12, maxPartitionOrder: 6, stereo: true, exhaustive: true},
+	{blockSize: 4096, maxLPCOrder: 12, maxPartitionOrder: 8, stereo: true, exhaustive: true},
+}
+
+type flacSubframe struct {
+	kind              int
+	bitsPerSample     int
+	samples           []int64
+	order             int
+	coefficients      []int32
+	precision         int
+	shift             int
+	residual          []int64
+	partitionOrder    int
+	riceParams        []int
+	extendedRiceCodes bool
+	size              int
+}
+
+// <editor-fold desc="Encoder">
+
+// EncodeFLAC encodes interleaved little-endian PCM into a complete FLAC stream.
+// Supported bit depths are 8 (unsigned, as in WAV), 16 and 24.
+func EncodeFLAC(pcmData []byte, sampleRate, channels, bitDepth, compressionLevel int) ([]byte, error) {
+	if channels < 1 || channels > 8 {
+		return nil, fmt.Errorf("flac: unsupported channel count: %d", channels)
+	}
+	if bitDepth != 8 && bitDepth != 16 && bitDepth != 24 {
+		return nil, fmt.Errorf("flac: unsupported bit depth: %d", bitDepth)
+	}
+	if sampleRate <= 0 || sampleRate >= 1<<20 {
+		return nil, fmt.Errorf("flac: unsupported sample rate: %d", sampleRate)
+	}
+	if compressionLevel < MinFLACCompressionLevel || compressionLevel > MaxFLACCompressionLevel {
+		return nil, fmt.Errorf("flac: compression level must be between %d and %d, got %d",
+			MinFLACCompressionLevel, MaxFLACCompressionLevel, compressionLevel)
+	}
+
+	level := flacCompressionLevels[compressionLevel]
+	samples := deinterleavePCM(pcmData, channels, bitDepth)
+	totalSamples := 0
+	if len(samples) > 0 {
+		totalSamples = len(samples[0])
+	}
+
+	var frames [][]byte
+	minFrameSize, maxFrameSize := 0, 0
+	for start, frameNumber := 0, uint64(0); start < totalSamples; start, frameNumber = start+level.blockSize, frameNumber+1 {
+		end := min(start+level.blockSize, totalSamples)
+
+		block := make([][]int64, channels)
+		for channel := range block {
+			block[channel] = samples[channel][start:end]
+		}
+
+		frame := encodeFLACFrame(block, frameNumber, sampleRate, bitDepth, level)
+		frames = append(frames, frame)
+
+		if minFrameSize == 0 || len(frame) < minFrameSize {
+			minFrameSize = len(frame)
+		}
+		maxFrameSize = max(maxFrameSize, len(frame))
+	}
+
+	writer := &bitWriter{}
+	writer.writeBytes([]byte("fLaC"))
+
+	// STREAMINFO, the only (and therefore last) metadata block
+	writer.writeBits(1, 1)
+	writer.writeBits(0, 7)
+	writer.writeBits(34, 24)
+	writer.writeBits(uint64(level.blockSize), 16)
+	writer.writeBits(uint64(level.blockSize), 16)
+	writer.writeBits(uint64(minFrameSize), 24)
+	writer.writeBits(uint64(maxFrameSize), 24)
+	writer.writeBits(uint64(sampleRate), 20)
+	writer.writeBits(uint64(channels-1), 3)
+	writer.writeBits(uint64(bitDepth-1), 5)
+	writer.writeBits(uint64(totalSamples), 36)
+	signature := flacMD5(samples, bitDepth)
+	writer.writeBytes(signature[:])
+
+	for _, frame := range frames {
+		writer.writeBytes(frame)
+	}
+
+	return writer.bytes(), nil
+}
+
+func deinterleavePCM(pcmData []byte, channels, bitDepth int) [][]int64 {
+	bytesPerSample := bitDepth / 8
+	frameCount := len(pcmData) / (bytesPerSample * channels)
+
+	samples := make([][]int64, channels)
+	for channel := range samples {
+		samples[channel] = make([]int64, frameCount)
+	}
+
+	offset := 0
+	for i := 0; i < frameCount; i++ {
+		for channel := 0; channel < channels; channel++ {
+			var sample int64
+			switch bitDepth {
+			case 8:
+				sample = int64(pcmData[offset]) - 128
+			case 16:
+				sample = int64(int16(binary.LittleEndian.Uint16(pcmData[offset:])))
+			case 24:
+				value := int32(pcmData[offset]) | int32(pcmData[offset+1])<<8 | int32(pcmData[offset+2])<<16
+				sample = int64(value<<8) >> 8
+			}
+			samples[channel][i] = sample
+			offset += bytesPerSample
+		}
+	}
+
+	return samples
+}
+
+// flacMD5 hashes the signed, interleaved samples the way decoders verify them
+func flacMD5(samples [][]int64, bitDepth int) [16]byte {
+	if len(samples) == 0 {
+		return md5.Sum(nil)
+	}
+
+	bytesPerSample := bitDepth / 8
+	raw := make([]byte, 0, len(samples)*len(samples[0])*bytesPerSample)
+	for i := range samples[0] {
+		for channel := range samples {
+			sample := samples[channel][i]
+			for b := 0; b < bytesPerSample; b++ {
+				raw = append(raw, byte(sample>>(8*b)))
+			}
+		}
+	}
+
+	return md5.Sum(raw)
+}
+
+//</editor-fold>
+
+// <editor-fold desc="Frames">
+
+func encodeFLACFrame(block [][]int64, frameNumber uint64, sampleRate, bitDepth int, level flacCompressionLevel) []byte {
+	blockSize := len(block[0])
+	assignment := flacChannelIndependent
+	var subframes []*flacSubframe
+
+	if len(block) == 2 && level.stereo {
+		left, right := block[0], block[1]
+		mid := make([]int64, blockSize)
+		side := make([]int64, blockSize)
+		for i := range left {
+			mid[i] = (left[i] + right[i]) >> 1
+			side[i] = left[i] - right[i]
+		}
+
+		leftSub := encodeFLACSubframe(left, bitDepth, level)
+		rightSub := encodeFLACSubframe(right, bitDepth, level)
+		midSub := encodeFLACSubframe(mid, bitDepth, level)
+		sideSub := encodeFLACSubframe(side, bitDepth+1, level)
+
+		candidates := [...][2]*flacSubframe{
+			flacChannelIndependent: {leftSub, rightSub},
+			flacChannelLeftSide:    {leftSub, sideSub},
+			flacChannelSideRight:   {sideSub, rightSub},
+			flacChannelMidSide:     {midSub, sideSub},
+		}
+		for candidate, pair := range candidates {
+			if pair[0].size+pair[1].size < candidates[assignment][0].size+candidates[assignment][1].size {
+				assignment = candidate
+			}
+		}
+		subframes = candidates[assignment][:]
+	} else {
+		for _, channel := range block {
+			subframes = append(subframes, encodeFLACSubframe(channel, bitDepth, level))
+		}
+	}
+
+	writer := &bitWriter{}
+
+	writer.writeBits(0xFFF8, 16) // sync code, fixed blocksize stream
+	blockSizeCode, blockSizeExtra, blockSizeExtraBits := flacBlockSizeCode(blockSize)
+	writer.writeBits(blockSizeCode, 4)
+	sampleRateCode, sampleRateExtra, sampleRateExtraBits := flacSampleRateCode(sampleRate)
+	writer.writeBits(sampleRateCode, 4)
+	if assignment == flacChannelIndependent {
+		writer.writeBits(uint64(len(block)-1), 4)
+	} else {
+		writer.writeBits(uint64(7+assignment), 4)
+	}
+	writer.writeBits(flacSampleSizeCode(bitDepth), 3)
+	writer.writeBits(0, 1)
+	writer.writeBytes(flacUTF8(frameNumber))
+	if blockSizeExtraBits > 0 {
+		writer.writeBits(blockSizeExtra, blockSizeExtraBits)
+	}
+	if sampleRateExtraBits > 0 {
+		writer.writeBits(sampleRateExtra, sampleRateExtraBits)
+	}
+	writer.writeBits(uint64(crc8(writer.bytes())), 8)
+
+	for _, subframe := range subframes {
+		writeFLACSubframe(writer, subframe)
+	}
+
+	writer.align()
+	writer.writeBits(uint64(crc16(writer.bytes())), 16)
+
+	return writer.bytes()
+}
+
+func flacBlockSizeCode(blockSize int) (uint64, uint64, uint) {
+	switch blockSize {
+	case 192:
+		return 1, 0, 0
+	case 576, 1152, 2304, 4608:
+		return uint64(2 + bits.TrailingZeros(uint(blockSize/576))), 0, 0
+	case 256, 512, 1024, 2048, 4096, 8192, 16384, 32768:
+		return uint64(8 + bits.TrailingZeros(uint(blockSize/256))), 0, 0
+	}
+
+	if blockSize <= 256 {
+		return 6, uint64(blockSize - 1), 8
+	}
+	return 7, uint64(blockSize - 1), 16
+}
+
+func flacSampleRateCode(sampleRate int) (uint64, uint64, uint) {
+	switch sampleRate {
+	case 88200:
+		return 1, 0, 0
+	case 176400:
+		return 2, 0, 0
+	case 192000:
+		return 3, 0, 0
+	case 8000:
+		return 4, 0, 0
+	case 16000:
+		return 5, 0, 0
+	case 22050:
+		return 6, 0, 0
+	case 24000:
+		return 7, 0, 0
+	case 32000:
+		return 8, 0, 0
+	case 44100:
+		return 9, 0, 0
+	case 48000:
+		return 10, 0, 0
+	case 96000:
+		return 11, 0, 0
+	}
+
+	if sampleRate%1000 == 0 && sampleRate/1000 < 256 {
+		return 12, uint64(sampleRate / 1000), 8
+	}
+	if sampleRate < 65536 {
+		return 13, uint64(sampleRate), 16
+	}
+	if sampleRate%10 == 0 && sampleRate/10 < 65536 {
+		return 14, uint64(sampleRate / 10), 16
+	}
+	return 0, 0, 0
+}
+
+func flacSampleSizeCode(bitDepth int) uint64 {
+	switch bitDepth {
+	case 8:
+		return 1
+	case 12:
+		return 2
+	case 16:
+		return 4
+	case 20:
+		return 5
+	case 24:
+		return 6
+	default:
+		return 0
+	}
+}
+
+// flacUTF8 encodes frame numbers using the extended UTF-8 scheme from the spec
+func flacUTF8(value uint64) []byte {
+	if value < 0x80 {
+		return []byte{byte(value)}
+	}
+
+	length := 2
+	for value >= 1<<(5*length+1) && length < 7 {
+		length++
+	}
+
+	encoded := make([]byte, length)
+	for i := length - 1; i > 0; i-- {
+		encoded[i] = 0x80 | byte(value&0x3F)
+		value >>= 6
+	}
+	encoded[0] = byte(0xFF<<(8-length)) | byte(value)
+
+	return encoded
+}
+
+//</editor-fold>
+
+// <editor-fold desc="Subframes">
+
+func encodeFLACSubframe(samples []int64, bitsPerSample int, level flacCompressionLevel) *flacSubframe {
+	constant := true
+	for _, sample := range samples[1:] {
+		if sample != samples[0] {
+			constant = false
+			break
+		}
+	}
+	if constant {
+		return &flacSubframe{
+			kind:          flacSubframeConstant,
+			bitsPerSample: bitsPerSample,
+			samples:       samples,
+			size:          flacSubframeHeaderLen + bitsPerSample,
+		}
+	}
+
+	best := &flacSubframe{
+		kind:          flacSubframeVerbatim,
+		bitsPerSample: bitsPerSample,
+		samples:       samples,
+		size:          flacSubframeHeaderLen + bitsPerSample*len(samples),
+	}
+
+	for order := 0; order <= flacMaxFixedOrder && order < len(samples); order++ {
+		candidate := &flacSubframe{
+			kind:          flacSubframeFixed,
+			bitsPerSample: bitsPerSample,
+			samples:       samples,
+			order:         order,
+			residual:      fixedResidual(samples, order),
+		}
+		candidate.size = flacSubframeHeaderLen + order*bitsPerSample +
+			chooseRicePartitions(candidate, len(samples), level.maxPartitionOrder)
+
+		if candidate.size < best.size {
+			best = candidate
+		}
+	}
+
+	if level.maxLPCOrder > 0 && len(samples) > level.maxLPCOrder {
+		if candidate := encodeLPCSubframe(samples, bitsPerSample, level); candidate != nil && candidate.size < best.size {
+			best = candidate
+		}
+	}
+
+	return best
+}
+
+func fixedResidual(samples []int64, order int) []int64 {
+	residual := make([]int64, len(samples)-order)
+	for i := order; i < len(samples); i++ {
+		var prediction int64
+		switch order {
+		case 1:
+			prediction = samples[i-1]
+		case 2:
+			prediction = 2*samples[i-1] - samples[i-2]
+		case 3:
+			prediction = 3*samples[i-1] - 3*samples[i-2] + samples[i-3]
+		case 4:
+			prediction = 4*samples[i-1] - 6*samples[i-2] + 4*samples[i-3] - samples[i-4]
+		}
+		residual[i-order] = samples[i] - prediction
+	}
+	return residual
+}
+
+func encodeLPCSubframe(samples []int64, bitsPerSample int, level flacCompressionLevel) *flacSubframe {
+	maxOrder := level.maxLPCOrder
+	autocorrelation := windowedAutocorrelation(samples, maxOrder)
+	if autocorrelation[0] == 0 {
+		return nil
+	}
+
+	coefficients, errors := levinsonDurbin(autocorrelation, maxOrder)
+	precision := qlpPrecision(len(samples), bitsPerSample)
+
+	orders := []int{estimateLPCOrder(errors, len(samples), bitsPerSample, precision)}
+	if level.exhaustive {
+		orders = orders[:0]
+		for order := 1; order <= maxOrder; order++ {
+			orders = append(orders, order)
+		}
+	}
+
+	var best *flacSubframe
+	for _, order := range orders {
+		orderPrecision := min(precision, 32-bitsPerSample-bits.Len(uint(order)))
+		quantized, shift, ok := quantizeLPC(coefficients[order-1], orderPrecision)
+		if !ok {
+			continue
+		}
+
+		candidate := &flacSubframe{
+			kind:          flacSubframeLPC,
+			bitsPerSample: bitsPerSample,
+			samples:       samples,
+			order:         order,
+			coefficients:  quantized,
+			precision:     orderPrecision,
+			shift:         shift,
+			residual:      lpcResidual(samples, quantized, shift),
+		}
+		candidate.size = flacSubframeHeaderLen + order*bitsPerSample + 4 + 5 + order*orderPrecision +
+			chooseRicePartitions(candidate, len(samples), level.maxPartitionOrder)
+
+		if best == nil || candidate.size < best.size {
+			best = candidate
+		}
+	}
+
+	return best
+}
+
+// windowedAutocorrelation applies a Welch window before computing the autocorrelation
+func windowedAutocorrelation(samples []int64, maxOrder int) []float64 {
+	n := len(samples)
+	windowed := make([]float64, n)
+	half := float64(n-1) / 2
+	for i, sample := range samples {
+		position := (float64(i) - half) / (half + 1)
+		windowed[i] = float64(sample) * (1 - position*position)
+	}
+
+	autocorrelation := make([]float64, maxOrder+1)
+	for lag := range autocorrelation {
+		var sum float64
+		for i := lag; i < n; i++ {
+			sum += windowed[i] * windowed[i-lag]
+		}
+		autocorrelation[lag] = sum
+	}
+	return autocorrelation
+}
+
+// levinsonDurbin returns predictor coefficients and the prediction error for each order 1..maxOrder
+func levinsonDurbin(autocorrelation []float64, maxOrder int) ([][]float64, []float64) {
+	coefficients := make([][]float64, maxOrder)
+	errors := make([]float64, maxOrder)
+
+	lpc := make([]float64, maxOrder)
+	err := autocorrelation[0]
+	for order := 0; order < maxOrder; order++ {
+		reflection := -autocorrelation[order+1]
+		for j := 0; j < order; j++ {
+			reflection -= lpc[j] * autocorrelation[order-j]
+		}
+		if err != 0 {
+			reflection /= err
+		}
+
+		lpc[order] = reflection
+		for j := 0; j < order/2; j++ {
+			tmp := lpc[j]
+			lpc[j] += reflection * lpc[order-1-j]
+			lpc[order-1-j] += reflection * tmp
+		}
+		if order%2 == 1 {
+			lpc[order/2] += lpc[order/2] * reflection
+		}
+
+		err *= 1 - reflection*reflection
+
+		coefficients[order] = make([]float64, order+1)
+		for j := 0; j <= order; j++ {
+			coefficients[order][j] = -lpc[j]
+		}
+		errors[order] = err
+	}
+
+	return coefficients, errors
+}
+
+func estimateLPCOrder(errors []float64, blockSize, bitsPerSample, precision int) int {
+	bestOrder := 1
+	bestBits := math.Inf(1)
+	errorScale := 0.5 / float64(blockSize)
+
+	for i, err := range errors {
+		order := i + 1
+		bitsPerResidual := 0.0
+		if err > 0 {
+			bitsPerResidual = math.Max(0, 0.5*math.Log2(errorScale*err))
+		}
+
+		estimate := bitsPerResidual*float64(blockSize-order) + float64(order*(bitsPerSample+precision))
+		if estimate < bestBits {
+			bestBits = estimate
+			bestOrder = order
+		}
+	}
+
+	return bestOrder
+}
+
+func qlpPrecision(blockSize, bitsPerSample int) int {
+	var precision int
+	switch {
+	case blockSize <= 192:
+		precision = 7
+	case blockSize <= 384:
+		precision = 8
+	case blockSize <= 576:
+		precision = 9
+	case blockSize <= 1152:
+		precision = 10
+	case blockSize <= 2304:
+		precision = 11
+	case blockSize <= 4608:
+		precision = 12
+	default:
+		precision = 13
+	}
+
+	if bitsPerSample > 16 {
+		precision += 2
+	}
+	return min(precision, flacMaxQLPPrecision)
+}
+
+func quantizeLPC(coefficients []float64, precision int) ([]int32, int, bool) {
+	maxValue := int64(1)<<(precision-1) - 1
+	minValue := -int64(1) << (precision - 1)
+
+	var largest float64
+	for _, coefficient := range coefficients {
+		largest = math.Max(largest, math.Abs(coefficient))
+	}
+	if largest <= 0 {
+		return nil, 0, false
+	}
+
+	_, exponent := math.Frexp(largest)
+	shift := precision - 1 - exponent
+	if shift > flacMaxQLPShift {
+		shift = flacMaxQLPShift
+	}
+	if shift < 0 {
+		return nil, 0, false
+	}
+
+	quantized := make([]int32, len(coefficients))
+	var carried float64
+	for i, coefficient := range coefficients {
+		carried += coefficient * float64(int64(1)<<shift)
+		value := int64(math.Round(carried))
+		value = max(minValue, min(maxValue, value))
+		carried -= float64(value)
+		quantized[i] = int32(value)
+	}
+
+	return quantized, shift, true
+}
+
+func lpcResidual(samples []int64, coefficients []int32, shift int) []int64 {
+	order := len(coefficients)
+	residual := make([]int64, len(samples)-order)
+	for i := order; i < len(samples); i++ {
+		var prediction int64
+		for j, coefficient := range coefficients {
+			prediction += int64(coefficient) * samples[i-j-1]
+		}
+		residual[i-order] = samples[i] - prediction>>shift
+	}
+	return residual
+}
+
+// chooseRicePartitions picks the partition order and Rice parameters with the smallest estimated
+// size, stores them on the subframe and returns the size of the residual section in bits
+func chooseRicePartitions(subframe *flacSubframe, blockSize, maxPartitionOrder int) int {
+	order := subframe.order
+	folded := make([]uint64, len(subframe.residual))
+	for i, value := range subframe.residual {
+		folded[i] = zigzag(value)
+	}
+
+	bestSize := -1
+	for partitionOrder := 0; partitionOrder <= maxPartitionOrder; partitionOrder++ {
+		partitions := 1 << partitionOrder
+		if blockSize%partitions != 0 || blockSize/partitions <= order {
+			break
+		}
+
+		partitionSize := blockSize / partitions
+		params := make([]int, partitions)
+		extended := false
+		size := 2 + 4
+
+		offset := 0
+		for partition := 0; partition < partitions; partition++ {
+			count := partitionSize
+			if partition == 0 {
+				count -= order
+			}
+
+			var sum uint64
+			for _, value := range folded[offset : offset+count] {
+				sum += value
+			}
+			offset += count
+
+			param, partitionBits := bestRiceParam(sum, count)
+			params[partition] = param
+			if param > flacMaxRiceParam {
+				extended = true
+			}
+			size += partitionBits
+		}
+
+		if extended {
+			size += 5 * partitions
+		} else {
+			size += 4 * partitions
+		}
+
+		if bestSize < 0 || size < bestSize {
+			bestSize = size
+			subframe.partitionOrder = partitionOrder
+			subframe.riceParams = params
+			subframe.extendedRiceCodes = extended
+		}
+	}
+
+	return bestSize
+}
+
+func bestRiceParam(sum uint64, count int) (int, int) {
+	bestParam, bestBits := 0, -1
+	for param := 0; param <= flacMaxRiceParamExt; param++ {
+		size := count*(param+1) + int(sum>>param)
+		if bestBits < 0 || size < bestBits {
+			bestParam, bestBits = param, size
+		}
+	}
+	return bestParam, bestBits
+}
+
+func zigzag(value int64) uint64 {
+	return uint64((value << 1) ^ (value >> 63))
+}
+
+func writeFLACSubframe(writer *bitWriter, subframe *flacSubframe) {
+	bitsPerSample := uint(subframe.bitsPerSample)
+
+	writer.writeBits(0, 1)
+	switch subframe.kind {
+	case flacSubframeConstant:
+		writer.writeBits(0, 6)
+		writer.writeBits(0, 1)
+		writer.writeSigned(subframe.samples[0], bitsPerSample)
+
+	case flacSubframeVerbatim:
+		writer.writeBits(1, 6)
+		writer.writeBits(0, 1)
+		for _, sample := range subframe.samples {
+			writer.writeSigned(sample, bitsPerSample)
+		}
+
+	case flacSubframeFixed:
+		writer.writeBits(uint64(8|subframe.order), 6)
+		writer.writeBits(0, 1)
+		for _, sample := range subframe.samples[:subframe.order] {
+			writer.writeSigned(sample, bitsPerSample)
+		}
+		writeFLACResidual(writer, subframe)
+
+	case flacSubframeLPC:
+		writer.writeBits(uint64(32|(subframe.order-1)), 6)
+		writer.writeBits(0, 1)
+		for _, sample := range subframe.samples[:subframe.order] {
+			writer.writeSigned(sample, bitsPerSample)
+		}
+		writer.writeBits(uint64(subframe.precision-1), 4)
+		writer.writeSigned(int64(subframe.shift), 5)
+		for _, coefficient := range subframe.coefficients {
+			writer.writeSigned(int64(coefficient), uint(subframe.precision))
+		}
+		writeFLACResidual(writer, subframe)
+	}
+}
+
+func writeFLACResidual(writer *bitWriter, subframe *flacSubframe) {
+	paramBits := uint(4)
+	if subframe.extendedRiceCodes {
+		writer.writeBits(1, 2)
+		paramBits = 5
+	} else {
+		writer.writeBits(0, 2)
+	}
+	writer.writeBits(uint64(subframe.partitionOrder), 4)
+
+	blockSize := len(subframe.samples)
+	partitionSize := blockSize >> subframe.partitionOrder
+	offset := 0
+	for partition, param := range subframe.riceParams {
+		count := partitionSize
+		if partition == 0 {
+			count -= subframe.order
+		}
+
+		writer.writeBits(uint64(param), paramBits)
+		for _, value := range subframe.residual[offset : offset+count] {
+			folded := zigzag(value)
+			writer.writeUnary(folded >> param)
+			writer.writeBits(folded&(1<<param-1), uint(param))
+		}
+		offset += count
+	}
+}
+
+//</editor-fold>
+
+// <editor-fold desc="Bitstream">
+
+type bitWriter struct {
+	buffer      []byte
+	accumulator uint64
+	count       uint
+}
+
+func (w *bitWriter) writeBits(value uint64, n uint) {
+	for n > 32 {
+		n -= 32
+		w.writeBits(value>>n, 32)
+	}
+	if n == 0 {
+		return
+	}
+
+	w.accumulator = w.accumulator<<n | value&(1<<n-1)
+	w.count += n
+	for w.count >= 8 {
+		w.count -= 8
+		w.buffer = append(w.buffer, byte(w.accumulator>>w.count))
+	}
+}
+
+func (w *bitWriter) writeSigned(value int64, n uint) {
+	w.writeBits(uint64(value), n)
+}
+
+// writeUnary writes value zero bits followed by a terminating one bit
+func (w *bitWriter) writeUnary(value uint64) {
+	for value >= 32 {
+		w.writeBits(0, 32)
+		value -= 32
+	}
+	w.writeBits(1, uint(value)+1)
+}
+
+func (w *bitWriter) writeBytes(data []byte) {
+	if w.count == 0 {
+		w.buffer = append(w.buffer, data...)
+		return
+	}
+	for _, b := range data {
+		w.writeBits(uint64(b), 8)
+	}
+}
+
+func (w *bitWriter) align() {
+	if w.count > 0 {
+		w.writeBits(0, 8-w.count)
+	}
+}
+
+// bytes returns the completed bytes, excluding any partially written byte
+func (w *bitWriter) bytes() []byte {
+	return w.buffer
+}
+
+var crc8Table = func() [256]uint8 {
+	var table [256]uint8
+	for i := range table {
+		crc := uint8(i)
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}()
+
+var crc16Table = func() [256]uint16 {
+	var table [256]uint16
+	for i := range table {
+		crc := uint16(i) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x8005
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}()
+
+func crc8(data []byte) uint8 {
+	var crc uint8
+	for _, b := range data {
+		crc = crc8Table[crc^b]
+	}
+	return crc
+}
+
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc = crc<<8 ^ crc16Table[byte(crc>>8)^b]
+	}
+	return crc
+}
+
+//</editor-fold>
@@ -0,0 +1,122 @@
+package audio
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+// testSignal builds interleaved little-endian PCM of frames samples per channel, in the layout EncodeFLAC reads
+func testSignal(kind string, frames, channels, bitDepth int) []byte {
+	maxValue := int64(1)<<(bitDepth-1) - 1
+	random := rand.New(rand.NewPCG(uint64(frames), uint64(channels*bitDepth)))
+
+	var buffer bytes.Buffer
+	for frame := range frames {
+		for channel := range channels {
+			var sample int64
+			switch kind {
+			case "constant":
+				sample = maxValue / 3
+			case "noise":
+				sample = random.Int64N(2*maxValue+1) - maxValue
+			case "sine":
+				phase := 2 * math.Pi * 440 * float64(frame) / 44100
+				sample = int64(0.8 * float64(maxValue) * math.Sin(phase+float64(channel)))
+			}
+
+			switch bitDepth {
+			case 8:
+				buffer.WriteByte(byte(sample + 128))
+			case 16:
+				buffer.Write([]byte{byte(sample), byte(sample >> 8)})
+			case 24:
+				buffer.Write([]byte{byte(sample), byte(sample >> 8), byte(sample >> 16)})
+			}
+		}
+	}
+	return buffer.Bytes()
+}
+
+func TestEncodeFLACRoundTrip(t *testing.T) {
+	signals := []string{"silence", "constant", "noise", "sine"}
+
+	for _, channels := range []int{1, 2} {
+		for _, bitDepth := range []int{8, 16, 24} {
+			for _, signal := range signals {
+				for level := MinFLACCompressionLevel; level <= MaxFLACCompressionLevel; level++ {
+					name := fmt.Sprintf("%dch/%dbit/%s/level%d", channels, bitDepth, signal, level)
+					t.Run(name, func(t *testing.T) {
+						// Not a multiple of any block size, so the last frame is a short one
+						pcmData := testSignal(signal, 10007, channels, bitDepth)
+
+						encoded, err := EncodeFLAC(pcmData, 44100, channels, bitDepth, level)
+						if err != nil {
+							t.Fatalf("EncodeFLAC: %v", err)
+						}
+
+						decoded, sampleRate, decodedChannels, decodedBitDepth, err := decodeFLACToPCM(encoded)
+						if err != nil {
+							t.Fatalf("decodeFLACToPCM: %v", err)
+						}
+						if sampleRate != 44100 || decodedChannels != channels || decodedBitDepth != bitDepth {
+							t.Fatalf("decoded %d Hz, %d channels, %d bits, want 44100 Hz, %d channels, %d bits",
+								sampleRate, decodedChannels, decodedBitDepth, channels, bitDepth)
+						}
+						if !bytes.Equal(decoded, pcmData) {
+							t.Fatalf("decoded PCM differs from the input: %d bytes, want %d", len(decoded), len(pcmData))
+						}
+					})
+				}
+			}
+		}
+	}
+}
+
+func TestEncodeFLACSampleRates(t *testing.T) {
+	for _, sampleRate := range []int{8000, 16000, 22050, 24000, 44100, 48000, 96000, 11025, 37800} {
+		t.Run(fmt.Sprint(sampleRate), func(t *testing.T) {
+			pcmData := testSignal("sine", 5000, 1, 16)
+
+			encoded, err := EncodeFLAC(pcmData, sampleRate, 1, 16, DefaultFLACCompressionLevel)
+			if err != nil {
+				t.Fatalf("EncodeFLAC: %v", err)
+			}
+
+			decoded, decodedRate, _, _, err := decodeFLACToPCM(encoded)
+			if err != nil {
+				t.Fatalf("decodeFLACToPCM: %v", err)
+			}
+			if decodedRate != sampleRate {
+				t.Fatalf("decoded %d Hz, want %d", decodedRate, sampleRate)
+			}
+			if !bytes.Equal(decoded, pcmData) {
+				t.Fatal("decoded PCM differs from the input")
+			}
+		})
+	}
+}
+
+func TestEncodeFLACRejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		name                                  string
+		sampleRate, channels, bitDepth, level int
+	}{
+		{"no channels", 44100, 0, 16, 5},
+		{"too many channels", 44100, 9, 16, 5},
+		{"32-bit", 44100, 1, 32, 5},
+		{"no sample rate", 0, 1, 16, 5},
+		{"level too high", 44100, 1, 16, MaxFLACCompressionLevel + 1},
+		{"level too low", 44100, 1, 16, MinFLACCompressionLevel - 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := EncodeFLAC(make([]byte, 64), test.sampleRate, test.channels, test.bitDepth, test.level); err == nil {
+				t.Fatal("EncodeFLAC accepted invalid input")
+			}
+		})
+	}
+}
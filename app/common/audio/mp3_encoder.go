@@ -0,0 +1,969 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+const (
+	DefaultMP3Bitrate = 128
+	MinMP3Bitrate     = 32
+	MaxMP3Bitrate     = 320
+)
+
+// MP3 quality sets the noise allowed under the masking threshold and the lowpass, 10 being the most transparent
+const (
+	DefaultMP3Quality = 4.0
+	MinMP3Quality     = 0.0
+	MaxMP3Quality     = 10.0
+)
+
+const (
+	mp3GranuleSize       = 576
+	mp3FrameSamples      = 2 * mp3GranuleSize
+	mp3Subbands          = 32
+	mp3SubbandSamples    = 18
+	mp3ScalefactorBands  = 21
+	mp3MaxQuantized      = 15 + 8191
+	mp3MaxPart23Bits     = 4095
+	mp3MaxReservoirBytes = 511
+	mp3StepOffset        = 256
+
+	// Samples of delay introduced by the analysis filterbank and MDCT overlap
+	mp3EncoderDelay = 576 + 529
+)
+
+// MPEG-1 Layer III bitrates in kbps, by bitrate index
+var mp3Bitrates = [15]int{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320}
+
+// MPEG-1 sample rates, by sampling frequency index
+var mp3SampleRates = [3]int{44100, 48000, 32000}
+
+// Long block scalefactor band boundaries, by sampling frequency index
+var mp3BandBoundaries = [3][mp3ScalefactorBands + 2]int{
+	{0, 4, 8, 12, 16, 20, 24, 30, 36, 44, 52, 62, 74, 90, 110, 134, 162, 196, 238, 288, 342, 418, 576},
+	{0, 4, 8, 12, 16, 20, 24, 30, 36, 42, 50, 60, 72, 88, 106, 128, 156, 190, 230, 276, 330, 384, 576},
+	{0, 4, 8, 12, 16, 20, 24, 30, 36, 44, 54, 66, 82, 102, 126, 156, 194, 240, 296, 364, 448, 550, 576},
+}
+
+// Scalefactor bit widths {slen1, slen2} for each scalefac_compress value
+var mp3ScalefactorLengths = [16][2]int{
+	{0, 0}, {0, 1}, {0, 2}, {0, 3}, {3, 0}, {1, 1}, {1, 2}, {1, 3},
+	{2, 1}, {2, 2}, {2, 3}, {3, 1}, {3, 2}, {3, 3}, {4, 2}, {4, 3},
+}
+
+// Region0/region1 sizes, indexed by the scalefactor band in which the big values end
+var mp3RegionSplits = [mp3ScalefactorBands + 2][2]int{
+	{0, 0}, {0, 0}, {0, 0}, {0, 0}, {0, 0}, {0, 1}, {1, 1}, {1, 1}, {1, 2}, {2, 2}, {2, 3}, {2, 3},
+	{3, 4}, {3, 4}, {3, 4}, {4, 5}, {4, 5}, {4, 6}, {5, 6}, {5, 6}, {5, 7}, {6, 7}, {6, 7},
+}
+
+var mp3AliasCoefficients = [8]float64{-0.6, -0.535, -0.33, -0.185, -0.095, -0.041, -0.0142, -0.0037}
+
+type mp3Tables struct {
+	analysisWindow [512]float64
+	matrix         [mp3Subbands][64]float64
+	mdctWindow     [2 * mp3SubbandSamples]float64
+	mdctCosine     [mp3SubbandSamples][2 * mp3SubbandSamples]float64
+	aliasCS        [8]float64
+	aliasCA        [8]float64
+	quantizeStep   [2 * mp3StepOffset]float64
+	dequantizeStep [2 * mp3StepOffset]float64
+	power43        [mp3MaxQuantized + 1]float64
+}
+
+var mp3Lookup = func() *mp3Tables {
+	tables := &mp3Tables{}
+	for i, coefficient := range mp3WindowCoefficients {
+		tables.analysisWindow[i] = coefficient / 32
+	}
+	for i := range tables.matrix {
+		for k := range tables.matrix[i] {
+			tables.matrix[i][k] = math.Cos(float64((2*i+1)*(k-16)) * math.Pi / 64)
+		}
+	}
+	for n := range tables.mdctWindow {
+		tables.mdctWindow[n] = math.Sin(math.Pi / 36 * (float64(n) + 0.5))
+	}
+	for k := range tables.mdctCosine {
+		for n := range tables.mdctCosine[k] {
+			tables.mdctCosine[k][n] = math.Cos(math.Pi/72*float64(2*n+1+18)*float64(2*k+1)) / 9
+		}
+	}
+	for i, c := range mp3AliasCoefficients {
+		norm := math.Sqrt(1 + c*c)
+		tables.aliasCS[i] = 1 / norm
+		tables.aliasCA[i] = c / norm
+	}
+	for i := range tables.quantizeStep {
+		step := float64(i - mp3StepOffset - 210)
+		tables.quantizeStep[i] = math.Pow(2, -3*step/16)
+		tables.dequantizeStep[i] = math.Pow(2, step/4)
+	}
+	for i := range tables.power43 {
+		tables.power43[i] = math.Pow(float64(i), 4.0/3.0)
+	}
+	return tables
+}()
+
+// mp3NoiseModel holds the per-line noise energy allowed when choosing quantizer step sizes
+type mp3NoiseModel struct {
+	signalToNoise float64
+	masking       float64
+	threshold     float64
+}
+
+func newMP3NoiseModel(quality float64) mp3NoiseModel {
+	return mp3NoiseModel{
+		signalToNoise: math.Pow(10, -(12+2*quality)/10),
+		masking:       math.Pow(10, -(28+3*quality)/10),
+		threshold:     math.Pow(10, -(80+2*quality)/10),
+	}
+}
+
+type mp3Granule struct {
+	quantized        [mp3GranuleSize]int
+	scalefactors     [mp3ScalefactorBands]int
+	globalGain       int
+	scalefacScale    int
+	scalefacCompress int
+	bigValues        int
+	count1End        int
+	tableSelect      [3]int
+	region0Count     int
+	region1Count     int
+	count1Table      int
+	part2Bits        int
+	part23Bits       int
+}
+
+type mp3Encoder struct {
+	sampleRate  int
+	rateIndex   int
+	channels    int
+	bands       []int
+	lowpassLine int
+
+	history  [][512]float64
+	overlap  [][mp3Subbands][mp3SubbandSamples]float64
+	subbands [mp3Subbands][mp3SubbandSamples]float64
+}
+
+// <editor-fold desc="Encoder">
+
+// EncodeMP3 encodes interleaved little-endian PCM as an MPEG-1 Layer III stream.
+// With vbr unset the stream is constant bitrate at the given kbps (0 uses DefaultMP3Bitrate); with vbr set,
+// each frame takes the smallest bitrate that holds the given quality (0-10), or a quality is picked to average
+// the given kbps when it is non-zero. Sample rates other than 32, 44.1 and 48 kHz are resampled.
+func EncodeMP3(pcmData []byte, sampleRate, channels, bitDepth int, quality float64, bitrate int, vbr bool) ([]byte, error) {
+	if channels != 1 && channels != 2 {
+		return nil, fmt.Errorf("mp3: unsupported channel count: %d", channels)
+	}
+	if bitDepth != 8 && bitDepth != 16 && bitDepth != 24 {
+		return nil, fmt.Errorf("mp3: unsupported bit depth: %d", bitDepth)
+	}
+	if sampleRate <= 0 {
+		return nil, fmt.Errorf("mp3: unsupported sample rate: %d", sampleRate)
+	}
+	if quality < MinMP3Quality || quality > MaxMP3Quality {
+		return nil, fmt.Errorf("mp3: quality must be between %g and %g, got %g", MinMP3Quality, MaxMP3Quality, quality)
+	}
+	if bitrate < 0 || bitrate > MaxMP3Bitrate || (bitrate > 0 && bitrate < MinMP3Bitrate) {
+		return nil, fmt.Errorf("mp3: bitrate must be between %d and %d kbps, got %d", MinMP3Bitrate, MaxMP3Bitrate, bitrate)
+	}
+
+	samples := deinterleavePCM(pcmData, channels, bitDepth)
+	scale := 1 / float64(int64(1)<<(bitDepth-1))
+	normalized := make([][]float64, channels)
+	for channel := range samples {
+		normalized[channel] = make([]float64, len(samples[channel]))
+		for i, sample := range samples[channel] {
+			normalized[channel][i] = float64(sample) * scale
+		}
+	}
+
+	outputRate := mp3OutputSampleRate(sampleRate)
+	if outputRate != sampleRate {
+		for channel := range normalized {
+			normalized[channel] = resampleLinear(normalized[channel], sampleRate, outputRate)
+		}
+	}
+
+	cbrBitrate := 0
+	lowpass := 6000 + 1300*quality
+	if !vbr {
+		cbrBitrate = nearestMP3Bitrate(bitrate)
+		lowpass = float64(3000 + 130*cbrBitrate/channels)
+	} else if bitrate > 0 {
+		lowpass = float64(3000 + 130*bitrate/channels)
+	}
+	lowpass = min(lowpass, 19000, float64(sampleRate)/2)
+
+	encoder := newMP3Encoder(outputRate, channels, lowpass)
+	spectra := encoder.analyze(normalized)
+	duration := float64(len(normalized[0])) / float64(outputRate)
+
+	if cbrBitrate > 0 {
+		return encoder.encode(spectra, newMP3NoiseModel(MaxMP3Quality), cbrBitrate), nil
+	}
+
+	if bitrate > 0 && duration > 0 {
+		// Bisect the quality scale until the average bitrate settles just below the target
+		var best []byte
+		low, high := MinMP3Quality, MaxMP3Quality
+		for i := 0; i < 7; i++ {
+			candidate := (low + high) / 2
+			stream := encoder.encode(spectra, newMP3NoiseModel(candidate), 0)
+			if float64(len(stream)*8)/duration/1000 > float64(bitrate) {
+				high = candidate
+				continue
+			}
+			low = candidate
+			best = stream
+		}
+		if best == nil {
+			best = encoder.encode(spectra, newMP3NoiseModel(low), 0)
+		}
+		return best, nil
+	}
+
+	return encoder.encode(spectra, newMP3NoiseModel(quality), 0), nil
+}
+
+func newMP3Encoder(sampleRate, channels int, lowpass float64) *mp3Encoder {
+	rateIndex := 0
+	for i, rate := range mp3SampleRates {
+		if rate == sampleRate {
+			rateIndex = i
+		}
+	}
+
+	return &mp3Encoder{
+		sampleRate:  sampleRate,
+		rateIndex:   rateIndex,
+		channels:    channels,
+		bands:       mp3BandBoundaries[rateIndex][:],
+		lowpassLine: min(mp3GranuleSize, int(lowpass/(float64(sampleRate)/2)*mp3GranuleSize)),
+		history:     make([][512]float64, channels),
+		overlap:     make([][mp3Subbands][mp3SubbandSamples]float64, channels),
+	}
+}
+
+// encode packs the analysed granules into frames. A non-zero bitrate gives a constant bitrate stream,
+// otherwise every frame uses the smallest bitrate that holds its data and a Xing header is prepended.
+func (e *mp3Encoder) encode(spectra [][][]float64, model mp3NoiseModel, bitrate int) []byte {
+	frameCount := len(spectra) / 2
+	sideInfoSize := 32
+	if e.channels == 1 {
+		sideInfoSize = 17
+	}
+
+	var output []byte
+	var mainData []byte
+	var frameOffsets, slotStarts, frameSlots []int
+	slotStart := 0
+	padRemainder := 0
+
+	for frame := 0; frame < frameCount; frame++ {
+		granules := make([][]*mp3Granule, 2)
+		wanted := 0
+		for gr := range granules {
+			granules[gr] = make([]*mp3Granule, e.channels)
+			for ch := range granules[gr] {
+				granule := e.allocate(spectra[2*frame+gr][ch], model)
+				granules[gr][ch] = granule
+				wanted += granule.part23Bits
+			}
+		}
+
+		reservoir := min(slotStart-len(mainData), mp3MaxReservoirBytes)
+		wantedBytes := (wanted + 7) / 8
+
+		bitrateIndex, padding := 0, 0
+		if bitrate > 0 {
+			for i, rate := range mp3Bitrates {
+				if rate == bitrate {
+					bitrateIndex = i
+				}
+			}
+			padRemainder += 144 * bitrate * 1000 % e.sampleRate
+			if padRemainder >= e.sampleRate {
+				padRemainder -= e.sampleRate
+				padding = 1
+			}
+		} else {
+			bitrateIndex = len(mp3Bitrates) - 1
+			for i := 1; i < len(mp3Bitrates); i++ {
+				if reservoir+e.frameBytes(i, 0)-4-sideInfoSize >= wantedBytes {
+					bitrateIndex = i
+					break
+				}
+			}
+		}
+
+		slots := e.frameBytes(bitrateIndex, padding) - 4 - sideInfoSize
+		available := (reservoir + slots) * 8
+		if wanted > available {
+			// Share the frame's bits out in proportion to what each granule asked for
+			for gr := range granules {
+				for ch, granule := range granules[gr] {
+					budget := available * granule.part23Bits / wanted
+					granules[gr][ch] = e.fit(spectra[2*frame+gr][ch], granule, budget)
+				}
+			}
+		}
+
+		// Main data begins up to 511 bytes back inside earlier frames; anything older is padding
+		if gap := slotStart - len(mainData) - reservoir; gap > 0 {
+			mainData = append(mainData, make([]byte, gap)...)
+		}
+
+		writer := &bitWriter{}
+		for gr := range granules {
+			for _, granule := range granules[gr] {
+				writeMP3MainData(writer, granule, e.bands)
+			}
+		}
+		writer.align()
+		mainData = append(mainData, writer.bytes()...)
+
+		header := e.frameHeader(bitrateIndex, padding)
+		sideInfo := e.sideInfo(reservoir, granules)
+
+		frameOffsets = append(frameOffsets, len(output))
+		slotStarts = append(slotStarts, slotStart)
+		frameSlots = append(frameSlots, slots)
+		output = append(output, header...)
+		output = append(output, sideInfo...)
+		output = append(output, make([]byte, slots)...)
+		slotStart += slots
+	}
+
+	// Copy the main data into the slots following each frame's side info
+	for frame, offset := range frameOffsets {
+		start := offset + 4 + sideInfoSize
+		copy(output[start:start+frameSlots[frame]], mainData[min(slotStarts[frame], len(mainData)):])
+	}
+
+	if bitrate > 0 {
+		return output
+	}
+	return append(e.xingFrame(frameOffsets, len(output)), output...)
+}
+
+// frameBytes returns the size of a whole frame, header included
+func (e *mp3Encoder) frameBytes(bitrateIndex, padding int) int {
+	return 144*mp3Bitrates[bitrateIndex]*1000/e.sampleRate + padding
+}
+
+//</editor-fold>
+
+// <editor-fold desc="Analysis">
+
+// analyze runs the polyphase filterbank and MDCT over the whole signal, returning 576 lines per granule and channel.
+// The signal is padded so that the filterbank delay is flushed through the last frame.
+func (e *mp3Encoder) analyze(samples [][]float64) [][][]float64 {
+	total := len(samples[0])
+	frames := max(1, (total+mp3EncoderDelay+mp3FrameSamples-1)/mp3FrameSamples)
+	spectra := make([][][]float64, 2*frames)
+
+	for granule := range spectra {
+		spectra[granule] = make([][]float64, e.channels)
+		for channel := range spectra[granule] {
+			spectra[granule][channel] = e.analyzeGranule(samples[channel], channel, granule*mp3GranuleSize)
+		}
+	}
+
+	return spectra
+}
+
+func (e *mp3Encoder) analyzeGranule(samples []float64, channel, start int) []float64 {
+	lookup := mp3Lookup
+	history := &e.history[channel]
+
+	for slot := 0; slot < mp3SubbandSamples; slot++ {
+		copy(history[32:], history[:512-32])
+		for i := 0; i < 32; i++ {
+			position := start + slot*32 + i
+			value := 0.0
+			if position < len(samples) {
+				value = samples[position]
+			}
+			history[31-i] = value
+		}
+
+		var partial [64]float64
+		for i := range partial {
+			sum := 0.0
+			for j := 0; j < 8; j++ {
+				sum += lookup.analysisWindow[i+64*j] * history[i+64*j]
+			}
+			partial[i] = sum
+		}
+
+		for subband := 0; subband < mp3Subbands; subband++ {
+			sum := 0.0
+			for k, value := range partial {
+				sum += lookup.matrix[subband][k] * value
+			}
+			// Odd subbands are frequency inverted, matching the decoder
+			if subband%2 == 1 && slot%2 == 1 {
+				sum = -sum
+			}
+			e.subbands[subband][slot] = sum
+		}
+	}
+
+	lines := make([]float64, mp3GranuleSize)
+	var block [2 * mp3SubbandSamples]float64
+	for subband := 0; subband < mp3Subbands; subband++ {
+		previous := &e.overlap[channel][subband]
+		for n := 0; n < mp3SubbandSamples; n++ {
+			block[n] = previous[n] * lookup.mdctWindow[n]
+			block[n+mp3SubbandSamples] = e.subbands[subband][n] * lookup.mdctWindow[n+mp3SubbandSamples]
+		}
+		*previous = e.subbands[subband]
+
+		for k := 0; k < mp3SubbandSamples; k++ {
+			sum := 0.0
+			for n, value := range block {
+				sum += value * lookup.mdctCosine[k][n]
+			}
+			lines[subband*mp3SubbandSamples+k] = sum
+		}
+	}
+
+	// Butterflies that the decoder's alias reduction undoes
+	for subband := 1; subband < mp3Subbands; subband++ {
+		for i := 0; i < 8; i++ {
+			lower := subband*mp3SubbandSamples - 1 - i
+			upper := subband*mp3SubbandSamples + i
+			a, b := lines[lower], lines[upper]
+			lines[lower] = a*lookup.aliasCS[i] + b*lookup.aliasCA[i]
+			lines[upper] = b*lookup.aliasCS[i] - a*lookup.aliasCA[i]
+		}
+	}
+
+	for i := e.lowpassLine; i < mp3GranuleSize; i++ {
+		lines[i] = 0
+	}
+
+	return lines
+}
+
+// mp3OutputSampleRate picks the MPEG-1 rate to encode at, preferring exact multiples of the source rate
+func mp3OutputSampleRate(sampleRate int) int {
+	ascending := []int{32000, 44100, 48000}
+	for _, rate := range ascending {
+		if rate == sampleRate {
+			return rate
+		}
+	}
+	for _, rate := range ascending {
+		if rate%sampleRate == 0 {
+			return rate
+		}
+	}
+	for _, rate := range ascending {
+		if rate >= sampleRate {
+			return rate
+		}
+	}
+	return 48000
+}
+
+func nearestMP3Bitrate(bitrate int) int {
+	if bitrate == 0 {
+		return DefaultMP3Bitrate
+	}
+	nearest := mp3Bitrates[1]
+	for _, rate := range mp3Bitrates[1:] {
+		if abs(rate-bitrate) < abs(nearest-bitrate) {
+			nearest = rate
+		}
+	}
+	return nearest
+}
+
+func resampleLinear(samples []float64, sourceRate, targetRate int) []float64 {
+	if len(samples) == 0 {
+		return samples
+	}
+
+	ratio := float64(sourceRate) / float64(targetRate)
+	output := make([]float64, int(float64(len(samples))/ratio))
+	for i := range output {
+		position := float64(i) * ratio
+		index := int(position)
+		fraction := position - float64(index)
+		next := min(index+1, len(samples)-1)
+		output[i] = samples[index]*(1-fraction) + samples[next]*fraction
+	}
+
+	return output
+}
+
+//</editor-fold>
+
+// <editor-fold desc="Quantization">
+
+// allocate quantizes a granule as coarsely as the noise model allows: each scalefactor band gets the largest
+// step whose noise stays under its allowance, and scalefactors bring the finer bands below the global gain.
+func (e *mp3Encoder) allocate(lines []float64, model mp3NoiseModel) *mp3Granule {
+	lookup := mp3Lookup
+	granule := &mp3Granule{}
+
+	var magnitudes, powers [mp3GranuleSize]float64
+	for i, line := range lines {
+		magnitudes[i] = math.Abs(line)
+		powers[i] = math.Pow(magnitudes[i], 0.75)
+	}
+
+	var energies [mp3ScalefactorBands + 1]float64
+	loudest := 0.0
+	for band := range energies {
+		for i := e.bands[band]; i < e.bands[band+1]; i++ {
+			energies[band] += lines[i] * lines[i]
+		}
+		loudest = max(loudest, energies[band]/float64(e.bands[band+1]-e.bands[band]))
+	}
+
+	var steps, minimumSteps [mp3ScalefactorBands + 1]int
+	for band := range steps {
+		start, end := e.bands[band], e.bands[band+1]
+		width := float64(end - start)
+		allowed := max(energies[band]*model.signalToNoise, loudest*model.masking*width, model.threshold*width)
+
+		peak := 0.0
+		for i := start; i < end; i++ {
+			peak = max(peak, powers[i])
+		}
+		minimum := -mp3StepOffset
+		for low, high := -mp3StepOffset, 255; low <= high; {
+			middle := (low + high) / 2
+			if peak*lookup.quantizeStep[middle+mp3StepOffset]+0.4054 < mp3MaxQuantized+1 {
+				minimum, high = middle, middle-1
+			} else {
+				low = middle + 1
+			}
+		}
+		minimumSteps[band] = minimum
+
+		step := minimum
+		for low, high := minimum, 255; low <= high; {
+			middle := (low + high) / 2
+			if bandNoise(magnitudes[start:end], powers[start:end], middle) <= allowed {
+				step, low = middle, middle+1
+			} else {
+				high = middle - 1
+			}
+		}
+		steps[band] = step
+	}
+
+	globalGain := 0
+	for _, step := range steps {
+		globalGain = max(globalGain, step)
+	}
+	globalGain = min(globalGain, steps[mp3ScalefactorBands])
+
+	// Prefer fine scalefactor steps, falling back to coarse ones and then to a lower global gain
+	for scale := 0; scale <= 1; scale++ {
+		multiplier := 2 << scale
+		limit := globalGain
+		for band := 0; band < mp3ScalefactorBands; band++ {
+			limit = min(limit, steps[band]+multiplier*mp3MaxScalefactor(band))
+		}
+		if limit == globalGain || scale == 1 {
+			granule.scalefacScale = scale
+			globalGain = limit
+			break
+		}
+	}
+	for _, minimum := range minimumSteps {
+		globalGain = max(globalGain, minimum)
+	}
+	granule.globalGain = min(max(globalGain, 0), 255)
+
+	multiplier := 2 << granule.scalefacScale
+	for band := 0; band < mp3ScalefactorBands; band++ {
+		needed := (granule.globalGain - steps[band] + multiplier - 1) / multiplier
+		limit := (granule.globalGain - minimumSteps[band]) / multiplier
+		granule.scalefactors[band] = min(max(needed, 0), max(limit, 0), mp3MaxScalefactor(band))
+	}
+
+	e.quantize(granule, powers[:], lines)
+	if granule.part23Bits > mp3MaxPart23Bits {
+		return e.fit(lines, granule, mp3MaxPart23Bits)
+	}
+	return granule
+}
+
+// fit raises the global gain of an allocated granule until it codes in at most budget bits
+func (e *mp3Encoder) fit(lines []float64, granule *mp3Granule, budget int) *mp3Granule {
+	budget = min(budget, mp3MaxPart23Bits)
+
+	var powers [mp3GranuleSize]float64
+	for i, line := range lines {
+		powers[i] = math.Pow(math.Abs(line), 0.75)
+	}
+
+	candidate := *granule
+	candidate.globalGain = 255
+	e.quantize(&candidate, powers[:], lines)
+	if candidate.part23Bits > budget {
+		candidate.scalefactors = [mp3ScalefactorBands]int{}
+		e.quantize(&candidate, powers[:], lines)
+		return &candidate
+	}
+
+	best := candidate
+	for low, high := granule.globalGain, 254; low <= high; {
+		middle := (low + high) / 2
+		candidate = *granule
+		candidate.globalGain = middle
+		e.quantize(&candidate, powers[:], lines)
+		if candidate.part23Bits <= budget {
+			best, high = candidate, middle-1
+		} else {
+			low = middle + 1
+		}
+	}
+
+	return &best
+}
+
+// quantize fills in the quantized lines, scalefactor coding and Huffman layout for the granule's gains
+func (e *mp3Encoder) quantize(granule *mp3Granule, powers []float64, lines []float64) {
+	lookup := mp3Lookup
+	multiplier := 2 << granule.scalefacScale
+
+	for band := 0; band <= mp3ScalefactorBands; band++ {
+		step := granule.globalGain
+		if band < mp3ScalefactorBands {
+			step -= multiplier * granule.scalefactors[band]
+		}
+		factor := lookup.quantizeStep[step+mp3StepOffset]
+		for i := e.bands[band]; i < e.bands[band+1]; i++ {
+			value := min(int(powers[i]*factor+0.4054), mp3MaxQuantized)
+			if lines[i] < 0 {
+				value = -value
+			}
+			granule.quantized[i] = value
+		}
+	}
+
+	maxLow, maxHigh := 0, 0
+	for band := 0; band < mp3ScalefactorBands; band++ {
+		if band < 11 {
+			maxLow = max(maxLow, granule.scalefactors[band])
+		} else {
+			maxHigh = max(maxHigh, granule.scalefactors[band])
+		}
+	}
+	granule.part2Bits = math.MaxInt
+	for index, lengths := range mp3ScalefactorLengths {
+		if maxLow >= 1<<lengths[0] || maxHigh >= 1<<lengths[1] {
+			continue
+		}
+		if bits := 11*lengths[0] + 10*lengths[1]; bits < granule.part2Bits {
+			granule.part2Bits = bits
+			granule.scalefacCompress = index
+		}
+	}
+
+	granule.part23Bits = granule.part2Bits + layoutMP3Huffman(granule, e.bands)
+}
+
+func bandNoise(magnitudes, powers []float64, step int) float64 {
+	lookup := mp3Lookup
+	factor := lookup.quantizeStep[step+mp3StepOffset]
+	scale := lookup.dequantizeStep[step+mp3StepOffset]
+
+	noise := 0.0
+	for i, power := range powers {
+		value := min(int(power*factor+0.4054), mp3MaxQuantized)
+		difference := magnitudes[i] - lookup.power43[value]*scale
+		noise += difference * difference
+	}
+	return noise
+}
+
+func mp3MaxScalefactor(band int) int {
+	if band < 11 {
+		return 15
+	}
+	return 7
+}
+
+//</editor-fold>
+
+// <editor-fold desc="Huffman coding">
+
+// layoutMP3Huffman splits the quantized lines into big value regions and count1 quads,
+// picks a table for each region and returns the number of bits they code to
+func layoutMP3Huffman(granule *mp3Granule, bands []int) int {
+	values := &granule.quantized
+
+	end := mp3GranuleSize
+	for end > 1 && values[end-1] == 0 && values[end-2] == 0 {
+		end -= 2
+	}
+	granule.count1End = end
+	for end > 3 && abs(values[end-1]) <= 1 && abs(values[end-2]) <= 1 && abs(values[end-3]) <= 1 && abs(values[end-4]) <= 1 {
+		end -= 4
+	}
+	granule.bigValues = end / 2
+
+	bits := 0
+	quadBits := [2]int{}
+	for i := end; i < granule.count1End; i += 4 {
+		index := abs(values[i])<<3 | abs(values[i+1])<<2 | abs(values[i+2])<<1 | abs(values[i+3])
+		signs := abs(values[i]) + abs(values[i+1]) + abs(values[i+2]) + abs(values[i+3])
+		for table := range quadBits {
+			quadBits[table] += int(mp3QuadTables[table].lengths[index]) + signs
+		}
+	}
+	granule.count1Table = 0
+	if quadBits[1] < quadBits[0] {
+		granule.count1Table = 1
+	}
+	bits += quadBits[granule.count1Table]
+
+	bigEnd := granule.bigValues * 2
+	band := 0
+	for band < len(bands)-1 && bands[band] < bigEnd {
+		band++
+	}
+
+	region0 := mp3RegionSplits[band][0]
+	for region0 > 0 && bands[region0+1] > bigEnd {
+		region0--
+	}
+	region1 := mp3RegionSplits[band][1]
+	for region1 > 0 && bands[region0+region1+2] > bigEnd {
+		region1--
+	}
+	granule.region0Count = region0
+	granule.region1Count = region1
+
+	boundaries := [4]int{0, min(bands[region0+1], bigEnd), min(bands[region0+region1+2], bigEnd), bigEnd}
+	for region := 0; region < 3; region++ {
+		table, regionBits := chooseMP3Table(values[boundaries[region]:boundaries[region+1]])
+		granule.tableSelect[region] = table
+		bits += regionBits
+	}
+
+	return bits
+}
+
+func chooseMP3Table(values []int) (int, int) {
+	peak := 0
+	for _, value := range values {
+		peak = max(peak, abs(value))
+	}
+	if peak == 0 {
+		return 0, 0
+	}
+
+	bestTable, bestBits := 0, math.MaxInt
+	try := func(table int) {
+		if bits := mp3PairBits(values, table); bits < bestBits {
+			bestTable, bestBits = table, bits
+		}
+	}
+
+	if peak < 16 {
+		for table := 1; table < 16; table++ {
+			if dimension := mp3HuffmanTables[table].dimension; dimension > peak {
+				try(table)
+			}
+		}
+		return bestTable, bestBits
+	}
+
+	for _, family := range [][]int{{16, 17, 18, 19, 20, 21, 22, 23}, {24, 25, 26, 27, 28, 29, 30, 31}} {
+		for _, table := range family {
+			if 15+(1<<mp3HuffmanTables[table].linbits)-1 >= peak {
+				try(table)
+				break
+			}
+		}
+	}
+	return bestTable, bestBits
+}
+
+func mp3PairBits(values []int, table int) int {
+	huffman := &mp3HuffmanTables[table]
+	bits := 0
+	for i := 0; i+1 < len(values); i += 2 {
+		x, y := abs(values[i]), abs(values[i+1])
+		if huffman.linbits > 0 {
+			if x >= 15 {
+				bits += huffman.linbits
+				x = 15
+			}
+			if y >= 15 {
+				bits += huffman.linbits
+				y = 15
+			}
+		}
+		bits += int(huffman.lengths[x*huffman.dimension+y])
+		if x != 0 {
+			bits++
+		}
+		if y != 0 {
+			bits++
+		}
+	}
+	return bits
+}
+
+//</editor-fold>
+
+// <editor-fold desc="Bitstream">
+
+func (e *mp3Encoder) frameHeader(bitrateIndex, padding int) []byte {
+	mode := 0 // stereo
+	if e.channels == 1 {
+		mode = 3 // single channel
+	}
+
+	writer := &bitWriter{}
+	writer.writeBits(0x7FF, 11)
+	writer.writeBits(3, 2) // MPEG-1
+	writer.writeBits(1, 2) // Layer III
+	writer.writeBits(1, 1) // no CRC
+	writer.writeBits(uint64(bitrateIndex), 4)
+	writer.writeBits(uint64(e.rateIndex), 2)
+	writer.writeBits(uint64(padding), 1)
+	writer.writeBits(0, 1)
+	writer.writeBits(uint64(mode), 2)
+	writer.writeBits(0, 2)
+	writer.writeBits(0, 1)
+	writer.writeBits(1, 1) // original
+	writer.writeBits(0, 2)
+	return writer.bytes()
+}
+
+func (e *mp3Encoder) sideInfo(mainDataBegin int, granules [][]*mp3Granule) []byte {
+	writer := &bitWriter{}
+	writer.writeBits(uint64(mainDataBegin), 9)
+	if e.channels == 1 {
+		writer.writeBits(0, 5)
+	} else {
+		writer.writeBits(0, 3)
+	}
+	writer.writeBits(0, uint(4*e.channels)) // no scalefactor sharing between granules
+
+	for _, channelGranules := range granules {
+		for _, granule := range channelGranules {
+			writer.writeBits(uint64(granule.part23Bits), 12)
+			writer.writeBits(uint64(granule.bigValues), 9)
+			writer.writeBits(uint64(granule.globalGain), 8)
+			writer.writeBits(uint64(granule.scalefacCompress), 4)
+			writer.writeBits(0, 1) // long blocks only
+			for _, table := range granule.tableSelect {
+				writer.writeBits(uint64(table), 5)
+			}
+			writer.writeBits(uint64(granule.region0Count), 4)
+			writer.writeBits(uint64(granule.region1Count), 3)
+			writer.writeBits(0, 1) // no preemphasis
+			writer.writeBits(uint64(granule.scalefacScale), 1)
+			writer.writeBits(uint64(granule.count1Table), 1)
+		}
+	}
+	return writer.bytes()
+}
+
+func writeMP3MainData(writer *bitWriter, granule *mp3Granule, bands []int) {
+	lengths := mp3ScalefactorLengths[granule.scalefacCompress]
+	for band := 0; band < mp3ScalefactorBands; band++ {
+		if band < 11 {
+			writer.writeBits(uint64(granule.scalefactors[band]), uint(lengths[0]))
+		} else {
+			writer.writeBits(uint64(granule.scalefactors[band]), uint(lengths[1]))
+		}
+	}
+
+	values := &granule.quantized
+	bigEnd := granule.bigValues * 2
+	boundaries := [4]int{0, min(bands[granule.region0Count+1], bigEnd), min(bands[granule.region0Count+granule.region1Count+2], bigEnd), bigEnd}
+	for region := 0; region < 3; region++ {
+		table := granule.tableSelect[region]
+		if table == 0 {
+			continue
+		}
+		huffman := &mp3HuffmanTables[table]
+		for i := boundaries[region]; i < boundaries[region+1]; i += 2 {
+			x, y := abs(values[i]), abs(values[i+1])
+			codeX, codeY := min(x, 15), min(y, 15)
+			if huffman.linbits == 0 {
+				codeX, codeY = x, y
+			}
+			index := codeX*huffman.dimension + codeY
+			writer.writeBits(uint64(huffman.codes[index]), uint(huffman.lengths[index]))
+			if huffman.linbits > 0 && x >= 15 {
+				writer.writeBits(uint64(x-15), uint(huffman.linbits))
+			}
+			if x != 0 {
+				writer.writeBits(mp3SignBit(values[i]), 1)
+			}
+			if huffman.linbits > 0 && y >= 15 {
+				writer.writeBits(uint64(y-15), uint(huffman.linbits))
+			}
+			if y != 0 {
+				writer.writeBits(mp3SignBit(values[i+1]), 1)
+			}
+		}
+	}
+
+	quad := &mp3QuadTables[granule.count1Table]
+	for i := bigEnd; i < granule.count1End; i += 4 {
+		index := abs(values[i])<<3 | abs(values[i+1])<<2 | abs(values[i+2])<<1 | abs(values[i+3])
+		writer.writeBits(uint64(quad.codes[index]), uint(quad.lengths[index]))
+		for j := i; j < i+4; j++ {
+			if values[j] != 0 {
+				writer.writeBits(mp3SignBit(values[j]), 1)
+			}
+		}
+	}
+}
+
+func mp3SignBit(value int) uint64 {
+	if value < 0 {
+		return 1
+	}
+	return 0
+}
+
+// xingFrame builds the leading silent frame that tells players the frame count, length and seek table of a VBR stream
+func (e *mp3Encoder) xingFrame(frameOffsets []int, streamBytes int) []byte {
+	const bitrateIndex = 5 // 64 kbps, large enough for the tag at every sample rate
+	sideInfoSize := 32
+	if e.channels == 1 {
+		sideInfoSize = 17
+	}
+
+	frame := make([]byte, e.frameBytes(bitrateIndex, 0))
+	copy(frame, e.frameHeader(bitrateIndex, 0))
+	tag := frame[4+sideInfoSize:]
+	totalBytes := len(frame) + streamBytes
+
+	copy(tag, "Xing")
+	binary.BigEndian.PutUint32(tag[4:], 0x7) // frames, bytes and table of contents
+	binary.BigEndian.PutUint32(tag[8:], uint32(len(frameOffsets)))
+	binary.BigEndian.PutUint32(tag[12:], uint32(totalBytes))
+	for i := 0; i < 100; i++ {
+		offset := len(frame)
+		if len(frameOffsets) > 0 {
+			offset += frameOffsets[i*len(frameOffsets)/100]
+		}
+		tag[16+i] = byte(min(255, offset*256/totalBytes))
+	}
+
+	return frame
+}
+
+//</editor-fold>
@@ -0,0 +1,87 @@
+package audio
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEncodeMP3Decodes(t *testing.T) {
+	tests := []struct {
+		sampleRate, channels int
+		quality              float64
+		bitrate              int
+		vbr                  bool
+		minSNR               float64
+	}{
+		{44100, 1, 0, 0, false, 25},
+		{44100, 2, 0, DefaultMP3Bitrate, false, 25},
+		{48000, 2, 0, MaxMP3Bitrate, false, 25},
+		{32000, 1, 0, MinMP3Bitrate, false, 15},
+		{44100, 2, DefaultMP3Quality, 0, true, 18},
+		{48000, 1, 0, 160, true, 25},
+	}
+
+	for _, test := range tests {
+		mode := "cbr"
+		if test.vbr {
+			mode = "vbr"
+		}
+		name := fmt.Sprintf("%dHz/%dch/%s/q%g/%dkbps", test.sampleRate, test.channels, mode, test.quality, test.bitrate)
+		t.Run(name, func(t *testing.T) {
+			frames := test.sampleRate // one second
+			pcmData := testSweep(frames, test.sampleRate, test.channels)
+
+			encoded, err := EncodeMP3(pcmData, test.sampleRate, test.channels, 16, test.quality, test.bitrate, test.vbr)
+			if err != nil {
+				t.Fatalf("EncodeMP3: %v", err)
+			}
+
+			decodedPCM, sampleRate, channels, bitDepth, err := decodeMP3ToPCM(encoded)
+			if err != nil {
+				t.Fatalf("decodeMP3ToPCM: %v", err)
+			}
+			// The decoder always outputs stereo, a mono stream comes out on both channels
+			if sampleRate != test.sampleRate || channels != 2 || bitDepth != 16 {
+				t.Fatalf("decoded %d Hz, %d channels, %d bits, want %d Hz, 2 channels, 16 bits", sampleRate, channels, bitDepth, test.sampleRate)
+			}
+
+			original := floatChannels(pcmData, test.channels)
+			decoded := floatChannels(decodedPCM, channels)
+			for channel := range original {
+				if length := len(decoded[channel]); length < frames || length > frames+4*1152 {
+					t.Fatalf("channel %d decoded to %d samples, want about %d", channel, length, frames)
+				}
+				if snr, delay := alignedSNR(original[channel], decoded[channel], 4*1152); snr < test.minSNR {
+					t.Fatalf("channel %d: SNR %.1f dB at a delay of %d samples, want at least %g dB", channel, snr, delay, test.minSNR)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeMP3ResamplesOtherRates(t *testing.T) {
+	for _, sampleRate := range []int{16000, 22050, 24000} {
+		t.Run(fmt.Sprint(sampleRate), func(t *testing.T) {
+			pcmData := testSweep(sampleRate, sampleRate, 1)
+
+			encoded, err := EncodeMP3(pcmData, sampleRate, 1, 16, 0, 0, false)
+			if err != nil {
+				t.Fatalf("EncodeMP3: %v", err)
+			}
+
+			decodedPCM, decodedRate, channels, _, err := decodeMP3ToPCM(encoded)
+			if err != nil {
+				t.Fatalf("decodeMP3ToPCM: %v", err)
+			}
+			if decodedRate != 32000 && decodedRate != 44100 && decodedRate != 48000 {
+				t.Fatalf("decoded %d Hz, want an MPEG-1 sample rate", decodedRate)
+			}
+
+			// One second in, about one second out
+			frames := len(decodedPCM) / (2 * channels)
+			if frames < decodedRate || frames > decodedRate+4*1152 {
+				t.Fatalf("decoded %d samples at %d Hz, want about one second", frames, decodedRate)
+			}
+		})
+	}
+}
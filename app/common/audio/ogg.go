@@ -0,0 +1,130 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+const (
+	oggHeaderContinued = 0x01
+	oggHeaderFirstPage = 0x02
+	oggHeaderLastPage  = 0x04
+
+	oggMaxSegments = 255
+	oggPageTarget  = 4096
+)
+
+// oggWriter packs codec packets into a single logical Ogg bitstream
+type oggWriter struct {
+	output   bytes.Buffer
+	serial   uint32
+	sequence uint32
+
+	segments  []byte
+	body      []byte
+	granule   int64
+	firstPage bool
+	continued bool
+}
+
+func newOggWriter(serial uint32) *oggWriter {
+	return &oggWriter{
+		serial:    serial,
+		granule:   -1,
+		firstPage: true,
+	}
+}
+
+// writePacket appends a packet ending at the given granule position, spilling onto new pages as needed.
+// Full pages are only flushed once the next packet arrives, so the final page always has data to carry the end flag.
+func (w *oggWriter) writePacket(packet []byte, granule int64) {
+	if len(w.body) >= oggPageTarget {
+		w.flushPage(false)
+	}
+
+	remaining := packet
+	for {
+		if len(w.segments) == oggMaxSegments {
+			w.flushPage(false)
+			w.continued = true
+		}
+
+		size := min(len(remaining), 255)
+		w.segments = append(w.segments, byte(size))
+		w.body = append(w.body, remaining[:size]...)
+		remaining = remaining[size:]
+
+		if size < 255 {
+			break
+		}
+	}
+
+	w.granule = granule
+}
+
+// flushPage writes out the pending page, if any
+func (w *oggWriter) flushPage(last bool) {
+	if len(w.segments) == 0 && !last {
+		return
+	}
+
+	var headerType byte
+	if w.continued {
+		headerType |= oggHeaderContinued
+	}
+	if w.firstPage {
+		headerType |= oggHeaderFirstPage
+	}
+	if last {
+		headerType |= oggHeaderLastPage
+	}
+
+	page := make([]byte, 27, 27+len(w.segments)+len(w.body))
+	copy(page, "OggS")
+	page[4] = 0
+	page[5] = headerType
+	binary.LittleEndian.PutUint64(page[6:], uint64(w.granule))
+	binary.LittleEndian.PutUint32(page[14:], w.serial)
+	binary.LittleEndian.PutUint32(page[18:], w.sequence)
+	page[26] = byte(len(w.segments))
+	page = append(page, w.segments...)
+	page = append(page, w.body...)
+	binary.LittleEndian.PutUint32(page[22:], oggCRC(page))
+
+	w.output.Write(page)
+	w.sequence++
+	w.segments = w.segments[:0]
+	w.body = w.body[:0]
+	w.granule = -1
+	w.firstPage = false
+	w.continued = false
+}
+
+func (w *oggWriter) close() []byte {
+	w.flushPage(true)
+	return w.output.Bytes()
+}
+
+var oggCRCTable = func() [256]uint32 {
+	var table [256]uint32
+	for i := range table {
+		crc := uint32(i) << 24
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04C11DB7
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}()
+
+func oggCRC(data []byte) uint32 {
+	var crc uint32
+	for _, b := range data {
+		crc = crc<<8 ^ oggCRCTable[byte(crc>>24)^b]
+	}
+	return crc
+}
@@ -0,0 +1,957 @@
+package audio
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"math/rand/v2"
+)
+
+const (
+	DefaultOGGQuality = 4.0
+	MinOGGQuality     = 0.0
+	MaxOGGQuality     = 10.0
+)
+
+const (
+	vorbisShortBlockBits  = 8
+	vorbisLongBlockBits   = 11
+	vorbisBlockSize       = 1 << vorbisLongBlockBits
+	vorbisSpectrumSize    = vorbisBlockSize / 2
+	vorbisFloorMultiplier = 2
+	vorbisFloorRange      = 128
+	vorbisFloorRangeBits  = 10
+	vorbisFloorDimensions = 3
+	vorbisFloorYBits      = 7
+	vorbisPartitionSize   = 16
+	vorbisMaxResidue      = 2456
+	vorbisMaxCodeLength   = 24
+	vorbisVendor          = "Narration Studio"
+)
+
+// Codebooks, in setup header order
+const (
+	vorbisBookFloor = iota
+	vorbisBookClass
+	vorbisBookUnit
+	vorbisBookSmall
+	vorbisBookMedium
+	vorbisBookFine
+	vorbisBookMid
+	vorbisBookCoarse
+	vorbisBookCount
+)
+
+// Floor posts ordered coarse to fine so that each one is predicted from already coded neighbours
+var vorbisFloorPosts = []int{
+	56, 14, 256, 6, 28, 128, 512, 3, 10, 20, 40, 96, 192, 384, 768, 2, 4,
+	8, 12, 16, 24, 32, 48, 80, 112, 160, 224, 320, 448, 640, 896, 1, 64,
+}
+
+type vorbisResidueClass struct {
+	limit int
+	books []int
+}
+
+// Larger values are split into base-17 digits spread over the cascade passes
+var vorbisResidueClasses = []vorbisResidueClass{
+	{limit: 0},
+	{limit: 1, books: []int{vorbisBookUnit}},
+	{limit: 2, books: []int{vorbisBookSmall}},
+	{limit: 4, books: []int{vorbisBookMedium}},
+	{limit: 8, books: []int{vorbisBookFine}},
+	{limit: 144, books: []int{vorbisBookMid, vorbisBookFine}},
+	{limit: vorbisMaxResidue, books: []int{vorbisBookCoarse, vorbisBookMid, vorbisBookFine}},
+}
+
+type vorbisCodebook struct {
+	dimensions int
+	entries    int
+	values     int
+	minimum    int
+	delta      int
+	lengths    []int
+	codewords  []uint32
+}
+
+type vorbisChannelFrame struct {
+	unused   bool
+	floor    []int
+	residue  []int
+	classes  []int
+	passData [][][]int
+}
+
+type vorbisEncoder struct {
+	sampleRate int
+	channels   int
+	quality    float64
+	residueEnd int
+	books      []*vorbisCodebook
+
+	floorX      []int
+	floorSorted []int
+	regionStart []int
+	regionEnd   []int
+	floorValues [vorbisFloorRange][vorbisFloorRange]int
+	inverseDB   [256]float64
+}
+
+// <editor-fold desc="Encoder">
+
+// EncodeOGG encodes interleaved little-endian PCM as an Ogg Vorbis stream.
+// Quality ranges from 0 to 10; a non-zero bitrate (kbps) picks the quality that best matches it instead.
+func EncodeOGG(pcmData []byte, sampleRate, channels, bitDepth int, quality float64, bitrate int) ([]byte, error) {
+	if channels < 1 || channels > 255 {
+		return nil, fmt.Errorf("ogg: unsupported channel count: %d", channels)
+	}
+	if bitDepth != 8 && bitDepth != 16 && bitDepth != 24 {
+		return nil, fmt.Errorf("ogg: unsupported bit depth: %d", bitDepth)
+	}
+	if sampleRate <= 0 {
+		return nil, fmt.Errorf("ogg: unsupported sample rate: %d", sampleRate)
+	}
+	if quality < MinOGGQuality || quality > MaxOGGQuality {
+		return nil, fmt.Errorf("ogg: quality must be between %g and %g, got %g", MinOGGQuality, MaxOGGQuality, quality)
+	}
+	if bitrate < 0 {
+		return nil, fmt.Errorf("ogg: invalid bitrate: %d", bitrate)
+	}
+
+	samples := deinterleavePCM(pcmData, channels, bitDepth)
+	scale := 1 / float64(int64(1)<<(bitDepth-1))
+	normalized := make([][]float64, channels)
+	for channel := range samples {
+		normalized[channel] = make([]float64, len(samples[channel]))
+		for i, sample := range samples[channel] {
+			normalized[channel][i] = float64(sample) * scale
+		}
+	}
+
+	totalSamples := len(normalized[0])
+	spectra := vorbisAnalyze(normalized)
+	duration := float64(totalSamples) / float64(sampleRate)
+
+	encode := func(quality float64) ([][]byte, []byte, int) {
+		encoder := newVorbisEncoder(sampleRate, channels, quality)
+		packets, setup := encoder.encode(spectra)
+		size := 0
+		for _, packet := range packets {
+			size += len(packet)
+		}
+		return packets, setup, size
+	}
+
+	var packets [][]byte
+	var setup []byte
+	var size int
+	if bitrate > 0 && duration > 0 {
+		// Bisect the quality scale until the average bitrate settles just below the target
+		low, high := MinOGGQuality, MaxOGGQuality
+		for i := 0; i < 7; i++ {
+			candidate := (low + high) / 2
+			candidatePackets, candidateSetup, candidateSize := encode(candidate)
+			if float64(candidateSize*8)/duration/1000 > float64(bitrate) {
+				high = candidate
+				continue
+			}
+			low = candidate
+			packets, setup, size = candidatePackets, candidateSetup, candidateSize
+		}
+		if packets == nil {
+			packets, setup, size = encode(low)
+		}
+	} else {
+		packets, setup, size = encode(quality)
+	}
+
+	nominalBitrate := bitrate * 1000
+	if nominalBitrate == 0 && duration > 0 {
+		nominalBitrate = int(float64(size*8) / duration)
+	}
+
+	writer := newOggWriter(rand.Uint32())
+	writer.writePacket(vorbisIdentificationHeader(sampleRate, channels, nominalBitrate), 0)
+	writer.flushPage(false)
+	writer.writePacket(vorbisCommentHeader(), 0)
+	writer.writePacket(setup, 0)
+	writer.flushPage(false)
+
+	for i, packet := range packets {
+		granule := int64(i * vorbisSpectrumSize)
+		if i == len(packets)-1 {
+			granule = int64(totalSamples)
+		}
+		writer.writePacket(packet, granule)
+	}
+
+	return writer.close(), nil
+}
+
+func newVorbisEncoder(sampleRate, channels int, quality float64) *vorbisEncoder {
+	encoder := &vorbisEncoder{
+		sampleRate: sampleRate,
+		channels:   channels,
+		quality:    quality,
+		books:      vorbisCodebooks(),
+	}
+
+	for i := range encoder.inverseDB {
+		encoder.inverseDB[i] = math.Exp(math.Log(1.0649863e-07) * float64(255-i) / 255)
+	}
+
+	// Band limit rises with quality, capped at Nyquist
+	lowpass := 8000 + 1400*quality
+	binWidth := float64(sampleRate) / 2 / vorbisSpectrumSize
+	encoder.residueEnd = min(vorbisSpectrumSize, int(math.Ceil(lowpass/binWidth)))
+	encoder.residueEnd = (encoder.residueEnd + vorbisPartitionSize - 1) / vorbisPartitionSize * vorbisPartitionSize
+
+	encoder.floorX = append([]int{0, vorbisSpectrumSize}, vorbisFloorPosts...)
+	encoder.floorSorted = make([]int, len(encoder.floorX))
+	for i := range encoder.floorSorted {
+		encoder.floorSorted[i] = i
+	}
+	for i := 1; i < len(encoder.floorSorted); i++ {
+		for j := i; j > 0 && encoder.floorX[encoder.floorSorted[j]] < encoder.floorX[encoder.floorSorted[j-1]]; j-- {
+			encoder.floorSorted[j], encoder.floorSorted[j-1] = encoder.floorSorted[j-1], encoder.floorSorted[j]
+		}
+	}
+
+	// Each post measures the spectrum halfway out to its sorted neighbours
+	encoder.regionStart = make([]int, len(encoder.floorX))
+	encoder.regionEnd = make([]int, len(encoder.floorX))
+	for position, post := range encoder.floorSorted {
+		x := encoder.floorX[post]
+		start, end := x, x+1
+		if position > 0 {
+			start = (encoder.floorX[encoder.floorSorted[position-1]] + x + 1) / 2
+		}
+		if position < len(encoder.floorSorted)-1 {
+			end = (x + encoder.floorX[encoder.floorSorted[position+1]] + 1) / 2
+		}
+		encoder.regionStart[post] = min(start, vorbisSpectrumSize-1)
+		encoder.regionEnd[post] = max(min(end, vorbisSpectrumSize), encoder.regionStart[post]+1)
+	}
+
+	for predicted := 0; predicted < vorbisFloorRange; predicted++ {
+		for value := vorbisFloorRange - 1; value >= 0; value-- {
+			encoder.floorValues[predicted][vorbisFloorDecodeValue(predicted, value)] = value
+		}
+	}
+
+	return encoder
+}
+
+func (e *vorbisEncoder) encode(spectra [][][]float64) ([][]byte, []byte) {
+	frames := make([][]vorbisChannelFrame, len(spectra))
+	for block, channels := range spectra {
+		frames[block] = make([]vorbisChannelFrame, len(channels))
+		for channel, spectrum := range channels {
+			frames[block][channel] = e.quantize(spectrum)
+		}
+	}
+
+	// First pass gathers symbol statistics so every codebook can be Huffman coded for this clip
+	books := e.books
+	counter := &vorbisSymbolCounter{counts: make([][]int, len(books))}
+	for i, book := range books {
+		counter.counts[i] = make([]int, book.entries)
+	}
+	for _, blockFrames := range frames {
+		e.writePacket(counter, blockFrames)
+	}
+	for i, book := range books {
+		book.lengths = huffmanLengths(counter.counts[i], vorbisMaxCodeLength)
+		book.codewords = vorbisCodewords(book.lengths)
+	}
+
+	packets := make([][]byte, len(frames))
+	for block, blockFrames := range frames {
+		writer := &vorbisPacketWriter{books: books}
+		e.writePacket(writer, blockFrames)
+		packets[block] = writer.bytes()
+	}
+
+	return packets, e.setupHeader(books)
+}
+
+// quantize computes the floor curve for a spectrum and the integer residue relative to it
+func (e *vorbisEncoder) quantize(spectrum []float64) vorbisChannelFrame {
+	// Noise may sit a fixed number of decibels below each band, and bands far below the loudest one are left to masking
+	snr := 2 + 2.2*e.quality
+	factor := math.Pow(10, -snr/20)
+	masking := math.Pow(10, -(40+3*e.quality)/20)
+	threshold := math.Pow(10, -(96+2*e.quality)/20)
+
+	levels := make([]float64, len(e.floorX))
+	var loudest float64
+	for post := range e.floorX {
+		var energy float64
+		for _, value := range spectrum[e.regionStart[post]:e.regionEnd[post]] {
+			energy += value * value
+		}
+		levels[post] = math.Sqrt(energy / float64(e.regionEnd[post]-e.regionStart[post]))
+		loudest = math.Max(loudest, levels[post])
+	}
+	threshold = math.Max(threshold, loudest*masking)
+
+	targets := make([]int, len(e.floorX))
+	for post, level := range levels {
+		amplitude := math.Max(level*factor, threshold)
+		index := math.Log(amplitude/e.inverseDB[0]) / math.Log(e.inverseDB[1]/e.inverseDB[0]) / vorbisFloorMultiplier
+		targets[post] = max(0, min(vorbisFloorRange-1, int(math.Round(index))))
+	}
+
+	floorValues := make([]int, len(e.floorX))
+	finalY := make([]int, len(e.floorX))
+	used := make([]bool, len(e.floorX))
+	floorValues[0], floorValues[1] = targets[0], targets[1]
+	finalY[0], finalY[1] = targets[0], targets[1]
+	used[0], used[1] = true, true
+
+	for post := 2; post < len(e.floorX); post++ {
+		low, high := vorbisNeighbors(e.floorX, post)
+		predicted := vorbisRenderPoint(e.floorX[low], finalY[low], e.floorX[high], finalY[high], e.floorX[post])
+
+		if abs(targets[post]-predicted) <= 1 {
+			finalY[post] = predicted
+			continue
+		}
+
+		floorValues[post] = e.floorValues[predicted][targets[post]]
+		finalY[post] = vorbisFloorDecodeValue(predicted, floorValues[post])
+		used[low], used[high], used[post] = true, true, true
+	}
+
+	curve := make([]float64, vorbisSpectrumSize)
+	lastX, lastY := 0, finalY[0]*vorbisFloorMultiplier
+	for _, post := range e.floorSorted[1:] {
+		if !used[post] {
+			continue
+		}
+		y := finalY[post] * vorbisFloorMultiplier
+		e.renderLine(lastX, lastY, e.floorX[post], y, curve)
+		lastX, lastY = e.floorX[post], y
+	}
+
+	frame := vorbisChannelFrame{
+		floor:   floorValues,
+		residue: make([]int, e.residueEnd),
+		unused:  true,
+	}
+	for i := range frame.residue {
+		value := quantizeDeadzone(spectrum[i] / curve[i])
+		frame.residue[i] = max(-vorbisMaxResidue, min(vorbisMaxResidue, value))
+		if value != 0 {
+			frame.unused = false
+		}
+	}
+
+	partitions := e.residueEnd / vorbisPartitionSize
+	frame.classes = make([]int, partitions)
+	frame.passData = make([][][]int, partitions)
+	for partition := range frame.classes {
+		values := frame.residue[partition*vorbisPartitionSize : (partition+1)*vorbisPartitionSize]
+		largest := 0
+		for _, value := range values {
+			largest = max(largest, abs(value))
+		}
+
+		class := 0
+		for class < len(vorbisResidueClasses)-1 && vorbisResidueClasses[class].limit < largest {
+			class++
+		}
+		frame.classes[partition] = class
+
+		passes := len(vorbisResidueClasses[class].books)
+		frame.passData[partition] = make([][]int, passes)
+		for pass := range frame.passData[partition] {
+			frame.passData[partition][pass] = make([]int, len(values))
+		}
+		for i, value := range values {
+			for pass := passes - 1; pass >= 0; pass-- {
+				digit := value
+				if pass > 0 {
+					digit = ((value+8)%17+17)%17 - 8
+					value = (value - digit) / 17
+				}
+				frame.passData[partition][pass][i] = digit
+			}
+		}
+	}
+
+	return frame
+}
+
+func (e *vorbisEncoder) renderLine(x0, y0, x1, y1 int, curve []float64) {
+	dy := y1 - y0
+	adx := x1 - x0
+	ady := abs(dy)
+	base := dy / adx
+	step := base + 1
+	if dy < 0 {
+		step = base - 1
+	}
+	ady -= abs(base) * adx
+
+	y := y0
+	fault := 0
+	curve[x0] = e.inverseDB[y]
+	for x := x0 + 1; x < x1; x++ {
+		fault += ady
+		if fault >= adx {
+			fault -= adx
+			y += step
+		} else {
+			y += base
+		}
+		curve[x] = e.inverseDB[y]
+	}
+}
+
+func (e *vorbisEncoder) writePacket(writer vorbisSymbolWriter, frames []vorbisChannelFrame) {
+	writer.writeBits(0, 1) // audio packet
+	writer.writeBits(1, 1) // previous window is long
+	writer.writeBits(1, 1) // next window is long
+
+	for _, frame := range frames {
+		if frame.unused {
+			writer.writeBits(0, 1)
+			continue
+		}
+		writer.writeBits(1, 1)
+		writer.writeBits(uint32(frame.floor[0]), vorbisFloorYBits)
+		writer.writeBits(uint32(frame.floor[1]), vorbisFloorYBits)
+		for _, value := range frame.floor[2:] {
+			writer.writeSymbol(vorbisBookFloor, value)
+		}
+	}
+
+	partitions := e.residueEnd / vorbisPartitionSize
+	for pass := 0; pass < 3; pass++ {
+		for partition := 0; partition < partitions; {
+			if pass == 0 {
+				for _, frame := range frames {
+					if frame.unused {
+						continue
+					}
+					second := 0
+					if partition+1 < partitions {
+						second = frame.classes[partition+1]
+					}
+					writer.writeSymbol(vorbisBookClass, frame.classes[partition]*len(vorbisResidueClasses)+second)
+				}
+			}
+
+			for word := 0; word < 2 && partition < partitions; word++ {
+				for _, frame := range frames {
+					if frame.unused {
+						continue
+					}
+					classBooks := vorbisResidueClasses[frame.classes[partition]].books
+					if pass >= len(classBooks) {
+						continue
+					}
+
+					book := e.books[classBooks[pass]]
+					values := frame.passData[partition][pass]
+					for i := 0; i < len(values); i += book.dimensions {
+						entry, multiplier := 0, 1
+						for _, value := range values[i : i+book.dimensions] {
+							entry += (value + book.values/2) * multiplier
+							multiplier *= book.values
+						}
+						writer.writeSymbol(classBooks[pass], entry)
+					}
+				}
+				partition++
+			}
+		}
+	}
+}
+
+//</editor-fold>
+
+// <editor-fold desc="Analysis">
+
+// vorbisAnalyze windows the signal into overlapping long blocks and returns their MDCT spectra.
+// Block b covers samples [(b-1)*N, (b+1)*N) so the first decoded output starts at sample zero.
+func vorbisAnalyze(samples [][]float64) [][][]float64 {
+	total := len(samples[0])
+	blocks := max(1, (total+vorbisSpectrumSize-1)/vorbisSpectrumSize) + 1
+
+	window := make([]float64, vorbisBlockSize)
+	for i := range window {
+		inner := math.Sin((float64(i) + 0.5) / vorbisBlockSize * math.Pi)
+		window[i] = math.Sin(math.Pi / 2 * inner * inner)
+	}
+
+	spectra := make([][][]float64, blocks)
+	frame := make([]float64, vorbisBlockSize)
+	for block := range spectra {
+		spectra[block] = make([][]float64, len(samples))
+		start := (block - 1) * vorbisSpectrumSize
+		for channel, channelSamples := range samples {
+			for i := range frame {
+				frame[i] = 0
+				if position := start + i; position >= 0 && position < total {
+					frame[i] = channelSamples[position] * window[i]
+				}
+			}
+			spectra[block][channel] = mdct(frame)
+		}
+	}
+
+	return spectra
+}
+
+// mdct computes the forward transform of 2N samples into N coefficients, scaled to match the Vorbis inverse transform
+func mdct(input []float64) []float64 {
+	n := len(input) / 2
+	half := n / 2
+
+	// Fold into a DCT-IV input
+	folded := make([]float64, n)
+	for i := 0; i < half; i++ {
+		folded[i] = -input[n+half-1-i] - input[n+half+i]
+		folded[half+i] = input[i] - input[n-1-i]
+	}
+
+	// DCT-IV through a complex FFT of half the length
+	values := make([]complex128, half)
+	for i := range values {
+		twiddle := cmplx.Exp(complex(0, -math.Pi*float64(4*i+1)/float64(4*n)))
+		values[i] = complex(folded[2*i], folded[n-1-2*i]) * twiddle
+	}
+	fft(values)
+
+	output := make([]float64, n)
+	scale := 2 / float64(n)
+	for k := 0; k < half; k++ {
+		value := values[k] * cmplx.Exp(complex(0, -math.Pi*float64(k)/float64(n)))
+		output[2*k] = real(value) * scale
+		output[n-1-2*k] = -imag(value) * scale
+	}
+
+	return output
+}
+
+// fft is an in-place radix-2 transform; the length must be a power of two
+func fft(values []complex128) {
+	n := len(values)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			values[i], values[j] = values[j], values[i]
+		}
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			twiddle := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				odd := twiddle * values[start+k+size/2]
+				values[start+k+size/2] = values[start+k] - odd
+				values[start+k] += odd
+				twiddle *= step
+			}
+		}
+	}
+}
+
+//</editor-fold>
+
+// <editor-fold desc="Floor">
+
+func vorbisNeighbors(x []int, index int) (int, int) {
+	low, high := 0, 1
+	for i := 2; i < index; i++ {
+		if x[i] < x[index] && x[i] > x[low] {
+			low = i
+		}
+		if x[i] > x[index] && x[i] < x[high] {
+			high = i
+		}
+	}
+	return low, high
+}
+
+func vorbisRenderPoint(x0, y0, x1, y1, x int) int {
+	dy := y1 - y0
+	offset := abs(dy) * (x - x0) / (x1 - x0)
+	if dy < 0 {
+		return y0 - offset
+	}
+	return y0 + offset
+}
+
+// vorbisFloorDecodeValue mirrors how decoders turn a coded floor value into an absolute Y
+func vorbisFloorDecodeValue(predicted, value int) int {
+	highRoom := vorbisFloorRange - predicted
+	lowRoom := predicted
+	room := 2 * min(highRoom, lowRoom)
+
+	if value == 0 {
+		return predicted
+	}
+	if value >= room {
+		if highRoom > lowRoom {
+			return value - lowRoom + predicted
+		}
+		return predicted - value + highRoom - 1
+	}
+	if value%2 == 1 {
+		return predicted - (value+1)/2
+	}
+	return predicted + value/2
+}
+
+//</editor-fold>
+
+// <editor-fold desc="Headers">
+
+func vorbisIdentificationHeader(sampleRate, channels, nominalBitrate int) []byte {
+	header := make([]byte, 30)
+	header[0] = 1
+	copy(header[1:], "vorbis")
+	binary.LittleEndian.PutUint32(header[7:], 0)
+	header[11] = byte(channels)
+	binary.LittleEndian.PutUint32(header[12:], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(header[16:], 0)
+	binary.LittleEndian.PutUint32(header[20:], uint32(nominalBitrate))
+	binary.LittleEndian.PutUint32(header[24:], 0)
+	header[28] = vorbisShortBlockBits | vorbisLongBlockBits<<4
+	header[29] = 1
+	return header
+}
+
+func vorbisCommentHeader() []byte {
+	header := []byte("\x03vorbis")
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(vorbisVendor)))
+	header = append(header, vorbisVendor...)
+	header = binary.LittleEndian.AppendUint32(header, 0)
+	return append(header, 1)
+}
+
+func (e *vorbisEncoder) setupHeader(books []*vorbisCodebook) []byte {
+	writer := &vorbisPacketWriter{}
+	for _, b := range []byte("\x05vorbis") {
+		writer.writeBits(uint32(b), 8)
+	}
+
+	writer.writeBits(uint32(len(books)-1), 8)
+	for _, book := range books {
+		writer.writeBits(0x564342, 24)
+		writer.writeBits(uint32(book.dimensions), 16)
+		writer.writeBits(uint32(book.entries), 24)
+		writer.writeBits(0, 1) // not ordered
+		writer.writeBits(0, 1) // not sparse
+		for _, length := range book.lengths {
+			writer.writeBits(uint32(length-1), 5)
+		}
+
+		if book.values == 0 {
+			writer.writeBits(0, 4)
+			continue
+		}
+		valueBits := ilog(book.values - 1)
+		writer.writeBits(1, 4)
+		writer.writeBits(vorbisPackFloat(float64(book.minimum)), 32)
+		writer.writeBits(vorbisPackFloat(float64(book.delta)), 32)
+		writer.writeBits(uint32(valueBits-1), 4)
+		writer.writeBits(0, 1)
+		for value := 0; value < book.values; value++ {
+			writer.writeBits(uint32(value), valueBits)
+		}
+	}
+
+	// Time domain transforms are placeholders in Vorbis I
+	writer.writeBits(0, 6)
+	writer.writeBits(0, 16)
+
+	writer.writeBits(0, 6)
+	writer.writeBits(1, 16)
+	partitions := len(vorbisFloorPosts) / vorbisFloorDimensions
+	writer.writeBits(uint32(partitions), 5)
+	for i := 0; i < partitions; i++ {
+		writer.writeBits(0, 4)
+	}
+	writer.writeBits(vorbisFloorDimensions-1, 3)
+	writer.writeBits(0, 2)
+	writer.writeBits(vorbisBookFloor+1, 8)
+	writer.writeBits(vorbisFloorMultiplier-1, 2)
+	writer.writeBits(vorbisFloorRangeBits, 4)
+	for _, x := range vorbisFloorPosts {
+		writer.writeBits(uint32(x), vorbisFloorRangeBits)
+	}
+
+	writer.writeBits(0, 6)
+	writer.writeBits(1, 16)
+	writer.writeBits(0, 24)
+	writer.writeBits(uint32(e.residueEnd), 24)
+	writer.writeBits(vorbisPartitionSize-1, 24)
+	writer.writeBits(uint32(len(vorbisResidueClasses)-1), 6)
+	writer.writeBits(vorbisBookClass, 8)
+	for _, class := range vorbisResidueClasses {
+		cascade := uint32(1)<<len(class.books) - 1
+		writer.writeBits(cascade&7, 3)
+		if cascade > 7 {
+			writer.writeBits(1, 1)
+			writer.writeBits(cascade>>3, 5)
+		} else {
+			writer.writeBits(0, 1)
+		}
+	}
+	for _, class := range vorbisResidueClasses {
+		for _, book := range class.books {
+			writer.writeBits(uint32(book), 8)
+		}
+	}
+
+	writer.writeBits(0, 6)
+	writer.writeBits(0, 16)
+	writer.writeBits(0, 1) // single submap
+	writer.writeBits(0, 1) // no channel coupling
+	writer.writeBits(0, 2)
+	writer.writeBits(0, 8)
+	writer.writeBits(0, 8)
+	writer.writeBits(0, 8)
+
+	writer.writeBits(0, 6)
+	writer.writeBits(1, 1) // long blocks only
+	writer.writeBits(0, 16)
+	writer.writeBits(0, 16)
+	writer.writeBits(0, 8)
+
+	writer.writeBits(1, 1) // framing
+	return writer.bytes()
+}
+
+//</editor-fold>
+
+// <editor-fold desc="Codebooks">
+
+func vorbisCodebooks() []*vorbisCodebook {
+	lattice := func(dimensions, values, delta int) *vorbisCodebook {
+		entries := 1
+		for i := 0; i < dimensions; i++ {
+			entries *= values
+		}
+		return &vorbisCodebook{
+			dimensions: dimensions,
+			entries:    entries,
+			values:     values,
+			minimum:    -(values / 2) * delta,
+			delta:      delta,
+		}
+	}
+
+	classes := len(vorbisResidueClasses)
+	books := make([]*vorbisCodebook, vorbisBookCount)
+	books[vorbisBookFloor] = &vorbisCodebook{dimensions: 1, entries: vorbisFloorRange}
+	books[vorbisBookClass] = &vorbisCodebook{dimensions: 2, entries: classes * classes}
+	books[vorbisBookUnit] = lattice(4, 3, 1)
+	books[vorbisBookSmall] = lattice(2, 5, 1)
+	books[vorbisBookMedium] = lattice(2, 9, 1)
+	books[vorbisBookFine] = lattice(2, 17, 1)
+	books[vorbisBookMid] = lattice(2, 17, 17)
+	books[vorbisBookCoarse] = lattice(2, 17, 17*17)
+	return books
+}
+
+// vorbisCodewords assigns codewords in entry order, as the specification requires of decoders
+func vorbisCodewords(lengths []int) []uint32 {
+	var marker [33]uint32
+	codewords := make([]uint32, len(lengths))
+
+	for i, length := range lengths {
+		entry := marker[length]
+		codewords[i] = entry
+
+		for j := length; j > 0; j-- {
+			if marker[j]&1 != 0 {
+				if j == 1 {
+					marker[1]++
+				} else {
+					marker[j] = marker[j-1] << 1
+				}
+				break
+			}
+			marker[j]++
+		}
+
+		for j := length + 1; j < len(marker); j++ {
+			if marker[j]>>1 != entry {
+				break
+			}
+			entry = marker[j]
+			marker[j] = marker[j-1] << 1
+		}
+	}
+
+	return codewords
+}
+
+type huffmanNode struct {
+	weight  int
+	symbols []int
+}
+
+type huffmanQueue []huffmanNode
+
+func (q huffmanQueue) Len() int           { return len(q) }
+func (q huffmanQueue) Less(i, j int) bool { return q[i].weight < q[j].weight }
+func (q huffmanQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *huffmanQueue) Push(x any)        { *q = append(*q, x.(huffmanNode)) }
+func (q *huffmanQueue) Pop() any {
+	old := *q
+	node := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return node
+}
+
+// huffmanLengths builds code lengths for every symbol, flattening the statistics until no code exceeds maxLength
+func huffmanLengths(counts []int, maxLength int) []int {
+	weights := make([]int, len(counts))
+	for i, count := range counts {
+		weights[i] = count + 1
+	}
+
+	for {
+		lengths := make([]int, len(weights))
+		queue := make(huffmanQueue, len(weights))
+		for i, weight := range weights {
+			queue[i] = huffmanNode{weight: weight, symbols: []int{i}}
+		}
+		heap.Init(&queue)
+
+		for queue.Len() > 1 {
+			first := heap.Pop(&queue).(huffmanNode)
+			second := heap.Pop(&queue).(huffmanNode)
+			for _, symbol := range first.symbols {
+				lengths[symbol]++
+			}
+			for _, symbol := range second.symbols {
+				lengths[symbol]++
+			}
+			heap.Push(&queue, huffmanNode{
+				weight:  first.weight + second.weight,
+				symbols: append(first.symbols, second.symbols...),
+			})
+		}
+
+		longest := 0
+		for _, length := range lengths {
+			longest = max(longest, length)
+		}
+		if longest <= maxLength {
+			return lengths
+		}
+
+		for i := range weights {
+			weights[i] = weights[i]/2 + 1
+		}
+	}
+}
+
+func vorbisPackFloat(value float64) uint32 {
+	if value == 0 {
+		return 0
+	}
+
+	var sign uint32
+	if value < 0 {
+		sign = 0x80000000
+		value = -value
+	}
+
+	fraction, exponent := math.Frexp(value)
+	mantissa := uint32(fraction * (1 << 21))
+	return sign | uint32(exponent-21+788)<<21 | mantissa
+}
+
+//</editor-fold>
+
+// <editor-fold desc="Bitstream">
+
+type vorbisSymbolWriter interface {
+	writeBits(value uint32, n int)
+	writeSymbol(book, entry int)
+}
+
+type vorbisSymbolCounter struct {
+	counts [][]int
+}
+
+func (c *vorbisSymbolCounter) writeBits(value uint32, n int) {}
+
+func (c *vorbisSymbolCounter) writeSymbol(book, entry int) {
+	c.counts[book][entry]++
+}
+
+// vorbisPacketWriter packs bits least significant first, as Vorbis requires
+type vorbisPacketWriter struct {
+	books       []*vorbisCodebook
+	buffer      []byte
+	accumulator uint64
+	count       int
+}
+
+func (w *vorbisPacketWriter) writeBits(value uint32, n int) {
+	w.accumulator |= uint64(value) & (1<<n - 1) << w.count
+	w.count += n
+	for w.count >= 8 {
+		w.buffer = append(w.buffer, byte(w.accumulator))
+		w.accumulator >>= 8
+		w.count -= 8
+	}
+}
+
+// writeSymbol emits a Huffman codeword, whose first bit is its most significant one
+func (w *vorbisPacketWriter) writeSymbol(book, entry int) {
+	codeword := w.books[book].codewords[entry]
+	length := w.books[book].lengths[entry]
+	for bit := length - 1; bit >= 0; bit-- {
+		w.writeBits(codeword>>bit&1, 1)
+	}
+}
+
+func (w *vorbisPacketWriter) bytes() []byte {
+	if w.count > 0 {
+		return append(w.buffer, byte(w.accumulator))
+	}
+	return w.buffer
+}
+
+func ilog(value int) int {
+	bits := 0
+	for value > 0 {
+		bits++
+		value >>= 1
+	}
+	return bits
+}
+
+// quantizeDeadzone rounds towards zero slightly more eagerly than math.Round, trading a little noise for sparser residues
+func quantizeDeadzone(value float64) int {
+	if value < 0 {
+		return -int(-value + 0.4)
+	}
+	return int(value + 0.4)
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+//</editor-fold>
@@ -0,0 +1,136 @@
+package audio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/gopxl/beep/vorbis"
+)
+
+// decodeOGGForTest decodes an Ogg Vorbis stream into one float slice per channel
+func decodeOGGForTest(t *testing.T, data []byte) ([][]float64, int, int) {
+	t.Helper()
+
+	streamer, format, err := vorbis.Decode(io.NopCloser(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatalf("decoding Ogg Vorbis: %v", err)
+	}
+	defer streamer.Close()
+
+	channels := make([][]float64, format.NumChannels)
+	buffer := make([][2]float64, 1024)
+	for {
+		count, ok := streamer.Stream(buffer)
+		for _, sample := range buffer[:count] {
+			for channel := range channels {
+				channels[channel] = append(channels[channel], sample[channel])
+			}
+		}
+		if !ok {
+			break
+		}
+	}
+	if err := streamer.Err(); err != nil {
+		t.Fatalf("decoding Ogg Vorbis: %v", err)
+	}
+	return channels, int(format.SampleRate), format.NumChannels
+}
+
+// floatChannels splits 16-bit interleaved PCM into one float slice per channel, scaled to [-1, 1)
+func floatChannels(pcmData []byte, channels int) [][]float64 {
+	split := make([][]float64, channels)
+	for offset := 0; offset+2*channels <= len(pcmData); offset += 2 * channels {
+		for channel := range channels {
+			value := int16(uint16(pcmData[offset+2*channel]) | uint16(pcmData[offset+2*channel+1])<<8)
+			split[channel] = append(split[channel], float64(value)/32768)
+		}
+	}
+	return split
+}
+
+// alignedSNR is the signal to noise ratio in dB of decoded against original, at the delay up to maxDelay samples
+// that fits best. Lossy encoders and decoders add a delay of their own to the start of the stream.
+func alignedSNR(original, decoded []float64, maxDelay int) (float64, int) {
+	bestSNR, bestDelay := math.Inf(-1), 0
+	for delay := 0; delay <= maxDelay && delay < len(decoded); delay++ {
+		var signal, noise float64
+		for index, value := range original {
+			if index+delay >= len(decoded) {
+				break
+			}
+			difference := decoded[index+delay] - value
+			signal += value * value
+			noise += difference * difference
+		}
+		if noise == 0 {
+			return math.Inf(1), delay
+		}
+		if snr := 10 * math.Log10(signal/noise); snr > bestSNR {
+			bestSNR, bestDelay = snr, delay
+		}
+	}
+	return bestSNR, bestDelay
+}
+
+// testSweep is a tone sweeping from 200 Hz to 2 kHz over a steady 440 Hz one, as 16-bit interleaved PCM. The sweep
+// never repeats, so alignedSNR can only line it up at the actual delay.
+func testSweep(frames, sampleRate, channels int) []byte {
+	duration := float64(frames) / float64(sampleRate)
+	var buffer bytes.Buffer
+	for frame := range frames {
+		time := float64(frame) / float64(sampleRate)
+		sweep := 2 * math.Pi * (200*time + (2000-200)*time*time/(2*duration))
+		for channel := range channels {
+			value := 0.4*math.Sin(sweep) + 0.2*math.Sin(2*math.Pi*440*time+float64(channel))
+			sample := int16(value * 32767)
+			buffer.Write([]byte{byte(sample), byte(sample >> 8)})
+		}
+	}
+	return buffer.Bytes()
+}
+
+func TestEncodeOGGDecodes(t *testing.T) {
+	tests := []struct {
+		sampleRate, channels int
+		quality              float64
+		bitrate              int
+		minSNR               float64
+	}{
+		{22050, 1, DefaultOGGQuality, 0, 20},
+		{24000, 1, MaxOGGQuality, 0, 30},
+		{44100, 2, DefaultOGGQuality, 0, 20},
+		{48000, 2, MinOGGQuality, 0, 12},
+		{44100, 1, 0, 96, 30},
+	}
+
+	for _, test := range tests {
+		name := fmt.Sprintf("%dHz/%dch/q%g/%dkbps", test.sampleRate, test.channels, test.quality, test.bitrate)
+		t.Run(name, func(t *testing.T) {
+			frames := test.sampleRate // one second
+			pcmData := testSweep(frames, test.sampleRate, test.channels)
+
+			encoded, err := EncodeOGG(pcmData, test.sampleRate, test.channels, 16, test.quality, test.bitrate)
+			if err != nil {
+				t.Fatalf("EncodeOGG: %v", err)
+			}
+
+			decoded, sampleRate, channels := decodeOGGForTest(t, encoded)
+			if sampleRate != test.sampleRate || channels != test.channels {
+				t.Fatalf("decoded %d Hz, %d channels, want %d Hz, %d channels", sampleRate, channels, test.sampleRate, test.channels)
+			}
+
+			original := floatChannels(pcmData, test.channels)
+			for channel := range original {
+				if length := len(decoded[channel]); length < frames || length > frames+4096 {
+					t.Fatalf("channel %d decoded to %d samples, want about %d", channel, length, frames)
+				}
+				if snr, delay := alignedSNR(original[channel], decoded[channel], 4096); snr < test.minSNR {
+					t.Fatalf("channel %d: SNR %.1f dB at a delay of %d samples, want at least %g dB", channel, snr, delay, test.minSNR)
+				}
+			}
+		})
+	}
+}
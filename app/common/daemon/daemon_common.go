@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"path/filepath"
 	"sync"
@@ -14,6 +15,7 @@ const (
 	PidFileName    = "narration-studio.pid"
 	LogFileName    = "narration-studio-daemon.log"
 	StatusFileName = "narration-studio-status.json"
+	SocketFileName = "narration-studio.sock"
 )
 
 var statusMutex sync.Mutex
@@ -23,6 +25,9 @@ type DaemonStatusInfo struct {
 	Version           string    `json:"version"`
 	StartTime         time.Time `json:"start_time"`
 	ProcessedMessages int64     `json:"processed_messages"`
+	SocketPath        string    `json:"socket_path,omitempty"`
+	QueuedJobs        int       `json:"queued_jobs,omitempty"`
+	RunningJobs       int       `json:"running_jobs,omitempty"`
 }
 
 func GetPidFilePath() string {
@@ -40,6 +45,11 @@ func GetStatusFilePath() string {
 	return filepath.Join(tmpDir, StatusFileName)
 }
 
+func GetSocketFilePath() string {
+	tmpDir := os.TempDir()
+	return filepath.Join(tmpDir, SocketFileName)
+}
+
 func SetupDaemonLogger() (*os.File, error) {
 	logFile, err := os.OpenFile(GetLogFilePath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
@@ -82,3 +92,38 @@ func ReadDaemonStatus() (*DaemonStatusInfo, error) {
 
 	return &status, nil
 }
+
+// SocketInUse reports whether a server is accepting connections on the Unix socket at path
+func SocketInUse(path string) bool {
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// RemoveStaleSocket deletes the socket file at path unless a server is still listening on it.
+// It reports whether a file was removed.
+func RemoveStaleSocket(path string) (bool, error) {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error checking socket: %v", err)
+	}
+
+	if info.IsDir() || (info.Mode().IsRegular() && info.Size() > 0) {
+		return false, fmt.Errorf("%s exists and is not a socket", path)
+	}
+
+	if SocketInUse(path) {
+		return false, fmt.Errorf("socket %s is in use by another server", path)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return false, fmt.Errorf("error removing socket: %v", err)
+	}
+	return true, nil
+}
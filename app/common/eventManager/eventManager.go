@@ -10,6 +10,7 @@ notification.send - send a notification to the user
 notification.enabled - enable/disable notifications
 app.refresh - reload current view
 status - current progress
+script.progress - a line of a script render was written
 */
 
 type EventManager struct {
@@ -0,0 +1,140 @@
+package script
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"nstudio/app/common/util"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvColumns are the header names ImportCSV recognises, by the field they fill
+var csvColumns = map[string][]string{
+	"character": {"character", "speaker", "name", "voice"},
+	"text":      {"text", "line", "dialogue", "message"},
+	"id":        {"id", "cue", "cue_id", "number"},
+	"start":     {"start", "start_time", "begin"},
+	"end":       {"end", "end_time", "stop"},
+}
+
+// ImportCSV reads rows of character,text. A header row naming character and text columns (plus optional id,
+// start and end columns) allows any column order. Without an id column the row number is the cue ID.
+func ImportCSV(text string, options ImportOptions) ([]util.CharacterMessage, error) {
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	type row struct {
+		line   int
+		record []string
+	}
+
+	var rows []row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseError *csv.ParseError
+			if errors.As(err, &parseError) {
+				return nil, Errors{{Line: parseError.Line, Message: parseError.Err.Error()}}
+			}
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, row{line: line, record: record})
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	columns := map[string]int{"character": 0, "text": 1, "id": -1, "start": -1, "end": -1}
+	if header, ok := csvHeader(rows[0].record); ok {
+		columns = header
+		rows = rows[1:]
+	}
+
+	field := func(record []string, name string) string {
+		column := columns[name]
+		if column < 0 || column >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[column])
+	}
+
+	var messages []util.CharacterMessage
+	var errs Errors
+
+	for index, row := range rows {
+		record := row.record
+		var err error
+
+		content := field(record, "text")
+		if content == "" {
+			continue
+		}
+
+		character := field(record, "character")
+		if character == "" {
+			character = options.DefaultCharacter
+		}
+
+		cue := &util.Cue{ID: field(record, "id")}
+		if cue.ID == "" {
+			cue.ID = strconv.Itoa(index + 1)
+		}
+
+		if start := field(record, "start"); start != "" {
+			if cue.Start, err = parseCSVTime(start); err != nil {
+				errs = append(errs, Error{Line: row.line, Message: err.Error()})
+				continue
+			}
+		}
+		if end := field(record, "end"); end != "" {
+			if cue.End, err = parseCSVTime(end); err != nil {
+				errs = append(errs, Error{Line: row.line, Message: err.Error()})
+				continue
+			}
+		}
+
+		messages = append(messages, util.CharacterMessage{Character: character, Text: content, Cue: cue})
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return messages, nil
+}
+
+// csvHeader maps the columns of a header row, it is only a header when it names both character and text
+func csvHeader(record []string) (map[string]int, bool) {
+	columns := map[string]int{"id": -1, "start": -1, "end": -1}
+
+	for index, name := range record {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for field, names := range csvColumns {
+			for _, candidate := range names {
+				if name == candidate {
+					if _, seen := columns[field]; !seen || columns[field] < 0 {
+						columns[field] = index
+					}
+				}
+			}
+		}
+	}
+
+	_, character := columns["character"]
+	_, text := columns["text"]
+	return columns, character && text
+}
+
+// parseCSVTime accepts subtitle timestamps as well as plain seconds
+func parseCSVTime(value string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	return parseTimestamp(value)
+}
@@ -0,0 +1,152 @@
+package script
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TimedLine is a line of the combined file, with where it starts and ends in it
+type TimedLine struct {
+	ID        string        `json:"id,omitempty"`
+	Character string        `json:"character"`
+	Text      string        `json:"text"`
+	File      string        `json:"file,omitempty"`
+	Start     time.Duration `json:"-"`
+	End       time.Duration `json:"-"`
+
+	// Overrun is how far the line runs past the end of its cue when aligned to cue times, at Tempo
+	Overrun time.Duration `json:"-"`
+	Tempo   float64       `json:"tempo,omitempty"`
+}
+
+func (line TimedLine) MarshalJSON() ([]byte, error) {
+	type Alias TimedLine
+	return json.Marshal(&struct {
+		Alias
+		Start    float64 `json:"start"`
+		End      float64 `json:"end"`
+		Duration float64 `json:"duration"`
+		Overrun  float64 `json:"overrun,omitempty"`
+	}{
+		Alias:    (Alias)(line),
+		Start:    line.Start.Seconds(),
+		End:      line.End.Seconds(),
+		Duration: (line.End - line.Start).Seconds(),
+		Overrun:  line.Overrun.Seconds(),
+	})
+}
+
+// CueSheet describes a combined file for editors: which line is spoken when and by whom
+type CueSheet struct {
+	Audio    string      `json:"audio"`
+	Pause    float64     `json:"pause"`
+	Duration float64     `json:"duration"`
+	Cues     []TimedLine `json:"cues"`
+}
+
+// FormatSRT writes the lines as SubRip subtitles, with the character in front of the text
+func FormatSRT(lines []TimedLine) string {
+	var builder strings.Builder
+	for index, line := range lines {
+		fmt.Fprintf(&builder, "%d\n%s --> %s\n%s\n\n",
+			index+1,
+			formatTimestamp(line.Start, ","),
+			formatTimestamp(line.End, ","),
+			speakerText(line.Character, line.Text),
+		)
+	}
+	return builder.String()
+}
+
+// FormatVTT writes the lines as WebVTT, with the character in a voice span and the source cue ID as identifier
+func FormatVTT(lines []TimedLine) string {
+	var builder strings.Builder
+	builder.WriteString("WEBVTT\n\n")
+
+	for index, line := range lines {
+		id := line.ID
+		if id == "" || strings.Contains(id, "-->") {
+			id = fmt.Sprint(index + 1)
+		}
+
+		text := escapeVTT(line.Text)
+		if line.Character != "" {
+			text = fmt.Sprintf("<v %s>%s", escapeVTT(line.Character), text)
+		}
+
+		fmt.Fprintf(&builder, "%s\n%s --> %s\n%s\n\n",
+			strings.ReplaceAll(id, "\n", " "),
+			formatTimestamp(line.Start, "."),
+			formatTimestamp(line.End, "."),
+			text,
+		)
+	}
+	return builder.String()
+}
+
+// WriteCueFiles writes <name>.srt, <name>.vtt and <name>.json next to the combined file <name>.wav
+func WriteCueFiles(dirPath, audioFilename string, pause time.Duration, lines []TimedLine) error {
+	name := strings.TrimSuffix(audioFilename, filepath.Ext(audioFilename))
+
+	sheet := CueSheet{
+		Audio: audioFilename,
+		Pause: pause.Seconds(),
+		Cues:  lines,
+	}
+	for _, line := range lines {
+		sheet.Duration = max(sheet.Duration, line.End.Seconds())
+	}
+	if sheet.Cues == nil {
+		sheet.Cues = []TimedLine{}
+	}
+
+	sheetJSON, err := json.MarshalIndent(sheet, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	files := map[string][]byte{
+		name + ".srt":  []byte(FormatSRT(lines)),
+		name + ".vtt":  []byte(FormatVTT(lines)),
+		name + ".json": sheetJSON,
+	}
+	for filename, data := range files {
+		if err := os.WriteFile(filepath.Join(dirPath, filename), data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", filename, err)
+		}
+	}
+	return nil
+}
+
+// CueFilenames lists the files WriteCueFiles creates for a combined file
+func CueFilenames(audioFilename string) []string {
+	name := strings.TrimSuffix(audioFilename, filepath.Ext(audioFilename))
+	return []string{name + ".srt", name + ".vtt", name + ".json"}
+}
+
+// formatTimestamp writes hh:mm:ss followed by the separator and milliseconds
+func formatTimestamp(duration time.Duration, separator string) string {
+	duration = duration.Round(time.Millisecond)
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d",
+		int(duration.Hours()),
+		int(duration.Minutes())%60,
+		int(duration.Seconds())%60,
+		separator,
+		duration.Milliseconds()%1000,
+	)
+}
+
+func speakerText(character, text string) string {
+	if character == "" {
+		return text
+	}
+	return character + ": " + text
+}
+
+func escapeVTT(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\n", " ").Replace(text)
+}
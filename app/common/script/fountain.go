@@ -0,0 +1,162 @@
+package script
+
+import (
+	"fmt"
+	"nstudio/app/common/util"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	boneyardRegex       = regexp.MustCompile(`(?s)/\*.*?\*/|\[\[.*?\]\]`)
+	titleKeyRegex       = regexp.MustCompile(`^[A-Za-z][A-Za-z ]*:`)
+	sceneHeadingRegex   = regexp.MustCompile(`(?i)^(INT|EXT|EST|INT\.?/EXT|I/E)[. ]`)
+	sceneNumberRegex    = regexp.MustCompile(`\s*#([^#\s]+)#\s*$`)
+	extensionRegex      = regexp.MustCompile(`\s*\([^)]*\)`)
+	emphasisRegex       = regexp.MustCompile(`(\\?)([*_])`)
+	parentheticalRegex  = regexp.MustCompile(`^\(.*\)$`)
+	transitionLineRegex = regexp.MustCompile(`^[^a-z]*TO:$`)
+)
+
+// fountainScene numbers the lines of one scene, the cue ID of a line is "<scene>.<line>"
+type fountainScene struct {
+	number string
+	lines  int
+}
+
+func (scene *fountainScene) nextCue() *util.Cue {
+	scene.lines++
+	return &util.Cue{ID: fmt.Sprintf("%s.%d", scene.number, scene.lines)}
+}
+
+// ImportFountain reads the dialogue of a Fountain screenplay. Scene headings number the cues, explicit scene
+// numbers (#12A#) are kept. Parentheticals, notes, boneyard, sections, synopses and transitions are not spoken.
+func ImportFountain(text string, options ImportOptions) ([]util.CharacterMessage, error) {
+	// Keep the line breaks of removed notes so paragraphs stay apart
+	text = boneyardRegex.ReplaceAllStringFunc(text, func(match string) string {
+		return strings.Repeat("\n", strings.Count(match, "\n"))
+	})
+
+	lines := strings.Split(text, "\n")
+	for index := range lines {
+		lines[index] = strings.TrimRight(lines[index], " \t")
+	}
+	start := skipTitlePage(lines)
+
+	var messages []util.CharacterMessage
+	scene := &fountainScene{number: "0"}
+	scenes := 0
+
+	blank := func(index int) bool { return index < 0 || index >= len(lines) || strings.TrimSpace(lines[index]) == "" }
+
+	for index := start; index < len(lines); index++ {
+		line := strings.TrimSpace(lines[index])
+		if line == "" {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "#"), strings.HasPrefix(line, "="):
+			// Sections, synopses and page breaks
+			continue
+
+		case blank(index-1) && isSceneHeading(line):
+			scenes++
+			scene = &fountainScene{number: strconv.Itoa(scenes)}
+			if match := sceneNumberRegex.FindStringSubmatch(line); match != nil {
+				scene.number = match[1]
+			}
+			continue
+
+		case strings.HasPrefix(line, ">"), blank(index-1) && blank(index+1) && transitionLineRegex.MatchString(line):
+			// Transitions and centered text
+			continue
+
+		case blank(index-1) && !blank(index+1) && isCharacter(line):
+			character := characterName(line)
+
+			var dialogue []string
+			for index+1 < len(lines) && !blank(index+1) {
+				index++
+				spoken := strings.TrimSpace(lines[index])
+				if parentheticalRegex.MatchString(spoken) {
+					continue
+				}
+				dialogue = append(dialogue, strings.TrimPrefix(spoken, "~"))
+			}
+
+			if content := stripEmphasis(strings.Join(dialogue, " ")); content != "" {
+				messages = append(messages, util.CharacterMessage{Character: character, Text: content, Cue: scene.nextCue()})
+			}
+			continue
+		}
+
+		// Action, up to the end of the paragraph
+		action := []string{strings.TrimPrefix(line, "!")}
+		for index+1 < len(lines) && !blank(index+1) {
+			index++
+			action = append(action, strings.TrimSpace(lines[index]))
+		}
+
+		if options.IncludeAction {
+			if content := stripEmphasis(strings.Join(action, " ")); content != "" {
+				messages = append(messages, util.CharacterMessage{Character: options.DefaultCharacter, Text: content, Cue: scene.nextCue()})
+			}
+		}
+	}
+
+	return messages, nil
+}
+
+// skipTitlePage returns the first line after the "Key: value" title page, if the screenplay has one
+func skipTitlePage(lines []string) int {
+	if len(lines) == 0 || !titleKeyRegex.MatchString(lines[0]) {
+		return 0
+	}
+	for index, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			return index + 1
+		}
+	}
+	return len(lines)
+}
+
+func isSceneHeading(line string) bool {
+	if strings.HasPrefix(line, ".") {
+		return len(line) > 1 && line[1] != '.'
+	}
+	return sceneHeadingRegex.MatchString(line)
+}
+
+// isCharacter reports whether a line is a character cue: upper case, or forced with @
+func isCharacter(line string) bool {
+	if strings.HasPrefix(line, "@") {
+		return len(line) > 1
+	}
+
+	name := characterName(line)
+	if name == "" || strings.ToUpper(name) != name || strings.HasSuffix(line, "TO:") {
+		return false
+	}
+	return strings.IndexFunc(name, func(r rune) bool { return r >= 'A' && r <= 'Z' }) >= 0
+}
+
+// characterName drops the forcing @, extensions like (V.O.) and the dual dialogue caret from a character cue
+func characterName(line string) string {
+	name := strings.TrimPrefix(line, "@")
+	name = strings.TrimSuffix(strings.TrimSpace(name), "^")
+	name = extensionRegex.ReplaceAllString(name, "")
+	return strings.TrimSpace(name)
+}
+
+// stripEmphasis removes *italic*, **bold** and _underline_ markers, keeping escaped ones
+func stripEmphasis(text string) string {
+	text = emphasisRegex.ReplaceAllStringFunc(text, func(match string) string {
+		if strings.HasPrefix(match, `\`) {
+			return match[1:]
+		}
+		return ""
+	})
+	return strings.Join(strings.Fields(text), " ")
+}
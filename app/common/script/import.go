@@ -0,0 +1,97 @@
+package script
+
+import (
+	"fmt"
+	"nstudio/app/common/util"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCharacter speaks imported lines that don't name a speaker
+const DefaultCharacter = "Narrator"
+
+// ImportFormats lists the formats Import understands, by file extension
+var ImportFormats = []string{"fountain", "srt", "vtt", "csv"}
+
+type ImportOptions struct {
+	// DefaultCharacter replaces DefaultCharacter for lines without a speaker
+	DefaultCharacter string
+
+	// IncludeAction reads a screenplay's action paragraphs with the default character, instead of dialogue only
+	IncludeAction bool
+}
+
+var (
+	timestampRegex = regexp.MustCompile(`^(?:(\d+):)?(\d{1,2}):(\d{1,2})(?:[.,](\d{1,3}))?$`)
+	markupRegex    = regexp.MustCompile(`<[^>]*>|\{\\[^}]*\}`)
+)
+
+// Import reads a Fountain screenplay, an SRT or WebVTT subtitle file, or a character,text CSV into messages.
+// format is a file name or extension. Every message carries a Cue with its ID in the source, and for subtitles
+// and timed CSV rows its start and end time.
+func Import(format string, data []byte, options ImportOptions) ([]util.CharacterMessage, error) {
+	if options.DefaultCharacter == "" {
+		options.DefaultCharacter = DefaultCharacter
+	}
+
+	format = strings.ToLower(strings.TrimPrefix(filepath.Ext("."+format), "."))
+	text := strings.ReplaceAll(strings.TrimPrefix(string(data), "\ufeff"), "\r\n", "\n")
+
+	var messages []util.CharacterMessage
+	var err error
+
+	switch format {
+	case "fountain", "spmd":
+		messages, err = ImportFountain(text, options)
+	case "srt":
+		messages, err = ImportSRT(text, options)
+	case "vtt", "webvtt":
+		messages, err = ImportVTT(text, options)
+	case "csv":
+		messages, err = ImportCSV(text, options)
+	default:
+		return nil, fmt.Errorf("unsupported import format %q, supported: %s", format, strings.Join(ImportFormats, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("no lines found in %s file", format)
+	}
+	return messages, nil
+}
+
+// parseTimestamp reads subtitle times such as 01:02:03,500, 01:02:03.500 and 02:03.500
+func parseTimestamp(value string) (time.Duration, error) {
+	match := timestampRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, fmt.Errorf("invalid timestamp %q", value)
+	}
+
+	hours, _ := strconv.Atoi(match[1])
+	minutes, _ := strconv.Atoi(match[2])
+	seconds, _ := strconv.Atoi(match[3])
+
+	milliseconds := 0
+	if match[4] != "" {
+		// "5" after the separator is half a second, not five milliseconds
+		fraction := (match[4] + "00")[:3]
+		milliseconds, _ = strconv.Atoi(fraction)
+	}
+
+	return time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second +
+		time.Duration(milliseconds)*time.Millisecond, nil
+}
+
+// cleanText joins the lines of a cue and removes subtitle markup like <i> and {\an8}
+func cleanText(lines []string) string {
+	text := markupRegex.ReplaceAllString(strings.Join(lines, " "), "")
+	text = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">", "&nbsp;", " ").Replace(text)
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,376 @@
+package script
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	chapterHeader = "chapter"
+	sceneHeader   = "scene"
+
+	maxPause = time.Minute
+	minRate  = 0.25
+	maxRate  = 4.0
+	minGain  = -60.0
+	maxGain  = 20.0
+)
+
+var (
+	characterLineRegex = regexp.MustCompile(`^([^:\[\]]+?)\s*:\s*(.*)$`)
+	directiveRegex     = regexp.MustCompile(`\[\s*([A-Za-z]+)(?:\s+([^\]]*?))?\s*\]`)
+	headerRegex        = regexp.MustCompile(`(?i)^\[\s*(chapter|scene)(?:\s+([^\]]*?))?\s*\]$`)
+	unitRegex          = regexp.MustCompile(`[A-Za-zµ]+$`)
+)
+
+// token is a run of text or a directive, in the order they appear on a line
+type token struct {
+	text      string
+	directive *Directive
+}
+
+type parser struct {
+	script  *Script
+	chapter *Chapter
+	scene   *Scene
+
+	// entry is the one continuation lines are added to, nil after a blank line or a header
+	entry *Entry
+	text  strings.Builder
+	last  *Entry
+
+	// pending holds directives written on lines of their own, they go to the next entry
+	pending []Directive
+
+	errors Errors
+}
+
+// Parse reads a script. When there are problems the error is an Errors listing every one of them, and the
+// returned script still holds all the entries that could be read.
+func Parse(text string) (*Script, error) {
+	p := &parser{script: &Script{Chapters: []*Chapter{}}}
+
+	text = strings.TrimPrefix(text, "\ufeff")
+	for index, line := range strings.Split(text, "\n") {
+		p.parseLine(index+1, strings.TrimRight(line, "\r"))
+	}
+	p.finish()
+
+	if len(p.errors) > 0 {
+		return p.script, p.errors
+	}
+	return p.script, nil
+}
+
+func (p *parser) errorf(line int, format string, args ...interface{}) {
+	p.errors = append(p.errors, Error{Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+func (p *parser) parseLine(number int, line string) {
+	trimmed := strings.TrimSpace(line)
+
+	if trimmed == "" {
+		p.closeEntry()
+		return
+	}
+	if strings.HasPrefix(trimmed, "#") {
+		return
+	}
+
+	if header := headerRegex.FindStringSubmatch(trimmed); header != nil {
+		p.closeEntry()
+		if strings.EqualFold(header[1], chapterHeader) {
+			p.startChapter(number, header[2])
+		} else {
+			p.startScene(number, header[2])
+		}
+		return
+	}
+
+	indented := line[0] == ' ' || line[0] == '\t'
+	if !indented {
+		if match := characterLineRegex.FindStringSubmatch(trimmed); match != nil {
+			p.closeEntry()
+			p.openEntry(number, match[1])
+			p.addText(number, match[2])
+			return
+		}
+	}
+
+	tokens, ok := p.tokenize(number, trimmed)
+	if p.entry != nil && (indented || !directivesOnly(tokens)) {
+		p.addTokens(number, tokens)
+		return
+	}
+
+	// Directives on a line of their own go to the next entry
+	p.closeEntry()
+	if !ok {
+		return
+	}
+	if !directivesOnly(tokens) {
+		p.errorf(number, "text without a character, expected \"Character: text\"")
+		return
+	}
+	for _, tok := range tokens {
+		if tok.directive != nil {
+			p.pending = append(p.pending, *tok.directive)
+		}
+	}
+}
+
+func (p *parser) startChapter(number int, title string) {
+	p.chapter = &Chapter{Title: title, Line: number, Scenes: []*Scene{}}
+	p.script.Chapters = append(p.script.Chapters, p.chapter)
+	p.scene = nil
+}
+
+func (p *parser) startScene(number int, title string) {
+	if p.chapter == nil {
+		p.startChapter(0, "")
+	}
+	p.scene = &Scene{Title: title, Line: number, Entries: []*Entry{}}
+	p.chapter.Scenes = append(p.chapter.Scenes, p.scene)
+}
+
+func (p *parser) openEntry(number int, character string) {
+	if p.scene == nil {
+		p.startScene(0, "")
+	}
+
+	p.entry = &Entry{Line: number, EndLine: number}
+	p.entry.Character = character
+	p.text.Reset()
+
+	for _, directive := range p.pending {
+		directive.Offset = 0
+		p.addDirective(directive)
+	}
+	p.pending = nil
+}
+
+func (p *parser) closeEntry() {
+	if p.entry == nil {
+		return
+	}
+
+	entry := p.entry
+	entry.Text = p.text.String()
+	p.entry = nil
+
+	if entry.Text == "" {
+		p.errorf(entry.Line, "no text for %s", entry.Character)
+		return
+	}
+
+	p.scene.Entries = append(p.scene.Entries, entry)
+	p.last = entry
+}
+
+func (p *parser) finish() {
+	p.closeEntry()
+
+	for _, directive := range p.pending {
+		if directive.Kind != Pause || p.last == nil {
+			p.errorf(directive.Line, "[%s] has no line to apply to", directive.Kind)
+			continue
+		}
+		directive.Offset = len(p.last.Text)
+		p.last.Directives = append(p.last.Directives, directive)
+	}
+	p.pending = nil
+}
+
+// addText appends one physical line to the open entry, taking its directives out of the text
+func (p *parser) addText(number int, line string) {
+	tokens, _ := p.tokenize(number, line)
+	p.addTokens(number, tokens)
+}
+
+func (p *parser) addTokens(number int, tokens []token) {
+	p.entry.EndLine = number
+
+	for _, tok := range tokens {
+		if tok.directive != nil {
+			tok.directive.Offset = p.text.Len()
+			p.addDirective(*tok.directive)
+			continue
+		}
+
+		text := strings.TrimSpace(tok.text)
+		if text == "" {
+			continue
+		}
+		if p.text.Len() > 0 {
+			p.text.WriteByte(' ')
+		}
+		p.text.WriteString(text)
+	}
+}
+
+func (p *parser) addDirective(directive Directive) {
+	entry := p.entry
+
+	if directive.Kind != Pause {
+		for _, existing := range entry.Directives {
+			if existing.Kind == directive.Kind {
+				p.errorf(directive.Line, "[%s] given twice for the same line", directive.Kind)
+				return
+			}
+		}
+	}
+
+	if directive.Kind == Voice {
+		engine, model, voice, _ := splitVoice(directive.Voice)
+		entry.Voice.Name = entry.Character
+		entry.Voice.Engine = engine
+		entry.Voice.Model = model
+		entry.Voice.Voice = voice
+	}
+
+	entry.Directives = append(entry.Directives, directive)
+}
+
+// directivesOnly reports whether a line holds nothing but directives
+func directivesOnly(tokens []token) bool {
+	for _, tok := range tokens {
+		if tok.directive == nil && strings.TrimSpace(tok.text) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenize splits a line into text and directives. Brackets that don't name a directive stay in the text. It
+// returns false when the line holds an invalid directive, after recording the error.
+func (p *parser) tokenize(number int, line string) ([]token, bool) {
+	var tokens []token
+	ok := true
+	last := 0
+
+	for _, match := range directiveRegex.FindAllStringSubmatchIndex(line, -1) {
+		kind := strings.ToLower(line[match[2]:match[3]])
+		argument := ""
+		if match[4] >= 0 {
+			argument = line[match[4]:match[5]]
+		}
+
+		if kind == chapterHeader || kind == sceneHeader {
+			p.errorf(number, "[%s] must be on a line of its own", kind)
+			ok = false
+			continue
+		}
+
+		directive, known, err := parseDirective(Kind(kind), argument)
+		if !known {
+			continue
+		}
+
+		if match[0] > last {
+			tokens = append(tokens, token{text: line[last:match[0]]})
+		}
+		last = match[1]
+
+		if err != nil {
+			p.errorf(number, "%v", err)
+			ok = false
+			continue
+		}
+		directive.Line = number
+		tokens = append(tokens, token{directive: &directive})
+	}
+
+	if last < len(line) {
+		tokens = append(tokens, token{text: line[last:]})
+	}
+	return tokens, ok
+}
+
+// parseDirective reads the argument of a directive. known is false for bracketed words that aren't directives.
+func parseDirective(kind Kind, argument string) (directive Directive, known bool, err error) {
+	directive.Kind = kind
+	argument = strings.TrimSpace(argument)
+
+	switch kind {
+	case Pause:
+		duration, err := parsePause(argument)
+		if err != nil {
+			return directive, true, err
+		}
+		directive.Seconds = duration.Seconds()
+
+	case Rate:
+		value, err := strconv.ParseFloat(strings.TrimSuffix(strings.ToLower(argument), "x"), 64)
+		if err != nil {
+			return directive, true, fmt.Errorf("invalid rate %q, expected a number like 1.2", argument)
+		}
+		if value < minRate || value > maxRate {
+			return directive, true, fmt.Errorf("rate %v is out of range (%v to %v)", value, minRate, maxRate)
+		}
+		directive.Rate = value
+
+	case Volume:
+		number := argument
+		if strings.HasSuffix(strings.ToLower(number), "db") {
+			number = strings.TrimSpace(number[:len(number)-2])
+		}
+		value, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			return directive, true, fmt.Errorf("invalid volume %q, expected decibels like -3dB", argument)
+		}
+		if value < minGain || value > maxGain {
+			return directive, true, fmt.Errorf("volume %vdB is out of range (%vdB to %vdB)", value, minGain, maxGain)
+		}
+		directive.Decibels = value
+
+	case Voice:
+		if _, _, _, ok := splitVoice(argument); !ok {
+			return directive, true, fmt.Errorf("invalid voice %q, expected engine:model:voice", argument)
+		}
+		directive.Voice = argument
+
+	default:
+		return directive, false, nil
+	}
+
+	return directive, true, nil
+}
+
+func parsePause(argument string) (time.Duration, error) {
+	var duration time.Duration
+	var err error
+
+	if unitRegex.MatchString(argument) {
+		duration, err = time.ParseDuration(argument)
+	} else {
+		var seconds float64
+		seconds, err = strconv.ParseFloat(argument, 64)
+		duration = time.Duration(seconds * float64(time.Second))
+	}
+
+	if err != nil || argument == "" {
+		return 0, fmt.Errorf("invalid pause %q, expected a duration like 1.5s or 500ms", argument)
+	}
+	if duration <= 0 || duration > maxPause {
+		return 0, fmt.Errorf("pause %s is out of range (up to %s)", duration, maxPause)
+	}
+	return duration, nil
+}
+
+// splitVoice splits a voice key. The engine ends at the first colon and the voice starts after the last one, so
+// model names may contain colons.
+func splitVoice(key string) (engine, model, voice string, ok bool) {
+	first := strings.Index(key, ":")
+	last := strings.LastIndex(key, ":")
+	if first < 0 || first == last {
+		return "", "", "", false
+	}
+
+	engine = strings.TrimSpace(key[:first])
+	model = strings.TrimSpace(key[first+1 : last])
+	voice = strings.TrimSpace(key[last+1:])
+	return engine, model, voice, engine != "" && model != "" && voice != ""
+}
@@ -0,0 +1,101 @@
+package script
+
+import (
+	"fmt"
+	"nstudio/app/common/audio"
+	"sort"
+	"strings"
+	"time"
+)
+
+// part is a piece of an entry to render: either text to speak or a silence
+type part struct {
+	text  string
+	pause time.Duration
+}
+
+// parts cuts the entry's text at its pauses
+func (entry *Entry) parts() []part {
+	var pauses []Directive
+	for _, directive := range entry.Directives {
+		if directive.Kind == Pause {
+			pauses = append(pauses, directive)
+		}
+	}
+	sort.SliceStable(pauses, func(i, j int) bool { return pauses[i].Offset < pauses[j].Offset })
+
+	var parts []part
+	start := 0
+	for _, directive := range pauses {
+		offset := min(max(directive.Offset, start), len(entry.Text))
+		if text := strings.TrimSpace(entry.Text[start:offset]); text != "" {
+			parts = append(parts, part{text: text})
+		}
+		start = offset
+
+		if last := len(parts) - 1; last >= 0 && parts[last].text == "" {
+			parts[last].pause += directive.Duration()
+		} else {
+			parts = append(parts, part{pause: directive.Duration()})
+		}
+	}
+	if text := strings.TrimSpace(entry.Text[start:]); text != "" {
+		parts = append(parts, part{text: text})
+	}
+
+	return parts
+}
+
+// Render produces the entry's audio with its directives applied. generate is called for every stretch of text
+// between pauses; the rate is applied to the speech only, so pauses keep their length.
+func Render(entry *Entry, generate func(text string) (*audio.Audio, error)) (*audio.Audio, error) {
+	parts := entry.parts()
+
+	speech := make([]*audio.Audio, len(parts))
+	var format *audio.AudioMetadata
+
+	for index, part := range parts {
+		if part.text == "" {
+			continue
+		}
+
+		audioObject, err := generate(part.text)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := audioObject.ToPCM(); err != nil {
+			return nil, err
+		}
+		if err := audioObject.ChangeTempo(entry.Rate()); err != nil {
+			return nil, err
+		}
+
+		speech[index] = audioObject
+		if format == nil {
+			format = &audioObject.Metadata
+		}
+	}
+
+	if format == nil {
+		return nil, fmt.Errorf("line %d: no text to speak", entry.Line)
+	}
+
+	clips := make([]*audio.Audio, len(parts))
+	for index, part := range parts {
+		if speech[index] != nil {
+			clips[index] = speech[index]
+		} else {
+			clips[index] = audio.NewSilence(part.pause, format.SampleRate, format.Channels, format.BitDepth)
+		}
+	}
+
+	rendered, err := audio.Concatenate(clips...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rendered.ApplyGain(entry.Gain()); err != nil {
+		return nil, err
+	}
+	return rendered, nil
+}
@@ -0,0 +1,160 @@
+// Package script parses narration scripts into chapters, scenes and spoken entries.
+//
+// A script is made of "Character: text" lines:
+//
+//	# Lines starting with "#" are comments
+//	[chapter The Crossing]
+//	[scene The ferry, at dawn]
+//	Narrator: The ferry was late. [pause 1.5s] Again.
+//	Mara: [rate 1.2] [volume -3dB] If we miss it,
+//	  we wait until noon.
+//	Jon: [voice piper:en_US-lessac-medium:0] Then we wait.
+//	[pause 2s]
+//
+// A line continues the previous entry when it is indented, or when it follows it directly and has no
+// "Character:" prefix of its own. A blank line ends an entry.
+//
+// Directives are written in square brackets:
+//
+//	[pause 1.5s]     silence at that point of the line, "500ms" and plain seconds ("2") work too
+//	[rate 1.2]       speaking rate for the whole line, 1 is normal speed
+//	[volume -3dB]    gain for the whole line in decibels
+//	[voice e:m:v]    speak the line with engine:model:voice instead of the character's profile voice
+//	[chapter Title]  starts a chapter, must be on its own line
+//	[scene Title]    starts a scene, must be on its own line
+//
+// Directives on a line of their own apply to the next entry, unless the line is indented: then they continue the
+// entry like any other indented line. Brackets holding anything else, like "[laughs]",
+// are left in the text for engines that understand them.
+package script
+
+import (
+	"fmt"
+	"nstudio/app/common/util"
+	"strings"
+	"time"
+)
+
+type Kind string
+
+const (
+	Pause  Kind = "pause"
+	Rate   Kind = "rate"
+	Volume Kind = "volume"
+	Voice  Kind = "voice"
+)
+
+type Directive struct {
+	Kind Kind `json:"kind"`
+	Line int  `json:"line"`
+
+	// Offset is the byte position in the entry's text where the directive was written
+	Offset int `json:"offset"`
+
+	Seconds  float64 `json:"seconds,omitempty"`  // pause
+	Rate     float64 `json:"rate,omitempty"`     // rate
+	Decibels float64 `json:"decibels,omitempty"` // volume
+	Voice    string  `json:"voice,omitempty"`    // voice, as engine:model:voice
+}
+
+func (directive Directive) Duration() time.Duration {
+	return time.Duration(directive.Seconds * float64(time.Second))
+}
+
+// Entry is one spoken line. Text holds the dialogue without its directives, continuation lines joined by a space.
+// A voice override is stored in the embedded message's Voice.
+type Entry struct {
+	util.CharacterMessage
+	Line       int         `json:"line"`
+	EndLine    int         `json:"end_line"`
+	Directives []Directive `json:"directives,omitempty"`
+}
+
+type Scene struct {
+	Title   string   `json:"title,omitempty"`
+	Line    int      `json:"line,omitempty"`
+	Entries []*Entry `json:"entries"`
+}
+
+type Chapter struct {
+	Title  string   `json:"title,omitempty"`
+	Line   int      `json:"line,omitempty"`
+	Scenes []*Scene `json:"scenes"`
+}
+
+// Script is the parsed form of a script. Entries before the first header go into an untitled chapter and scene.
+type Script struct {
+	Chapters []*Chapter `json:"chapters"`
+}
+
+// Error is a problem found on one line of a script
+type Error struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+func (err Error) Error() string {
+	return fmt.Sprintf("line %d: %s", err.Line, err.Message)
+}
+
+// Errors holds every problem found in a script, in line order
+type Errors []Error
+
+func (errs Errors) Error() string {
+	messages := make([]string, len(errs))
+	for index, err := range errs {
+		messages[index] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Entries returns every entry of the script in order
+func (script *Script) Entries() []*Entry {
+	var entries []*Entry
+	for _, chapter := range script.Chapters {
+		for _, scene := range chapter.Scenes {
+			entries = append(entries, scene.Entries...)
+		}
+	}
+	return entries
+}
+
+// Messages returns the entries as plain messages, dropping their directives
+func (script *Script) Messages() []util.CharacterMessage {
+	entries := script.Entries()
+	messages := make([]util.CharacterMessage, len(entries))
+	for index, entry := range entries {
+		messages[index] = entry.CharacterMessage
+	}
+	return messages
+}
+
+// HasDirectives reports whether the entry needs more than a plain generation with the character's voice
+func (entry *Entry) HasDirectives() bool {
+	return len(entry.Directives) > 0
+}
+
+// Rate returns the speaking rate of the entry, 1 when none was given
+func (entry *Entry) Rate() float64 {
+	for _, directive := range entry.Directives {
+		if directive.Kind == Rate {
+			return directive.Rate
+		}
+	}
+	return 1
+}
+
+// Gain returns the volume change of the entry in decibels
+func (entry *Entry) Gain() float64 {
+	for _, directive := range entry.Directives {
+		if directive.Kind == Volume {
+			return directive.Decibels
+		}
+	}
+	return 0
+}
+
+// HasVoiceOverride reports whether the entry names its own voice instead of using the profile's
+func (entry *Entry) HasVoiceOverride() bool {
+	return entry.Voice.Engine != ""
+}
@@ -0,0 +1,189 @@
+package script
+
+import (
+	"fmt"
+	"nstudio/app/common/util"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	speakerRegex    = regexp.MustCompile(`^([A-Z][\p{L}0-9 .'-]{0,39}?)\s*:\s+(.+)$`)
+	vttVoiceRegex   = regexp.MustCompile(`^<v(?:\.[^\s>]*)?\s+([^>]+)>`)
+	vttCommentRegex = regexp.MustCompile(`^(NOTE|STYLE|REGION)(\s|$)`)
+)
+
+// subtitleBlock is one blank-line separated block of an SRT or WebVTT file
+type subtitleBlock struct {
+	line  int
+	lines []string
+}
+
+func subtitleBlocks(text string) []subtitleBlock {
+	var blocks []subtitleBlock
+	var current *subtitleBlock
+
+	for index, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if strings.TrimSpace(line) == "" {
+			current = nil
+			continue
+		}
+		if current == nil {
+			blocks = append(blocks, subtitleBlock{line: index + 1})
+			current = &blocks[len(blocks)-1]
+		}
+		current.lines = append(current.lines, line)
+	}
+	return blocks
+}
+
+// parseTiming reads "start --> end", ignoring the cue settings WebVTT allows after the end time
+func parseTiming(line string) (time.Duration, time.Duration, error) {
+	start, rest, found := strings.Cut(line, "-->")
+	if !found {
+		return 0, 0, fmt.Errorf("missing \"-->\" in timing line")
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return 0, 0, fmt.Errorf("missing end time")
+	}
+
+	startTime, err := parseTimestamp(start)
+	if err != nil {
+		return 0, 0, err
+	}
+	endTime, err := parseTimestamp(fields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	if endTime < startTime {
+		return 0, 0, fmt.Errorf("cue ends before it starts")
+	}
+	return startTime, endTime, nil
+}
+
+// speaker splits a "NAME: text" prefix off subtitle text
+func speaker(text, fallback string) (string, string) {
+	if match := speakerRegex.FindStringSubmatch(text); match != nil {
+		return strings.TrimSpace(match[1]), match[2]
+	}
+	return fallback, text
+}
+
+// ImportSRT reads SubRip subtitles. A cue starting with "NAME:" is spoken by that character.
+func ImportSRT(text string, options ImportOptions) ([]util.CharacterMessage, error) {
+	var messages []util.CharacterMessage
+	var errs Errors
+
+	for index, block := range subtitleBlocks(text) {
+		lines := block.lines
+		line := block.line
+
+		id := strconv.Itoa(index + 1)
+		if !strings.Contains(lines[0], "-->") {
+			id = strings.TrimSpace(lines[0])
+			lines = lines[1:]
+			line++
+		}
+
+		if len(lines) == 0 {
+			errs = append(errs, Error{Line: block.line, Message: "cue has no timing line"})
+			continue
+		}
+
+		start, end, err := parseTiming(lines[0])
+		if err != nil {
+			errs = append(errs, Error{Line: line, Message: err.Error()})
+			continue
+		}
+
+		content := cleanText(lines[1:])
+		if content == "" {
+			continue
+		}
+
+		character, content := speaker(content, options.DefaultCharacter)
+		messages = append(messages, util.CharacterMessage{
+			Character: character,
+			Text:      content,
+			Cue:       &util.Cue{ID: id, Start: start, End: end},
+		})
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return messages, nil
+}
+
+// ImportVTT reads WebVTT subtitles. The speaker comes from a <v Name> voice span or a "NAME:" prefix, NOTE,
+// STYLE and REGION blocks are skipped.
+func ImportVTT(text string, options ImportOptions) ([]util.CharacterMessage, error) {
+	blocks := subtitleBlocks(text)
+	if len(blocks) == 0 || !strings.HasPrefix(blocks[0].lines[0], "WEBVTT") {
+		return nil, Errors{{Line: 1, Message: "missing WEBVTT header"}}
+	}
+
+	var messages []util.CharacterMessage
+	var errs Errors
+	cues := 0
+
+	for _, block := range blocks[1:] {
+		lines := block.lines
+		line := block.line
+
+		if vttCommentRegex.MatchString(lines[0]) {
+			continue
+		}
+
+		cues++
+		id := strconv.Itoa(cues)
+		if !strings.Contains(lines[0], "-->") {
+			id = strings.TrimSpace(lines[0])
+			lines = lines[1:]
+			line++
+		}
+
+		if len(lines) == 0 {
+			errs = append(errs, Error{Line: block.line, Message: "cue has no timing line"})
+			continue
+		}
+
+		start, end, err := parseTiming(lines[0])
+		if err != nil {
+			errs = append(errs, Error{Line: line, Message: err.Error()})
+			continue
+		}
+
+		payload := lines[1:]
+		character := ""
+		if len(payload) > 0 {
+			if match := vttVoiceRegex.FindStringSubmatch(strings.TrimSpace(payload[0])); match != nil {
+				character = strings.TrimSpace(match[1])
+			}
+		}
+
+		content := cleanText(payload)
+		if content == "" {
+			continue
+		}
+		if character == "" {
+			character, content = speaker(content, options.DefaultCharacter)
+		}
+
+		messages = append(messages, util.CharacterMessage{
+			Character: character,
+			Text:      content,
+			Cue:       &util.Cue{ID: id, Start: start, End: end},
+		})
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return messages, nil
+}
@@ -0,0 +1,335 @@
+// Package ssml reads the subset of SSML accepted on every input path.
+//
+// Text is treated as SSML when it starts with <speak>. Google receives the document as is, every other engine
+// gets it flattened into segments: <break> becomes silence, <prosody> becomes a rate, pitch and volume change
+// for the text inside it, <say-as interpret-as="characters"> is spelled out, <sub> is replaced by its alias and
+// <mark> is dropped. Every other tag (<emphasis>, <p>, <s>, <lang>, <voice>, <phoneme>, <audio>, ...) is removed
+// and its text kept.
+package ssml
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const maxBreak = 10 * time.Second
+
+// Segment is a stretch of text spoken with the same prosody, or a break when Text is empty
+type Segment struct {
+	Text  string
+	Break time.Duration
+
+	Rate   float64 // speaking rate multiplier, 1 is normal
+	Pitch  float64 // semitones
+	Volume float64 // decibels
+}
+
+type prosody struct {
+	rate   float64
+	pitch  float64
+	volume float64
+}
+
+var breakStrengths = map[string]time.Duration{
+	"none":     0,
+	"x-weak":   100 * time.Millisecond,
+	"weak":     250 * time.Millisecond,
+	"medium":   500 * time.Millisecond,
+	"strong":   750 * time.Millisecond,
+	"x-strong": time.Second,
+}
+
+var rateNames = map[string]float64{"x-slow": 0.5, "slow": 0.75, "medium": 1, "default": 1, "fast": 1.25, "x-fast": 1.5}
+
+var pitchNames = map[string]float64{"x-low": -6, "low": -3, "medium": 0, "default": 0, "high": 3, "x-high": 6}
+
+var volumeNames = map[string]float64{"silent": -60, "x-soft": -12, "soft": -6, "medium": 0, "default": 0, "loud": 6, "x-loud": 12}
+
+// IsSSML reports whether text is an SSML document
+func IsSSML(text string) bool {
+	text = strings.TrimSpace(strings.TrimPrefix(text, "\ufeff"))
+	if strings.HasPrefix(text, "<?xml") {
+		if end := strings.Index(text, "?>"); end >= 0 {
+			text = strings.TrimSpace(text[end+2:])
+		}
+	}
+	return len(text) >= 6 && strings.EqualFold(text[:6], "<speak")
+}
+
+// Parse flattens an SSML document into segments. Neighbouring breaks are merged and segments with the same
+// prosody are joined, so the result alternates between speech and silence wherever it can.
+func Parse(document string) ([]Segment, error) {
+	decoder := xml.NewDecoder(strings.NewReader(strings.TrimPrefix(document, "\ufeff")))
+	decoder.Strict = true
+	decoder.Entity = xml.HTMLEntity
+
+	var segments []Segment
+	stack := []prosody{{rate: 1}}
+
+	// skip counts the elements whose content is not spoken, like <sub> once its alias is used
+	skip := 0
+	// names tracks open elements, text outside of <speak> is not spoken
+	var names []string
+	root := false
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid SSML: %w", err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(element.Name.Local)
+			if !root {
+				if name != "speak" {
+					return nil, fmt.Errorf("invalid SSML: the root element must be <speak>, not <%s>", name)
+				}
+				root = true
+			}
+			names = append(names, name)
+
+			current := stack[len(stack)-1]
+			if skip > 0 {
+				stack = append(stack, current)
+				skip++
+				continue
+			}
+
+			switch name {
+			case "break":
+				duration, err := breakDuration(element)
+				if err != nil {
+					return nil, err
+				}
+				segments = appendBreak(segments, duration)
+
+			case "prosody":
+				changed, err := applyProsody(current, element)
+				if err != nil {
+					return nil, err
+				}
+				current = changed
+
+			case "sub":
+				if alias := attribute(element, "alias"); alias != "" {
+					segments = appendText(segments, alias, current)
+					skip = 1
+				}
+
+			case "say-as":
+				interpret := strings.ToLower(attribute(element, "interpret-as"))
+				if interpret == "characters" || interpret == "spell-out" || interpret == "verbatim" {
+					var content string
+					if err := decoder.DecodeElement(&content, &element); err != nil {
+						return nil, fmt.Errorf("invalid SSML: %w", err)
+					}
+					segments = appendText(segments, spellOut(content), current)
+					names = names[:len(names)-1]
+					continue
+				}
+
+			case "mark", "desc":
+				skip = 1
+			}
+
+			stack = append(stack, current)
+
+		case xml.EndElement:
+			if len(names) > 0 {
+				names = names[:len(names)-1]
+			}
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+			if skip > 0 {
+				skip--
+			}
+
+		case xml.CharData:
+			if skip == 0 && root && len(names) > 0 {
+				segments = appendText(segments, string(element), stack[len(stack)-1])
+			}
+		}
+	}
+
+	if !root {
+		return nil, fmt.Errorf("invalid SSML: missing <speak> element")
+	}
+
+	for index := range segments {
+		segments[index].Text = strings.TrimSpace(segments[index].Text)
+	}
+	return segments, nil
+}
+
+// Strip returns the spoken text of a document, with breaks and prosody dropped
+func Strip(document string) (string, error) {
+	segments, err := Parse(document)
+	if err != nil {
+		return "", err
+	}
+
+	var parts []string
+	for _, segment := range segments {
+		if segment.Text != "" {
+			parts = append(parts, segment.Text)
+		}
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// appendText adds text to the last segment when the prosody is unchanged. Runs of whitespace are collapsed, but
+// kept at the edges, since they decide whether the text around a tag is one word or two.
+func appendText(segments []Segment, text string, current prosody) []Segment {
+	words := strings.Join(strings.Fields(text), " ")
+	if words == "" {
+		if last := len(segments) - 1; last >= 0 && segments[last].Text != "" && text != "" {
+			segments[last].Text += " "
+		}
+		return segments
+	}
+
+	if strings.TrimLeftFunc(text, unicode.IsSpace) != text {
+		words = " " + words
+	}
+	if strings.TrimRightFunc(text, unicode.IsSpace) != text {
+		words += " "
+	}
+
+	if last := len(segments) - 1; last >= 0 && segments[last].Text != "" && segments[last].prosody() == current {
+		segments[last].Text += words
+		return segments
+	}
+
+	return append(segments, Segment{Text: words, Rate: current.rate, Pitch: current.pitch, Volume: current.volume})
+}
+
+func appendBreak(segments []Segment, duration time.Duration) []Segment {
+	if duration <= 0 {
+		return segments
+	}
+	if last := len(segments) - 1; last >= 0 && segments[last].Text == "" {
+		segments[last].Break += duration
+		return segments
+	}
+	return append(segments, Segment{Break: duration})
+}
+
+func (segment Segment) prosody() prosody {
+	return prosody{rate: segment.Rate, pitch: segment.Pitch, volume: segment.Volume}
+}
+
+func attribute(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if strings.EqualFold(attr.Name.Local, name) {
+			return strings.TrimSpace(attr.Value)
+		}
+	}
+	return ""
+}
+
+func breakDuration(element xml.StartElement) (time.Duration, error) {
+	if value := attribute(element, "time"); value != "" {
+		duration, err := time.ParseDuration(strings.ToLower(value))
+		if err != nil || duration < 0 {
+			return 0, fmt.Errorf("invalid SSML: <break time=%q>, expected a duration like 500ms or 2s", value)
+		}
+		return min(duration, maxBreak), nil
+	}
+
+	strength := strings.ToLower(attribute(element, "strength"))
+	if strength == "" {
+		strength = "medium"
+	}
+	duration, ok := breakStrengths[strength]
+	if !ok {
+		return 0, fmt.Errorf("invalid SSML: <break strength=%q>", strength)
+	}
+	return duration, nil
+}
+
+// applyProsody combines a <prosody> element with the prosody around it. Rates multiply, pitch and volume add up.
+func applyProsody(current prosody, element xml.StartElement) (prosody, error) {
+	if value := strings.ToLower(attribute(element, "rate")); value != "" {
+		rate, ok := rateNames[value]
+		if !ok {
+			number, err := parsePercent(value)
+			if err != nil {
+				return current, fmt.Errorf("invalid SSML: <prosody rate=%q>", value)
+			}
+			// A plain percentage is relative to normal speed, a signed one is a change, a bare number a multiplier
+			rate = number
+			if strings.HasSuffix(value, "%") {
+				rate = number / 100
+				if strings.HasPrefix(value, "+") || strings.HasPrefix(value, "-") {
+					rate = 1 + rate
+				}
+			}
+		}
+		if rate <= 0 {
+			return current, fmt.Errorf("invalid SSML: <prosody rate=%q>", value)
+		}
+		current.rate *= rate
+	}
+
+	if value := strings.ToLower(attribute(element, "pitch")); value != "" {
+		pitch, ok := pitchNames[value]
+		if !ok {
+			var err error
+			switch {
+			case strings.HasSuffix(value, "st"):
+				pitch, err = strconv.ParseFloat(strings.TrimSuffix(value, "st"), 64)
+			case strings.HasSuffix(value, "%"):
+				var percent float64
+				percent, err = parsePercent(value)
+				if err == nil && percent > -100 {
+					pitch = 12 * math.Log2(1+percent/100)
+				}
+			default:
+				err = fmt.Errorf("unsupported pitch")
+			}
+			if err != nil {
+				return current, fmt.Errorf("invalid SSML: <prosody pitch=%q>, expected a name, semitones (+2st) or a percentage", value)
+			}
+		}
+		current.pitch += pitch
+	}
+
+	if value := strings.ToLower(attribute(element, "volume")); value != "" {
+		volume, ok := volumeNames[value]
+		if !ok {
+			var err error
+			volume, err = strconv.ParseFloat(strings.TrimSuffix(value, "db"), 64)
+			if err != nil {
+				return current, fmt.Errorf("invalid SSML: <prosody volume=%q>, expected a name or decibels like -6dB", value)
+			}
+		}
+		current.volume += volume
+	}
+
+	return current, nil
+}
+
+func parsePercent(value string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+}
+
+// spellOut separates letters and digits so engines read them one by one
+func spellOut(text string) string {
+	var letters []string
+	for _, character := range strings.Join(strings.Fields(text), "") {
+		letters = append(letters, string(character))
+	}
+	return strings.Join(letters, " ")
+}
@@ -0,0 +1,95 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+)
+
+// sentenceAbbreviations are words whose trailing period does not end a sentence
+var sentenceAbbreviations = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "st": true, "jr": true, "sr": true,
+	"prof": true, "mt": true, "vs": true, "etc": true, "e.g": true, "i.e": true, "no": true,
+}
+
+// SplitSentences breaks text into sentences on terminal punctuation and blank lines, keeping the punctuation.
+// Common abbreviations and single-letter initials do not end a sentence.
+func SplitSentences(text string) []string {
+	runes := []rune(text)
+	var sentences []string
+	start := 0
+
+	flush := func(end int) {
+		if sentence := strings.TrimSpace(string(runes[start:end])); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = end
+	}
+
+	for i := 0; i < len(runes); i++ {
+		char := runes[i]
+
+		if char == '\n' {
+			next := i + 1
+			for next < len(runes) && runes[next] != '\n' && unicode.IsSpace(runes[next]) {
+				next++
+			}
+			if next < len(runes) && runes[next] == '\n' {
+				flush(i)
+				i = next
+			}
+			continue
+		}
+
+		if !isSentenceTerminator(char) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && (isSentenceTerminator(runes[end]) || isClosingPunctuation(runes[end])) {
+			end++
+		}
+
+		if end < len(runes) && !unicode.IsSpace(runes[end]) {
+			i = end - 1
+			continue
+		}
+
+		if char == '.' && end == i+1 && isAbbreviation(runes[start:i]) {
+			continue
+		}
+
+		flush(end)
+		i = end - 1
+	}
+
+	flush(len(runes))
+	return sentences
+}
+
+func isSentenceTerminator(char rune) bool {
+	return char == '.' || char == '!' || char == '?' || char == '…'
+}
+
+func isClosingPunctuation(char rune) bool {
+	return char == '"' || char == '\'' || char == ')' || char == ']' || char == '”' || char == '’'
+}
+
+// isAbbreviation reports whether the last word of prefix is a known abbreviation or an initial
+func isAbbreviation(prefix []rune) bool {
+	wordStart := len(prefix)
+	for wordStart > 0 && !unicode.IsSpace(prefix[wordStart-1]) {
+		wordStart--
+	}
+
+	word := strings.TrimLeft(string(prefix[wordStart:]), "\"'([“‘")
+	if word == "" {
+		return false
+	}
+
+	letters := []rune(word)
+	if len(letters) == 1 && unicode.IsUpper(letters[0]) {
+		return true
+	}
+
+	return sentenceAbbreviations[strings.ToLower(word)]
+}
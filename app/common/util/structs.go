@@ -3,6 +3,7 @@ package util
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
 type CharacterMessage struct {
@@ -10,6 +11,49 @@ type CharacterMessage struct {
 	Text      string         `json:"text"`
 	Save      bool           `json:"save"`
 	Voice     CharacterVoice `json:"-"`
+	Cue       *Cue           `json:"cue,omitempty"`
+}
+
+// Cue is where an imported message came from: its ID in the source file and, for subtitles, its timing
+type Cue struct {
+	ID    string        `json:"id,omitempty"`
+	Start time.Duration `json:"-"`
+	End   time.Duration `json:"-"`
+}
+
+// HasTiming reports whether the cue came with start and end times
+func (cue *Cue) HasTiming() bool {
+	return cue != nil && cue.End > cue.Start
+}
+
+func (cue *Cue) UnmarshalJSON(data []byte) error {
+	type Alias Cue
+	unmarshalTarget := &struct {
+		*Alias
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+	}{
+		Alias: (*Alias)(cue),
+	}
+	if err := json.Unmarshal(data, &unmarshalTarget); err != nil {
+		return err
+	}
+	cue.Start = time.Duration(unmarshalTarget.Start * float64(time.Second))
+	cue.End = time.Duration(unmarshalTarget.End * float64(time.Second))
+	return nil
+}
+
+func (cue Cue) MarshalJSON() ([]byte, error) {
+	type Alias Cue
+	return json.Marshal(&struct {
+		Alias
+		Start float64 `json:"start,omitempty"`
+		End   float64 `json:"end,omitempty"`
+	}{
+		Alias: (Alias)(cue),
+		Start: cue.Start.Seconds(),
+		End:   cue.End.Seconds(),
+	})
 }
 
 type CharacterVoice struct {
@@ -24,6 +24,8 @@ func GetKeys[KeyType comparable, ValueType any](inputMap map[KeyType]ValueType)
 	return keys
 }
 
+var cueIDRegex = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
 func GenerateFilename(message CharacterMessage, index int, outputPath string) string {
 	currentTime := time.Now()
 	//datePath := currentTime.Format("2006-01-02_15-04-05")
@@ -33,6 +35,9 @@ func GenerateFilename(message CharacterMessage, index int, outputPath string) st
 	text = strings.ReplaceAll(text, " ", "_")
 
 	filename := fmt.Sprintf("%d) %s-%s.wav", index, message.Character, text)
+	if message.Cue != nil && message.Cue.ID != "" {
+		filename = fmt.Sprintf("%d) %s %s-%s.wav", index, cueIDRegex.ReplaceAllString(message.Cue.ID, "_"), message.Character, text)
+	}
 
 	outputPath = filepath.Join(outputPath, datePath)
 	outputPath = filepath.Join(outputPath, fileIndex.Timestamp())
@@ -95,6 +100,12 @@ func convertPercentVars(path string) string {
 	return re.ReplaceAllString(path, "${$1}")
 }
 
+// NormalizeText collapses the whitespace of a line into single spaces. Unlike HashText it keeps the case, which
+// changes how some words are spoken.
+func NormalizeText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
 func HashText(text string) string {
 	normalized := strings.ToLower(strings.TrimSpace(text))
 	hash := sha256.Sum256([]byte(normalized))
@@ -20,16 +20,28 @@ type Base struct {
 }
 
 type Settings struct {
-	OutputType OutputType.Option  `json:"outputType"`
-	OutputPath string             `json:"outputPath"`
-	Debug      bool               `json:"debug"`
-	AudioCache AudioCacheSettings `json:"audioCache,omitempty"`
-	Server     ServerSettings     `json:"server,omitempty"`
+	OutputType   OutputType.Option    `json:"outputType"`
+	OutputPath   string               `json:"outputPath"`
+	ProjectsPath string               `json:"projectsPath,omitempty"`
+	Debug        bool                 `json:"debug"`
+	AudioCache   AudioCacheSettings   `json:"audioCache,omitempty"`
+	CueAlignment CueAlignmentSettings `json:"cueAlignment,omitempty"`
+	Server       ServerSettings       `json:"server,omitempty"`
 }
 
+// AudioCacheSettings limit the cache with MaxSizeMB and MaxAgeDays, 0 leaves it unlimited. Past the maximum age
+// lines are removed, past the maximum size the least recently used ones are.
 type AudioCacheSettings struct {
-	Enabled  bool   `json:"enabled"`
-	Location string `json:"location"`
+	Enabled    bool   `json:"enabled"`
+	Location   string `json:"location"`
+	MaxSizeMB  int    `json:"maxSizeMB"`
+	MaxAgeDays int    `json:"maxAgeDays"`
+}
+
+// CueAlignmentSettings control how scripts imported with cue times (SRT, WebVTT) are combined
+type CueAlignmentSettings struct {
+	Enabled    bool    `json:"enabled"`
+	MaxSpeedup float64 `json:"maxSpeedup"`
 }
 
 type ServerSettings struct {
@@ -0,0 +1,286 @@
+// Package project keeps long-form narration on disk.
+//
+// A project is a directory:
+//
+//	project.json   the manifest: name, profile, render settings and every line with its takes
+//	script.txt     the script, in the syntax of the script package
+//	takes/         one WAV per take, named <line ID>-<take number>.wav
+//	renders/       the combined file of the last render, with its subtitles and cue sheet
+//
+// Rendering only generates the lines whose text, voice or directives changed since their last take, every other
+// line reuses the take it already has. Takes are never overwritten, so a line's earlier takes stay
+// available.
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"nstudio/app/common/response"
+	"nstudio/app/common/util"
+	"nstudio/app/config"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+const (
+	ManifestFilename = "project.json"
+	ScriptFilename   = "script.txt"
+
+	takesDirectory   = "takes"
+	rendersDirectory = "renders"
+	manifestVersion  = 1
+)
+
+var idRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._ -]*$`)
+
+// locks serializes saving and rendering per project directory
+var locks sync.Map
+
+type Manifest struct {
+	Version int            `json:"version"`
+	Name    string         `json:"name"`
+	Profile string         `json:"profile"`
+	Created time.Time      `json:"created"`
+	Updated time.Time      `json:"updated"`
+	Render  RenderSettings `json:"render"`
+	Lines   []*Line        `json:"lines"`
+
+	// NextLine numbers new lines, IDs are never reused so take files stay unique
+	NextLine int `json:"nextLine"`
+}
+
+// RenderSettings describe the combined file
+type RenderSettings struct {
+	Pause      float64 `json:"pause"` // seconds between lines
+	SampleRate int     `json:"sampleRate"`
+	Channels   int     `json:"channels"`
+	BitDepth   int     `json:"bitDepth"`
+	Subtitles  bool    `json:"subtitles"` // write SRT, WebVTT and a cue sheet next to the combined file
+}
+
+// Line is an entry of the script as it was last rendered
+type Line struct {
+	ID        string `json:"id"`
+	Character string `json:"character"`
+	Text      string `json:"text"`
+	Hash      string `json:"hash"`     // tts.LineKey of the line's text, voice and directives
+	Selected  int    `json:"selected"` // index in Takes of the take used in the combined file
+	Takes     []Take `json:"takes"`
+}
+
+type Take struct {
+	File     string    `json:"file"` // relative to the project directory
+	Hash     string    `json:"hash"`
+	Text     string    `json:"text"`
+	Created  time.Time `json:"created"`
+	Duration float64   `json:"duration"` // seconds
+}
+
+type Project struct {
+	Path     string   `json:"path"`
+	Script   string   `json:"script"`
+	Manifest Manifest `json:"manifest"`
+}
+
+func DefaultRenderSettings() RenderSettings {
+	return RenderSettings{
+		Pause:      1,
+		SampleRate: 48000,
+		Channels:   1,
+		BitDepth:   16,
+		Subtitles:  true,
+	}
+}
+
+// Create makes a new project in an empty or missing directory
+func Create(path, name, profileID string) (*Project, error) {
+	if _, err := os.Stat(filepath.Join(path, ManifestFilename)); err == nil {
+		return nil, fmt.Errorf("a project already exists in %s", path)
+	}
+
+	if name == "" {
+		name = filepath.Base(path)
+	}
+	if profileID == "" {
+		profileID = "default"
+	}
+
+	now := time.Now()
+	project := &Project{
+		Path: path,
+		Manifest: Manifest{
+			Version:  manifestVersion,
+			Name:     name,
+			Profile:  profileID,
+			Created:  now,
+			Updated:  now,
+			Render:   DefaultRenderSettings(),
+			Lines:    []*Line{},
+			NextLine: 1,
+		},
+	}
+
+	if err := project.Save(); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// Open reads the project in a directory
+func Open(path string) (*Project, error) {
+	manifestData, err := os.ReadFile(filepath.Join(path, ManifestFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no project in %s", path)
+		}
+		return nil, response.Err(err)
+	}
+
+	project := &Project{Path: path}
+	if err := json.Unmarshal(manifestData, &project.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ManifestFilename, err)
+	}
+	if project.Manifest.Version > manifestVersion {
+		return nil, fmt.Errorf("project was saved by a newer version (format %d)", project.Manifest.Version)
+	}
+	if project.Manifest.Lines == nil {
+		project.Manifest.Lines = []*Line{}
+	}
+
+	scriptData, err := os.ReadFile(filepath.Join(path, ScriptFilename))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, response.Err(err)
+	}
+	project.Script = string(scriptData)
+
+	return project, nil
+}
+
+// Save writes the manifest and the script. Both are written to a temporary file first, so a crash never leaves a
+// half written project behind.
+func (project *Project) Save() error {
+	unlock := lock(project.Path)
+	defer unlock()
+
+	return project.save()
+}
+
+func (project *Project) save() error {
+	if err := os.MkdirAll(project.Path, 0755); err != nil {
+		return response.Err(fmt.Errorf("failed to create project directory: %v", err))
+	}
+
+	project.Manifest.Updated = time.Now()
+	manifestData, err := json.MarshalIndent(project.Manifest, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	if err := writeFile(filepath.Join(project.Path, ScriptFilename), []byte(project.Script)); err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(project.Path, ManifestFilename), manifestData)
+}
+
+// Line returns the line with the given ID
+func (project *Project) Line(id string) (*Line, error) {
+	for _, line := range project.Manifest.Lines {
+		if line.ID == id {
+			return line, nil
+		}
+	}
+	return nil, fmt.Errorf("no line %q in the project", id)
+}
+
+// SelectTake makes an earlier take of a line the one used in the combined file. The take must have been
+// recorded for the line's current text.
+func (project *Project) SelectTake(lineID string, take int) error {
+	line, err := project.Line(lineID)
+	if err != nil {
+		return err
+	}
+	if take < 0 || take >= len(line.Takes) {
+		return fmt.Errorf("line %s has no take %d", lineID, take)
+	}
+	if line.Takes[take].Hash != line.Hash {
+		return fmt.Errorf("take %d of line %s was recorded for different text", take, lineID)
+	}
+
+	line.Selected = take
+	return project.Save()
+}
+
+// Root is the directory the servers keep projects in: the projectsPath setting, or "projects" next to the output
+// directory when it isn't set
+func Root() (string, error) {
+	settings := config.GetSettings()
+
+	if settings.ProjectsPath != "" {
+		err, root := util.ExpandPath(settings.ProjectsPath)
+		return root, err
+	}
+
+	err, outputPath := util.ExpandPath(settings.OutputPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(outputPath), "projects"), nil
+}
+
+// Resolve returns the directory of a project ID inside root. IDs are plain names, so a client can't reach
+// outside of root.
+func Resolve(root, id string) (string, error) {
+	if !idRegex.MatchString(id) || id == "." || id == ".." {
+		return "", fmt.Errorf("invalid project ID %q", id)
+	}
+	return filepath.Join(root, id), nil
+}
+
+// List returns the IDs of the projects inside root
+func List(root string) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, response.Err(err)
+	}
+
+	ids := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(root, entry.Name(), ManifestFilename)); err == nil {
+			ids = append(ids, entry.Name())
+		}
+	}
+	return ids, nil
+}
+
+func lock(path string) func() {
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		absolute = path
+	}
+
+	value, _ := locks.LoadOrStore(absolute, &sync.Mutex{})
+	mutex := value.(*sync.Mutex)
+	mutex.Lock()
+	return mutex.Unlock
+}
+
+func writeFile(path string, data []byte) error {
+	temporary := path + ".tmp"
+	if err := os.WriteFile(temporary, data, 0644); err != nil {
+		return response.Err(err)
+	}
+	if err := os.Rename(temporary, path); err != nil {
+		os.Remove(temporary)
+		return response.Err(err)
+	}
+	return nil
+}
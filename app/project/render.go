@@ -0,0 +1,359 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"nstudio/app/common/audio"
+	"nstudio/app/common/response"
+	"nstudio/app/common/script"
+	"nstudio/app/common/ssml"
+	"nstudio/app/common/status"
+	"nstudio/app/server/synthesis"
+	"nstudio/app/tts"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+const combinedFilename = "combined.wav"
+
+type LineStatus string
+
+const (
+	Unchanged LineStatus = "unchanged"
+	Changed   LineStatus = "changed"
+	Added     LineStatus = "new"
+	Retaken   LineStatus = "retake"
+)
+
+type RenderOptions struct {
+	// Retake records a new take for these line IDs even when they didn't change
+	Retake []string `json:"retake,omitempty"`
+	// Force records a new take for every line
+	Force bool `json:"force,omitempty"`
+	// DryRun only reports what the render would do, nothing is generated and the project isn't saved
+	DryRun bool `json:"dryRun,omitempty"`
+	// Progress is called after each entry of the script was generated or reused, with its index
+	Progress func(index, total int) `json:"-"`
+}
+
+type LineResult struct {
+	ID        string     `json:"id"`
+	Character string     `json:"character"`
+	Text      string     `json:"text"`
+	Status    LineStatus `json:"status"`
+	Take      int        `json:"take"`
+}
+
+type RenderResult struct {
+	DryRun    bool         `json:"dryRun,omitempty"`
+	Generated int          `json:"generated"`
+	Reused    int          `json:"reused"`
+	Removed   int          `json:"removed"`
+	Output    string       `json:"output"`
+	Lines     []LineResult `json:"lines"`
+}
+
+// step is what a render does with one entry of the script
+type step struct {
+	entry  *script.Entry
+	line   *Line
+	hash   string
+	status LineStatus
+}
+
+// Render generates the lines that changed since the last render and combines every line's selected take into
+// renders/combined.wav. The manifest is saved even when generating a line fails, so the takes recorded before it
+// are kept, the same goes for cancelling ctx. A dry run leaves the project's lines as they were planned, without
+// saving them.
+func (project *Project) Render(ctx context.Context, options RenderOptions) (*RenderResult, error) {
+	unlock := lock(project.Path)
+	defer unlock()
+
+	parsed, err := script.Parse(project.Script)
+	if err != nil {
+		return nil, err
+	}
+	entries := parsed.Entries()
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("the script has no lines to render")
+	}
+
+	steps, removed, err := project.plan(entries, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.DryRun {
+		result := &RenderResult{DryRun: true, Removed: removed, Lines: make([]LineResult, len(steps))}
+		for index, step := range steps {
+			if step.status == Unchanged {
+				result.Reused++
+			} else {
+				result.Generated++
+			}
+			result.Lines[index] = LineResult{
+				ID:        step.line.ID,
+				Character: step.line.Character,
+				Text:      step.line.Text,
+				Status:    step.status,
+				Take:      step.line.Selected,
+			}
+		}
+		return result, nil
+	}
+
+	project.Manifest.Lines = make([]*Line, len(steps))
+	for index, step := range steps {
+		project.Manifest.Lines[index] = step.line
+	}
+
+	result := &RenderResult{Removed: removed, Lines: make([]LineResult, len(steps))}
+
+	for index, step := range steps {
+		if step.status != Unchanged {
+			status.Set(status.Generating, fmt.Sprintf("Line %d of %d", index+1, len(steps)))
+
+			if err := project.record(ctx, step); err != nil {
+				project.save()
+				return nil, fmt.Errorf("line %d (%s): %w", step.entry.Line, step.entry.Character, err)
+			}
+			result.Generated++
+		} else {
+			result.Reused++
+		}
+
+		result.Lines[index] = LineResult{
+			ID:        step.line.ID,
+			Character: step.line.Character,
+			Text:      step.line.Text,
+			Status:    step.status,
+			Take:      step.line.Selected,
+		}
+		if options.Progress != nil {
+			options.Progress(index, len(steps))
+		}
+	}
+
+	output, err := project.combine()
+	if err != nil {
+		project.save()
+		return nil, err
+	}
+	result.Output = output
+
+	if err := project.save(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// plan pairs the entries of the script with the lines of the last render. A line with a take for exactly the
+// same entry is reused wherever it moved to; the remaining entries take over the line at their position, which
+// keeps its take history, or become new lines.
+func (project *Project) plan(entries []*script.Entry, options RenderOptions) ([]step, int, error) {
+	previous := project.Manifest.Lines
+	used := make([]bool, len(previous))
+
+	byHash := map[string][]int{}
+	for index, line := range previous {
+		for _, take := range line.Takes {
+			if !slices.Contains(byHash[take.Hash], index) {
+				byHash[take.Hash] = append(byHash[take.Hash], index)
+			}
+		}
+	}
+
+	steps := make([]step, len(entries))
+	for index, entry := range entries {
+		voice, err := tts.EntryVoice(project.Manifest.Profile, entry)
+		if err != nil {
+			return nil, 0, err
+		}
+		steps[index] = step{entry: entry, hash: tts.LineKey(entry, voice)}
+
+		for _, candidate := range byHash[steps[index].hash] {
+			if !used[candidate] {
+				used[candidate] = true
+				steps[index].line = previous[candidate]
+				steps[index].status = Unchanged
+				break
+			}
+		}
+	}
+
+	for index := range steps {
+		if steps[index].line != nil {
+			continue
+		}
+		if index < len(previous) && !used[index] {
+			used[index] = true
+			steps[index].line = previous[index]
+			steps[index].status = Changed
+			continue
+		}
+
+		steps[index].line = &Line{ID: fmt.Sprintf("L%d", project.Manifest.NextLine), Takes: []Take{}}
+		steps[index].status = Added
+		project.Manifest.NextLine++
+	}
+
+	for index := range steps {
+		step := &steps[index]
+		step.line.Character = step.entry.Character
+		step.line.Text = step.entry.Text
+		step.line.Hash = step.hash
+
+		if step.status != Unchanged {
+			continue
+		}
+		if options.Force || slices.Contains(options.Retake, step.line.ID) {
+			step.status = Retaken
+			continue
+		}
+
+		// Keep the selected take when it belongs to this text, otherwise use the latest one that does
+		if step.line.Selected >= len(step.line.Takes) || step.line.Takes[step.line.Selected].Hash != step.hash {
+			for take := len(step.line.Takes) - 1; take >= 0; take-- {
+				if step.line.Takes[take].Hash == step.hash {
+					step.line.Selected = take
+					break
+				}
+			}
+		}
+	}
+
+	removed := 0
+	for _, isUsed := range used {
+		if !isUsed {
+			removed++
+		}
+	}
+	return steps, removed, nil
+}
+
+// record generates a new take of a line. Retakes skip the audio cache, it would only hand back the same take.
+func (project *Project) record(ctx context.Context, step step) error {
+	var audioObject *audio.Audio
+	var err error
+
+	if step.status == Retaken {
+		audioObject, err = project.generateUncached(ctx, step.entry)
+	} else {
+		audioObject, err = synthesis.GenerateEntry(ctx, project.Manifest.Profile, step.entry)
+	}
+	if err != nil {
+		return err
+	}
+
+	wavData, err := audioObject.ToWAV()
+	if err != nil {
+		return err
+	}
+
+	filename := filepath.Join(takesDirectory, fmt.Sprintf("%s-%d.wav", step.line.ID, len(step.line.Takes)+1))
+	if err := os.MkdirAll(filepath.Join(project.Path, takesDirectory), 0755); err != nil {
+		return response.Err(err)
+	}
+	if err := os.WriteFile(filepath.Join(project.Path, filename), wavData, 0644); err != nil {
+		return response.Err(err)
+	}
+
+	step.line.Takes = append(step.line.Takes, Take{
+		File:     filepath.ToSlash(filename),
+		Hash:     step.hash,
+		Text:     step.entry.Text,
+		Created:  time.Now(),
+		Duration: duration(audioObject).Seconds(),
+	})
+	step.line.Selected = len(step.line.Takes) - 1
+	return nil
+}
+
+func (project *Project) generateUncached(ctx context.Context, entry *script.Entry) (*audio.Audio, error) {
+	voice, err := tts.EntryVoice(project.Manifest.Profile, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return script.Render(entry, func(text string) (*audio.Audio, error) {
+		return tts.GenerateAudio(ctx, voice, text)
+	})
+}
+
+// OutputPath is where the combined file of the last render is
+func (project *Project) OutputPath() string {
+	return filepath.Join(project.Path, rendersDirectory, combinedFilename)
+}
+
+// combine joins the selected takes into renders/combined.wav, with subtitles when the render settings ask for them
+func (project *Project) combine() (string, error) {
+	settings := project.Manifest.Render
+	defaults := DefaultRenderSettings()
+	if settings.SampleRate <= 0 {
+		settings.SampleRate = defaults.SampleRate
+	}
+	if settings.Channels <= 0 {
+		settings.Channels = defaults.Channels
+	}
+	if settings.BitDepth <= 0 {
+		settings.BitDepth = defaults.BitDepth
+	}
+	pause := time.Duration(max(settings.Pause, 0) * float64(time.Second))
+
+	rendersPath := filepath.Join(project.Path, rendersDirectory)
+	if err := os.MkdirAll(rendersPath, 0755); err != nil {
+		return "", response.Err(err)
+	}
+
+	files := make([]string, len(project.Manifest.Lines))
+	for index, line := range project.Manifest.Lines {
+		files[index] = filepath.Join(project.Path, filepath.FromSlash(line.Takes[line.Selected].File))
+	}
+
+	outputPath := project.OutputPath()
+	clips, err := audio.JoinWAVFiles(files, outputPath, pause, settings.SampleRate, settings.Channels, settings.BitDepth)
+	if err != nil {
+		return "", err
+	}
+
+	if settings.Subtitles {
+		lines := make([]script.TimedLine, len(clips))
+		for index, clip := range clips {
+			line := project.Manifest.Lines[index]
+			lines[index] = script.TimedLine{
+				ID:        line.ID,
+				Character: line.Character,
+				Text:      spokenText(line.Text),
+				File:      line.Takes[line.Selected].File,
+				Start:     clip.Start,
+				End:       clip.End,
+			}
+		}
+		if err := script.WriteCueFiles(rendersPath, combinedFilename, pause, lines); err != nil {
+			return "", err
+		}
+	}
+
+	return outputPath, nil
+}
+
+func spokenText(text string) string {
+	if ssml.IsSSML(text) {
+		if plain, err := ssml.Strip(text); err == nil {
+			return plain
+		}
+	}
+	return text
+}
+
+func duration(audioObject *audio.Audio) time.Duration {
+	pcmData, err := audioObject.ToPCM()
+	metadata := audioObject.Metadata
+	if err != nil || metadata.SampleRate <= 0 || metadata.Channels <= 0 || metadata.BitDepth <= 0 {
+		return 0
+	}
+	frames := len(pcmData) / (metadata.Channels * metadata.BitDepth / 8)
+	return time.Duration(float64(frames) / float64(metadata.SampleRate) * float64(time.Second))
+}
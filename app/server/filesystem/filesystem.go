@@ -0,0 +1,406 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"nstudio/app/common/audio"
+	"nstudio/app/common/script"
+	"nstudio/app/common/util"
+	"nstudio/app/server/synthesis"
+	"nstudio/app/tts/profile"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+type ServerConfig struct {
+	Inbox        string
+	Outbox       string
+	PollInterval time.Duration
+}
+
+const (
+	defaultInbox        = "inbox"
+	defaultOutbox       = "outbox"
+	defaultPollInterval = time.Second
+
+	doneDirectory   = "done"
+	failedDirectory = "failed"
+	resultSuffix    = ".result.json"
+)
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+func (state fileState) equal(other fileState) bool {
+	return state.size == other.size && state.modTime.Equal(other.modTime)
+}
+
+type watcher struct {
+	inbox  string
+	outbox string
+
+	// pending holds what each inbox file looked like on the previous poll. A file is only picked up once
+	// it is unchanged between two polls, so inputs that are still being written are left alone.
+	pending map[string]fileState
+
+	// stuck holds inputs that were processed but could not be moved out of the inbox, so they are not
+	// processed again until they change
+	stuck map[string]fileState
+}
+
+// StartFileSystemServer polls the inbox for .txt scripts and .json requests, writes the generated audio and a
+// <name>.result.json sidecar to the outbox, then moves each input to the inbox's done/ or failed/ directory.
+func StartFileSystemServer(config ServerConfig) error {
+	inbox, err := resolveDirectory(config.Inbox, defaultInbox)
+	if err != nil {
+		return err
+	}
+	outbox, err := resolveDirectory(config.Outbox, defaultOutbox)
+	if err != nil {
+		return err
+	}
+
+	for _, directory := range []string{inbox, filepath.Join(inbox, doneDirectory), filepath.Join(inbox, failedDirectory), outbox} {
+		if err := util.PrepareDirectory(directory); err != nil {
+			return fmt.Errorf("failed to create %s: %w", directory, err)
+		}
+	}
+
+	interval := config.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	fmt.Printf("Watching %s for requests, writing audio to %s\n", inbox, outbox)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	w := &watcher{
+		inbox:   inbox,
+		outbox:  outbox,
+		pending: make(map[string]fileState),
+		stuck:   make(map[string]fileState),
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// A file that is interrupted part way through stays in the inbox and is picked up again on the next start
+		w.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			fmt.Println("\nShutting down server...")
+			fmt.Println("Server stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func resolveDirectory(path, fallback string) (string, error) {
+	if path == "" {
+		path = fallback
+	}
+
+	err, expanded := util.ExpandPath(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(expanded)
+}
+
+func isRequestFile(name string) bool {
+	if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "~") {
+		return false
+	}
+
+	extension := strings.ToLower(filepath.Ext(name))
+	return extension == ".txt" || extension == ".json"
+}
+
+func (w *watcher) poll(ctx context.Context) {
+	entries, err := os.ReadDir(w.inbox)
+	if err != nil {
+		log.Error("failed to read inbox", "path", w.inbox, "error", err)
+		return
+	}
+
+	type readyFile struct {
+		name  string
+		state fileState
+	}
+
+	var ready []readyFile
+	seen := make(map[string]bool)
+
+	for _, entry := range entries {
+		if entry.IsDir() || !isRequestFile(entry.Name()) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		name := entry.Name()
+		state := fileState{size: info.Size(), modTime: info.ModTime()}
+		seen[name] = true
+
+		if stuck, exists := w.stuck[name]; exists {
+			if stuck.equal(state) {
+				continue
+			}
+			delete(w.stuck, name)
+		}
+
+		if previous, exists := w.pending[name]; exists && previous.equal(state) {
+			ready = append(ready, readyFile{name: name, state: state})
+		} else {
+			w.pending[name] = state
+		}
+	}
+
+	for name := range w.pending {
+		if !seen[name] {
+			delete(w.pending, name)
+		}
+	}
+	for name := range w.stuck {
+		if !seen[name] {
+			delete(w.stuck, name)
+		}
+	}
+
+	// Oldest first, so files are answered in the order they were dropped
+	sort.Slice(ready, func(i, j int) bool {
+		if !ready[i].state.modTime.Equal(ready[j].state.modTime) {
+			return ready[i].state.modTime.Before(ready[j].state.modTime)
+		}
+		return ready[i].name < ready[j].name
+	})
+
+	for _, file := range ready {
+		if ctx.Err() != nil {
+			return
+		}
+
+		delete(w.pending, file.name)
+		if !w.process(ctx, file.name) {
+			w.stuck[file.name] = file.state
+		}
+	}
+}
+
+// process handles one input file. It returns false when the input could not be moved out of the inbox.
+func (w *watcher) process(ctx context.Context, name string) bool {
+	path := filepath.Join(w.inbox, name)
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+
+	res := result{
+		Input:     name,
+		Outputs:   []output{},
+		Errors:    []lineError{},
+		StartedAt: time.Now(),
+	}
+
+	log.Info("processing request file", "file", name)
+
+	parsed, err := readRequest(path)
+	if err == nil {
+		res.Profile = parsed.Profile
+		res.Format = parsed.Audio.OutputFormat()
+
+		if _, err = profile.GetManager().GetProfile(parsed.Profile); err != nil {
+			err = fmt.Errorf("profile not found: %s", parsed.Profile)
+		}
+	}
+
+	var scriptErrors script.Errors
+	if errors.As(err, &scriptErrors) {
+		for _, scriptError := range scriptErrors {
+			res.Errors = append(res.Errors, lineError{Line: scriptError.Line, Error: scriptError.Message})
+		}
+	} else if err != nil {
+		res.addError(0, "", err)
+	} else if !w.synthesize(ctx, parsed, base, &res) {
+		log.Info("request file interrupted, leaving it in the inbox", "file", name)
+		return true
+	}
+
+	res.Success = len(res.Errors) == 0
+	res.FinishedAt = time.Now()
+	res.ElapsedMS = res.FinishedAt.Sub(res.StartedAt).Milliseconds()
+
+	if err := w.writeResult(base, res); err != nil {
+		log.Error("failed to write result", "file", name, "error", err)
+	}
+
+	destination := doneDirectory
+	if !res.Success {
+		destination = failedDirectory
+	}
+
+	if err := w.moveInput(name, destination); err != nil {
+		log.Error("failed to move request file", "file", name, "to", destination, "error", err)
+		return false
+	}
+
+	if res.Success {
+		log.Info("request file done", "file", name, "outputs", len(res.Outputs))
+	} else {
+		log.Warn("request file failed", "file", name, "errors", len(res.Errors))
+	}
+	return true
+}
+
+func readRequest(path string) (*job, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return parseJSON(data)
+	}
+	return parseScript(data)
+}
+
+// synthesize generates every message of the job into the outbox, recording each output or error in res.
+// It returns false when ctx was cancelled before all messages were handled.
+func (w *watcher) synthesize(ctx context.Context, parsed *job, base string, res *result) bool {
+	format := parsed.Audio.OutputFormat()
+
+	for index, msg := range parsed.Messages {
+		if ctx.Err() != nil {
+			return false
+		}
+
+		request := synthesis.Request{
+			Profile:   parsed.Profile,
+			Character: msg.Character,
+			Text:      msg.Text,
+			Audio:     parsed.Audio,
+		}
+		if err := request.Validate(); err != nil {
+			res.addError(msg.Line, msg.Character, err)
+			continue
+		}
+
+		var audioObject *audio.Audio
+		var err error
+		if msg.entry != nil {
+			audioObject, err = synthesis.GenerateEntry(ctx, parsed.Profile, msg.entry)
+		} else {
+			audioObject, err = synthesis.Generate(ctx, request)
+		}
+		if err != nil {
+			res.addError(msg.Line, msg.Character, err)
+			continue
+		}
+
+		data, err := synthesis.Encode(audioObject, parsed.Audio)
+		if err != nil {
+			res.addError(msg.Line, msg.Character, err)
+			continue
+		}
+
+		fileName := fmt.Sprintf("%s_%03d.%s", base, index+1, fileExtension(format))
+		if parsed.single {
+			fileName = fmt.Sprintf("%s.%s", base, fileExtension(format))
+		}
+
+		if err := writeFileAtomic(filepath.Join(w.outbox, fileName), data); err != nil {
+			res.addError(msg.Line, msg.Character, err)
+			continue
+		}
+
+		seconds := duration(audioObject)
+		res.Outputs = append(res.Outputs, output{
+			File:       fileName,
+			Line:       msg.Line,
+			Character:  msg.Character,
+			Text:       msg.Text,
+			SampleRate: audioObject.Metadata.SampleRate,
+			Channels:   audioObject.Metadata.Channels,
+			BitDepth:   audioObject.Metadata.BitDepth,
+			Duration:   seconds,
+			Size:       len(data),
+		})
+	}
+
+	return true
+}
+
+func (w *watcher) writeResult(base string, res result) error {
+	data, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(w.outbox, base+resultSuffix), data)
+}
+
+// moveInput moves an inbox file into done/ or failed/, adding a timestamp when the name is already taken
+func (w *watcher) moveInput(name, directory string) error {
+	target := filepath.Join(w.inbox, directory, name)
+	if _, err := os.Stat(target); err == nil {
+		extension := filepath.Ext(name)
+		target = filepath.Join(w.inbox, directory, fmt.Sprintf(
+			"%s-%s%s",
+			strings.TrimSuffix(name, extension),
+			time.Now().Format("20060102-150405.000"),
+			extension,
+		))
+	}
+
+	return os.Rename(filepath.Join(w.inbox, name), target)
+}
+
+// writeFileAtomic writes through a temporary file so tools watching the outbox never read a partial file
+func writeFileAtomic(path string, data []byte) error {
+	temporary := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err := os.WriteFile(temporary, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(temporary, path); err != nil {
+		_ = os.Remove(temporary)
+		return err
+	}
+	return nil
+}
+
+func fileExtension(format string) string {
+	if format == "pcm_s16le" {
+		return "pcm"
+	}
+	return format
+}
+
+// duration decodes to PCM first, since that is what fills in the metadata of compressed audio
+func duration(audioObject *audio.Audio) float64 {
+	pcmData, err := audioObject.ToPCM()
+	if err != nil {
+		return 0
+	}
+
+	metadata := audioObject.Metadata
+	bytesPerSecond := metadata.SampleRate * metadata.Channels * metadata.BitDepth / 8
+	if bytesPerSecond == 0 {
+		return 0
+	}
+	return float64(len(pcmData)) / float64(bytesPerSecond)
+}
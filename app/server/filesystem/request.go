@@ -0,0 +1,158 @@
+package filesystem
+
+import (
+	"encoding/json"
+	"fmt"
+	"nstudio/app/common/audio"
+	"nstudio/app/common/script"
+	"nstudio/app/server/synthesis"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// A drop folder request is either a .txt script or a .json file.
+//
+// Scripts use the editor's format, see the script package. Comments of the form "# profile: <id>" and
+// "# format: <format>" set the profile and output format for the whole file.
+//
+// JSON files hold a single request in the HTTP API's shape, or a list of lines under "messages":
+//
+//	{"profile": "default", "character": "Narrator", "text": "Hello", "options": {"audio": {"format": "mp3"}}}
+//	{"profile": "default", "format": "ogg", "messages": [{"character": "Narrator", "text": "Hello"}]}
+
+const defaultProfile = "default"
+
+var scriptDirectiveRegex = regexp.MustCompile(`^#\s*(profile|format)\s*:\s*(.*?)\s*$`)
+
+type message struct {
+	Line      int    `json:"line,omitempty"`
+	Character string `json:"character"`
+	Text      string `json:"text"`
+
+	entry *script.Entry // set for script lines, so their directives are applied
+}
+
+type job struct {
+	Profile  string
+	Audio    *synthesis.AudioOptions
+	Messages []message
+	single   bool // one message with no line numbers, written without an index suffix
+}
+
+type jsonRequest struct {
+	Profile   string                 `json:"profile"`
+	Character string                 `json:"character"`
+	Text      string                 `json:"text"`
+	Format    string                 `json:"format,omitempty"`
+	Options   map[string]interface{} `json:"options,omitempty"`
+	Messages  []message              `json:"messages,omitempty"`
+}
+
+// result is written next to the outputs as <name>.result.json once an input has been processed
+type result struct {
+	Input      string      `json:"input"`
+	Success    bool        `json:"success"`
+	Profile    string      `json:"profile,omitempty"`
+	Format     string      `json:"format,omitempty"`
+	Outputs    []output    `json:"outputs"`
+	Errors     []lineError `json:"errors"`
+	StartedAt  time.Time   `json:"started_at"`
+	FinishedAt time.Time   `json:"finished_at"`
+	ElapsedMS  int64       `json:"elapsed_ms"`
+}
+
+type output struct {
+	File       string  `json:"file"`
+	Line       int     `json:"line,omitempty"`
+	Character  string  `json:"character"`
+	Text       string  `json:"text"`
+	SampleRate int     `json:"sample_rate"`
+	Channels   int     `json:"channels"`
+	BitDepth   int     `json:"bit_depth"`
+	Duration   float64 `json:"duration_seconds"`
+	Size       int     `json:"size"`
+}
+
+type lineError struct {
+	Line      int    `json:"line,omitempty"`
+	Character string `json:"character,omitempty"`
+	Error     string `json:"error"`
+}
+
+func (r *result) addError(line int, character string, err error) {
+	r.Errors = append(r.Errors, lineError{Line: line, Character: character, Error: err.Error()})
+}
+
+func parseScript(data []byte) (*job, error) {
+	parsed := &job{Profile: defaultProfile}
+	format := ""
+
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	for _, line := range strings.Split(text, "\n") {
+		if directive := scriptDirectiveRegex.FindStringSubmatch(strings.TrimSpace(line)); directive != nil {
+			switch strings.ToLower(directive[1]) {
+			case "profile":
+				parsed.Profile = directive[2]
+			case "format":
+				format = directive[2]
+			}
+		}
+	}
+
+	parsedScript, err := script.Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range parsedScript.Entries() {
+		parsed.Messages = append(parsed.Messages, message{
+			Line:      entry.Line,
+			Character: entry.Character,
+			Text:      entry.Text,
+			entry:     entry,
+		})
+	}
+
+	if len(parsed.Messages) == 0 {
+		return nil, fmt.Errorf("no \"Character: text\" lines found")
+	}
+
+	if format != "" {
+		parsed.Audio = &synthesis.AudioOptions{Format: format, Encoding: audio.DefaultEncodeOptions()}
+	}
+
+	return parsed, nil
+}
+
+func parseJSON(data []byte) (*job, error) {
+	var request jsonRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		return nil, fmt.Errorf("invalid request format: %w", err)
+	}
+
+	options, err := synthesis.ParseAudioOptions(request.Options)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audio options: %w", err)
+	}
+	if request.Format != "" {
+		if options == nil {
+			options = &synthesis.AudioOptions{Encoding: audio.DefaultEncodeOptions()}
+		}
+		options.Format = request.Format
+	}
+
+	parsed := &job{Profile: request.Profile, Audio: options, Messages: request.Messages}
+	if parsed.Profile == "" {
+		parsed.Profile = defaultProfile
+	}
+
+	if len(parsed.Messages) == 0 {
+		parsed.Messages = []message{{Character: request.Character, Text: request.Text}}
+		parsed.single = true
+	} else if request.Character != "" || request.Text != "" {
+		return nil, fmt.Errorf("use either character/text or messages, not both")
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,86 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"nstudio/app/config"
+	"nstudio/app/server/grpc/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type configServer struct {
+	pb.UnimplementedConfigServiceServer
+}
+
+func (server *configServer) GetConfig(ctx context.Context, request *pb.GetConfigRequest) (*pb.ConfigResponse, error) {
+	return configResponse()
+}
+
+func (server *configServer) UpdateConfig(ctx context.Context, request *pb.UpdateConfigRequest) (*pb.ConfigResponse, error) {
+	var newConfig config.Base
+	if err := json.Unmarshal([]byte(request.GetConfigJson()), &newConfig); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid configuration format")
+	}
+
+	newConfig.Info = config.Get().Info
+
+	if err := config.Set(newConfig); err != nil {
+		return nil, status.Error(codes.Internal, "Failed to save configuration: "+err.Error())
+	}
+
+	return configResponse()
+}
+
+func (server *configServer) PatchConfig(ctx context.Context, request *pb.PatchConfigRequest) (*pb.PatchConfigResponse, error) {
+	if request.GetPath() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Path is required")
+	}
+
+	if err := config.SetValueToPath(request.GetPath(), request.GetValue()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Failed to set config value: "+err.Error())
+	}
+
+	return &pb.PatchConfigResponse{Path: request.GetPath(), Value: request.GetValue()}, nil
+}
+
+func (server *configServer) GetConfigValue(ctx context.Context, request *pb.GetConfigValueRequest) (*pb.GetConfigValueResponse, error) {
+	if request.GetPath() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Path is required")
+	}
+
+	value, err := config.GetValueFromPath(request.GetPath())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "Config path not found: "+err.Error())
+	}
+
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.GetConfigValueResponse{Path: request.GetPath(), ValueJson: string(valueJSON)}, nil
+}
+
+func (server *configServer) GetConfigSchema(ctx context.Context, request *pb.GetConfigSchemaRequest) (*pb.GetConfigSchemaResponse, error) {
+	schema, err := config.GetConfigSchema()
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to get config schema: "+err.Error())
+	}
+
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.GetConfigSchemaResponse{SchemaJson: string(schemaJSON)}, nil
+}
+
+func configResponse() (*pb.ConfigResponse, error) {
+	configJSON, err := json.Marshal(config.Get())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.ConfigResponse{ConfigJson: string(configJSON)}, nil
+}
@@ -0,0 +1,146 @@
+package grpc
+
+import (
+	"cmp"
+	"context"
+	"nstudio/app/server/grpc/pb"
+	"nstudio/app/tts/engine"
+	"nstudio/app/tts/modelManager"
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type engineServer struct {
+	pb.UnimplementedEngineServiceServer
+}
+
+func (server *engineServer) ListEngines(ctx context.Context, request *pb.ListEnginesRequest) (*pb.ListEnginesResponse, error) {
+	engines := modelManager.GetActiveEngines()
+	engineList := make([]*pb.Engine, 0, len(engines))
+
+	for _, eng := range engines {
+		engineList = append(engineList, &pb.Engine{
+			Id:         eng.ID,
+			Name:       eng.Name,
+			Type:       engineTypeToProto(eng.Type),
+			Tags:       eng.Tags,
+			ModelCount: int32(len(eng.Models)),
+		})
+	}
+
+	return &pb.ListEnginesResponse{Engines: engineList}, nil
+}
+
+func (server *engineServer) ListModels(ctx context.Context, request *pb.ListModelsRequest) (*pb.ListModelsResponse, error) {
+	if request.GetEngine() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Engine ID is required")
+	}
+
+	var targetEngine *engine.Engine
+	for _, eng := range modelManager.GetAllEngines() {
+		if eng.ID == request.GetEngine() {
+			targetEngine = &eng
+			break
+		}
+	}
+
+	if targetEngine == nil {
+		return nil, status.Errorf(codes.NotFound, "Engine not found: %s", request.GetEngine())
+	}
+
+	modelList := make([]*pb.Model, 0, len(targetEngine.Models))
+	for _, model := range sortedModels(targetEngine.Models) {
+		modelList = append(modelList, &pb.Model{
+			Id:     model.ID,
+			Name:   model.Name,
+			Engine: model.Engine,
+		})
+	}
+
+	return &pb.ListModelsResponse{Engine: request.GetEngine(), Models: modelList}, nil
+}
+
+func (server *engineServer) ListVoices(ctx context.Context, request *pb.ListVoicesRequest) (*pb.ListVoicesResponse, error) {
+	if request.GetEngine() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Engine ID is required")
+	}
+	if request.GetModel() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Model ID is required")
+	}
+
+	voices, err := modelManager.GetModelVoices(request.GetEngine(), request.GetModel())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "Model not found or no voices available: %s/%s", request.GetEngine(), request.GetModel())
+	}
+
+	return &pb.ListVoicesResponse{Voices: voicesToProto(voices)}, nil
+}
+
+func (server *engineServer) GetVoiceTree(ctx context.Context, request *pb.GetVoiceTreeRequest) (*pb.GetVoiceTreeResponse, error) {
+	engines := modelManager.GetAllEngines()
+	engineList := make([]*pb.EngineVoices, 0, len(engines))
+
+	for _, eng := range engines {
+		modelList := make([]*pb.ModelVoices, 0, len(eng.Models))
+
+		for _, model := range sortedModels(eng.Models) {
+			voices, err := modelManager.GetModelVoices(eng.ID, model.ID)
+			if err != nil {
+				voices = []engine.Voice{}
+			}
+
+			modelList = append(modelList, &pb.ModelVoices{
+				Id:     model.ID,
+				Name:   model.Name,
+				Voices: voicesToProto(voices),
+			})
+		}
+
+		engineList = append(engineList, &pb.EngineVoices{
+			Id:     eng.ID,
+			Name:   eng.Name,
+			Type:   engineTypeToProto(eng.Type),
+			Tags:   eng.Tags,
+			Models: modelList,
+		})
+	}
+
+	return &pb.GetVoiceTreeResponse{Engines: engineList}, nil
+}
+
+func engineTypeToProto(engineType engine.EngineType) pb.EngineType {
+	switch engineType {
+	case engine.Local:
+		return pb.EngineType_ENGINE_TYPE_LOCAL
+	case engine.Api:
+		return pb.EngineType_ENGINE_TYPE_API
+	default:
+		return pb.EngineType_ENGINE_TYPE_UNSPECIFIED
+	}
+}
+
+func voicesToProto(voices []engine.Voice) []*pb.Voice {
+	result := make([]*pb.Voice, 0, len(voices))
+	for _, voice := range voices {
+		result = append(result, &pb.Voice{
+			Id:     voice.ID,
+			Name:   voice.Name,
+			Gender: voice.Gender,
+		})
+	}
+	return result
+}
+
+// sortedModels returns an engine's models ordered by ID so responses are stable
+func sortedModels(models map[string]engine.Model) []engine.Model {
+	result := make([]engine.Model, 0, len(models))
+	for _, model := range models {
+		result = append(result, model)
+	}
+	slices.SortFunc(result, func(a, b engine.Model) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return result
+}
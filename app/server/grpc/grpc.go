@@ -0,0 +1,129 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"nstudio/app/server/grpc/pb"
+	customMiddleware "nstudio/app/server/http/middleware"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+type ServerConfig struct {
+	Host string
+	Port int
+}
+
+// StartGRPCServer serves the services defined in proto/narration_studio.proto, plus the standard
+// health and reflection services
+func StartGRPCServer(config ServerConfig) error {
+	address := fmt.Sprintf("%s:%d", config.Host, config.Port)
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryAuthInterceptor),
+		grpc.ChainStreamInterceptor(streamAuthInterceptor),
+	)
+
+	pb.RegisterSynthesisServiceServer(server, &synthesisServer{})
+	pb.RegisterEngineServiceServer(server, &engineServer{})
+	pb.RegisterProfileServiceServer(server, &profileServer{})
+	pb.RegisterConfigServiceServer(server, &configServer{})
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	fmt.Printf("gRPC server listening on %s\n", address)
+
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.Error("gRPC server stopped", "error", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	fmt.Println("\nShutting down server...")
+
+	healthServer.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(10 * time.Second):
+		server.Stop()
+	}
+
+	fmt.Println("Server stopped")
+	return nil
+}
+
+// <editor-fold desc="Authentication">
+
+// authorize applies the HTTP API's key rules to "authorization: Bearer <key>" metadata.
+// ConfigService is admin only, and the health service is public like GET /health.
+func authorize(ctx context.Context, fullMethod string) error {
+	if strings.HasPrefix(fullMethod, "/"+healthpb.Health_ServiceDesc.ServiceName+"/") {
+		return nil
+	}
+
+	token := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			token = customMiddleware.BearerToken(values[0])
+		}
+	}
+
+	if strings.HasPrefix(fullMethod, "/"+pb.ConfigService_ServiceDesc.ServiceName+"/") {
+		if !customMiddleware.IsAdminAuthorized(token) {
+			return status.Error(codes.PermissionDenied, "Admin authentication required")
+		}
+		return nil
+	}
+
+	if !customMiddleware.IsAuthorized(token) {
+		return status.Error(codes.Unauthenticated, "Valid authentication required")
+	}
+	return nil
+}
+
+func unaryAuthInterceptor(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, request)
+}
+
+func streamAuthInterceptor(server interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := authorize(stream.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(server, stream)
+}
+
+//</editor-fold>
@@ -0,0 +1,2960 @@
+// Narration Studio gRPC API, served by --mode=grpc.
+//
+// Authentication mirrors the HTTP API: send "authorization: Bearer <key>" metadata with the user or
+// admin key. ConfigService requires the admin key. Keys are only checked once they are configured.
+//
+// The Go code in app/server/grpc/pb is generated from this file:
+//
+//	protoc --go_out=. --go_opt=module=nstudio --go-grpc_out=. --go-grpc_opt=module=nstudio proto/narration_studio.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v5.29.3
+// source: proto/narration_studio.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AudioFormat int32
+
+const (
+	AudioFormat_AUDIO_FORMAT_UNSPECIFIED AudioFormat = 0 // wav
+	AudioFormat_AUDIO_FORMAT_WAV         AudioFormat = 1
+	AudioFormat_AUDIO_FORMAT_FLAC        AudioFormat = 2
+	AudioFormat_AUDIO_FORMAT_OGG         AudioFormat = 3
+	AudioFormat_AUDIO_FORMAT_MP3         AudioFormat = 4
+	AudioFormat_AUDIO_FORMAT_PCM_S16LE   AudioFormat = 5
+)
+
+// Enum value maps for AudioFormat.
+var (
+	AudioFormat_name = map[int32]string{
+		0: "AUDIO_FORMAT_UNSPECIFIED",
+		1: "AUDIO_FORMAT_WAV",
+		2: "AUDIO_FORMAT_FLAC",
+		3: "AUDIO_FORMAT_OGG",
+		4: "AUDIO_FORMAT_MP3",
+		5: "AUDIO_FORMAT_PCM_S16LE",
+	}
+	AudioFormat_value = map[string]int32{
+		"AUDIO_FORMAT_UNSPECIFIED": 0,
+		"AUDIO_FORMAT_WAV":         1,
+		"AUDIO_FORMAT_FLAC":        2,
+		"AUDIO_FORMAT_OGG":         3,
+		"AUDIO_FORMAT_MP3":         4,
+		"AUDIO_FORMAT_PCM_S16LE":   5,
+	}
+)
+
+func (x AudioFormat) Enum() *AudioFormat {
+	p := new(AudioFormat)
+	*p = x
+	return p
+}
+
+func (x AudioFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AudioFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_narration_studio_proto_enumTypes[0].Descriptor()
+}
+
+func (AudioFormat) Type() protoreflect.EnumType {
+	return &file_proto_narration_studio_proto_enumTypes[0]
+}
+
+func (x AudioFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AudioFormat.Descriptor instead.
+func (AudioFormat) EnumDescriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{0}
+}
+
+type BitrateMode int32
+
+const (
+	BitrateMode_BITRATE_MODE_UNSPECIFIED BitrateMode = 0 // cbr
+	BitrateMode_BITRATE_MODE_CBR         BitrateMode = 1
+	BitrateMode_BITRATE_MODE_VBR         BitrateMode = 2
+)
+
+// Enum value maps for BitrateMode.
+var (
+	BitrateMode_name = map[int32]string{
+		0: "BITRATE_MODE_UNSPECIFIED",
+		1: "BITRATE_MODE_CBR",
+		2: "BITRATE_MODE_VBR",
+	}
+	BitrateMode_value = map[string]int32{
+		"BITRATE_MODE_UNSPECIFIED": 0,
+		"BITRATE_MODE_CBR":         1,
+		"BITRATE_MODE_VBR":         2,
+	}
+)
+
+func (x BitrateMode) Enum() *BitrateMode {
+	p := new(BitrateMode)
+	*p = x
+	return p
+}
+
+func (x BitrateMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BitrateMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_narration_studio_proto_enumTypes[1].Descriptor()
+}
+
+func (BitrateMode) Type() protoreflect.EnumType {
+	return &file_proto_narration_studio_proto_enumTypes[1]
+}
+
+func (x BitrateMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BitrateMode.Descriptor instead.
+func (BitrateMode) EnumDescriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{1}
+}
+
+type EngineType int32
+
+const (
+	EngineType_ENGINE_TYPE_UNSPECIFIED EngineType = 0
+	EngineType_ENGINE_TYPE_LOCAL       EngineType = 1
+	EngineType_ENGINE_TYPE_API         EngineType = 2
+)
+
+// Enum value maps for EngineType.
+var (
+	EngineType_name = map[int32]string{
+		0: "ENGINE_TYPE_UNSPECIFIED",
+		1: "ENGINE_TYPE_LOCAL",
+		2: "ENGINE_TYPE_API",
+	}
+	EngineType_value = map[string]int32{
+		"ENGINE_TYPE_UNSPECIFIED": 0,
+		"ENGINE_TYPE_LOCAL":       1,
+		"ENGINE_TYPE_API":         2,
+	}
+)
+
+func (x EngineType) Enum() *EngineType {
+	p := new(EngineType)
+	*p = x
+	return p
+}
+
+func (x EngineType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EngineType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_narration_studio_proto_enumTypes[2].Descriptor()
+}
+
+func (EngineType) Type() protoreflect.EnumType {
+	return &file_proto_narration_studio_proto_enumTypes[2]
+}
+
+func (x EngineType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EngineType.Descriptor instead.
+func (EngineType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{2}
+}
+
+type AudioOptions struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Format           AudioFormat            `protobuf:"varint,1,opt,name=format,proto3,enum=nstudio.v1.AudioFormat" json:"format,omitempty"`
+	SampleRate       int32                  `protobuf:"varint,2,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"` // 0 keeps the engine's rate
+	Channels         int32                  `protobuf:"varint,3,opt,name=channels,proto3" json:"channels,omitempty"`                       // 0 keeps the engine's channel count
+	BitDepth         int32                  `protobuf:"varint,4,opt,name=bit_depth,json=bitDepth,proto3" json:"bit_depth,omitempty"`
+	Quality          *float64               `protobuf:"fixed64,5,opt,name=quality,proto3,oneof" json:"quality,omitempty"`                                                 // ogg and vbr mp3, 0-10
+	Bitrate          *int32                 `protobuf:"varint,6,opt,name=bitrate,proto3,oneof" json:"bitrate,omitempty"`                                                  // kbps for ogg and mp3
+	BitrateMode      BitrateMode            `protobuf:"varint,7,opt,name=bitrate_mode,json=bitrateMode,proto3,enum=nstudio.v1.BitrateMode" json:"bitrate_mode,omitempty"` // mp3
+	CompressionLevel *int32                 `protobuf:"varint,8,opt,name=compression_level,json=compressionLevel,proto3,oneof" json:"compression_level,omitempty"`        // flac, 0-8
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *AudioOptions) Reset() {
+	*x = AudioOptions{}
+	mi := &file_proto_narration_studio_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AudioOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AudioOptions) ProtoMessage() {}
+
+func (x *AudioOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AudioOptions.ProtoReflect.Descriptor instead.
+func (*AudioOptions) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AudioOptions) GetFormat() AudioFormat {
+	if x != nil {
+		return x.Format
+	}
+	return AudioFormat_AUDIO_FORMAT_UNSPECIFIED
+}
+
+func (x *AudioOptions) GetSampleRate() int32 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+func (x *AudioOptions) GetChannels() int32 {
+	if x != nil {
+		return x.Channels
+	}
+	return 0
+}
+
+func (x *AudioOptions) GetBitDepth() int32 {
+	if x != nil {
+		return x.BitDepth
+	}
+	return 0
+}
+
+func (x *AudioOptions) GetQuality() float64 {
+	if x != nil && x.Quality != nil {
+		return *x.Quality
+	}
+	return 0
+}
+
+func (x *AudioOptions) GetBitrate() int32 {
+	if x != nil && x.Bitrate != nil {
+		return *x.Bitrate
+	}
+	return 0
+}
+
+func (x *AudioOptions) GetBitrateMode() BitrateMode {
+	if x != nil {
+		return x.BitrateMode
+	}
+	return BitrateMode_BITRATE_MODE_UNSPECIFIED
+}
+
+func (x *AudioOptions) GetCompressionLevel() int32 {
+	if x != nil && x.CompressionLevel != nil {
+		return *x.CompressionLevel
+	}
+	return 0
+}
+
+type SynthesizeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profile       string                 `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	Character     string                 `protobuf:"bytes,2,opt,name=character,proto3" json:"character,omitempty"`
+	Text          string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	Audio         *AudioOptions          `protobuf:"bytes,4,opt,name=audio,proto3" json:"audio,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SynthesizeRequest) Reset() {
+	*x = SynthesizeRequest{}
+	mi := &file_proto_narration_studio_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SynthesizeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SynthesizeRequest) ProtoMessage() {}
+
+func (x *SynthesizeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SynthesizeRequest.ProtoReflect.Descriptor instead.
+func (*SynthesizeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SynthesizeRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *SynthesizeRequest) GetCharacter() string {
+	if x != nil {
+		return x.Character
+	}
+	return ""
+}
+
+func (x *SynthesizeRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *SynthesizeRequest) GetAudio() *AudioOptions {
+	if x != nil {
+		return x.Audio
+	}
+	return nil
+}
+
+type SynthesizeVoiceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Engine        string                 `protobuf:"bytes,1,opt,name=engine,proto3" json:"engine,omitempty"`
+	Model         string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	Voice         string                 `protobuf:"bytes,3,opt,name=voice,proto3" json:"voice,omitempty"`
+	Text          string                 `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"`
+	Audio         *AudioOptions          `protobuf:"bytes,5,opt,name=audio,proto3" json:"audio,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SynthesizeVoiceRequest) Reset() {
+	*x = SynthesizeVoiceRequest{}
+	mi := &file_proto_narration_studio_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SynthesizeVoiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SynthesizeVoiceRequest) ProtoMessage() {}
+
+func (x *SynthesizeVoiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SynthesizeVoiceRequest.ProtoReflect.Descriptor instead.
+func (*SynthesizeVoiceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SynthesizeVoiceRequest) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+func (x *SynthesizeVoiceRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *SynthesizeVoiceRequest) GetVoice() string {
+	if x != nil {
+		return x.Voice
+	}
+	return ""
+}
+
+func (x *SynthesizeVoiceRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *SynthesizeVoiceRequest) GetAudio() *AudioOptions {
+	if x != nil {
+		return x.Audio
+	}
+	return nil
+}
+
+type SynthesizeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Audio         []byte                 `protobuf:"bytes,1,opt,name=audio,proto3" json:"audio,omitempty"`
+	Format        AudioFormat            `protobuf:"varint,2,opt,name=format,proto3,enum=nstudio.v1.AudioFormat" json:"format,omitempty"`
+	ContentType   string                 `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	SampleRate    int32                  `protobuf:"varint,4,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"`
+	Channels      int32                  `protobuf:"varint,5,opt,name=channels,proto3" json:"channels,omitempty"`
+	BitDepth      int32                  `protobuf:"varint,6,opt,name=bit_depth,json=bitDepth,proto3" json:"bit_depth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SynthesizeResponse) Reset() {
+	*x = SynthesizeResponse{}
+	mi := &file_proto_narration_studio_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SynthesizeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SynthesizeResponse) ProtoMessage() {}
+
+func (x *SynthesizeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SynthesizeResponse.ProtoReflect.Descriptor instead.
+func (*SynthesizeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SynthesizeResponse) GetAudio() []byte {
+	if x != nil {
+		return x.Audio
+	}
+	return nil
+}
+
+func (x *SynthesizeResponse) GetFormat() AudioFormat {
+	if x != nil {
+		return x.Format
+	}
+	return AudioFormat_AUDIO_FORMAT_UNSPECIFIED
+}
+
+func (x *SynthesizeResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *SynthesizeResponse) GetSampleRate() int32 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+func (x *SynthesizeResponse) GetChannels() int32 {
+	if x != nil {
+		return x.Channels
+	}
+	return 0
+}
+
+func (x *SynthesizeResponse) GetBitDepth() int32 {
+	if x != nil {
+		return x.BitDepth
+	}
+	return 0
+}
+
+type AudioChunk struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Sequence int32                  `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Pcm      []byte                 `protobuf:"bytes,2,opt,name=pcm,proto3" json:"pcm,omitempty"`
+	// Deprecated: Marked as deprecated in proto/narration_studio.proto.
+	Text          string `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"` // always empty, chunks follow the engine's own boundaries
+	Final         bool   `protobuf:"varint,4,opt,name=final,proto3" json:"final,omitempty"`
+	SampleRate    int32  `protobuf:"varint,5,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"`
+	Channels      int32  `protobuf:"varint,6,opt,name=channels,proto3" json:"channels,omitempty"`
+	BitDepth      int32  `protobuf:"varint,7,opt,name=bit_depth,json=bitDepth,proto3" json:"bit_depth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AudioChunk) Reset() {
+	*x = AudioChunk{}
+	mi := &file_proto_narration_studio_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AudioChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AudioChunk) ProtoMessage() {}
+
+func (x *AudioChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AudioChunk.ProtoReflect.Descriptor instead.
+func (*AudioChunk) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AudioChunk) GetSequence() int32 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *AudioChunk) GetPcm() []byte {
+	if x != nil {
+		return x.Pcm
+	}
+	return nil
+}
+
+// Deprecated: Marked as deprecated in proto/narration_studio.proto.
+func (x *AudioChunk) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *AudioChunk) GetFinal() bool {
+	if x != nil {
+		return x.Final
+	}
+	return false
+}
+
+func (x *AudioChunk) GetSampleRate() int32 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+func (x *AudioChunk) GetChannels() int32 {
+	if x != nil {
+		return x.Channels
+	}
+	return 0
+}
+
+func (x *AudioChunk) GetBitDepth() int32 {
+	if x != nil {
+		return x.BitDepth
+	}
+	return 0
+}
+
+type Engine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type          EngineType             `protobuf:"varint,3,opt,name=type,proto3,enum=nstudio.v1.EngineType" json:"type,omitempty"`
+	Tags          []string               `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	ModelCount    int32                  `protobuf:"varint,5,opt,name=model_count,json=modelCount,proto3" json:"model_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Engine) Reset() {
+	*x = Engine{}
+	mi := &file_proto_narration_studio_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Engine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Engine) ProtoMessage() {}
+
+func (x *Engine) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Engine.ProtoReflect.Descriptor instead.
+func (*Engine) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Engine) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Engine) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Engine) GetType() EngineType {
+	if x != nil {
+		return x.Type
+	}
+	return EngineType_ENGINE_TYPE_UNSPECIFIED
+}
+
+func (x *Engine) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Engine) GetModelCount() int32 {
+	if x != nil {
+		return x.ModelCount
+	}
+	return 0
+}
+
+type Model struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Engine        string                 `protobuf:"bytes,3,opt,name=engine,proto3" json:"engine,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Model) Reset() {
+	*x = Model{}
+	mi := &file_proto_narration_studio_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Model) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Model) ProtoMessage() {}
+
+func (x *Model) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Model.ProtoReflect.Descriptor instead.
+func (*Model) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Model) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Model) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Model) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+type Voice struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Gender        string                 `protobuf:"bytes,3,opt,name=gender,proto3" json:"gender,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Voice) Reset() {
+	*x = Voice{}
+	mi := &file_proto_narration_studio_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Voice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Voice) ProtoMessage() {}
+
+func (x *Voice) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Voice.ProtoReflect.Descriptor instead.
+func (*Voice) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Voice) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Voice) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Voice) GetGender() string {
+	if x != nil {
+		return x.Gender
+	}
+	return ""
+}
+
+type ListEnginesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEnginesRequest) Reset() {
+	*x = ListEnginesRequest{}
+	mi := &file_proto_narration_studio_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEnginesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEnginesRequest) ProtoMessage() {}
+
+func (x *ListEnginesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEnginesRequest.ProtoReflect.Descriptor instead.
+func (*ListEnginesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{8}
+}
+
+type ListEnginesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Engines       []*Engine              `protobuf:"bytes,1,rep,name=engines,proto3" json:"engines,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEnginesResponse) Reset() {
+	*x = ListEnginesResponse{}
+	mi := &file_proto_narration_studio_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEnginesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEnginesResponse) ProtoMessage() {}
+
+func (x *ListEnginesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEnginesResponse.ProtoReflect.Descriptor instead.
+func (*ListEnginesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListEnginesResponse) GetEngines() []*Engine {
+	if x != nil {
+		return x.Engines
+	}
+	return nil
+}
+
+type ListModelsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Engine        string                 `protobuf:"bytes,1,opt,name=engine,proto3" json:"engine,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListModelsRequest) Reset() {
+	*x = ListModelsRequest{}
+	mi := &file_proto_narration_studio_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListModelsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListModelsRequest) ProtoMessage() {}
+
+func (x *ListModelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListModelsRequest.ProtoReflect.Descriptor instead.
+func (*ListModelsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListModelsRequest) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+type ListModelsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Engine        string                 `protobuf:"bytes,1,opt,name=engine,proto3" json:"engine,omitempty"`
+	Models        []*Model               `protobuf:"bytes,2,rep,name=models,proto3" json:"models,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListModelsResponse) Reset() {
+	*x = ListModelsResponse{}
+	mi := &file_proto_narration_studio_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListModelsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListModelsResponse) ProtoMessage() {}
+
+func (x *ListModelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListModelsResponse.ProtoReflect.Descriptor instead.
+func (*ListModelsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListModelsResponse) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+func (x *ListModelsResponse) GetModels() []*Model {
+	if x != nil {
+		return x.Models
+	}
+	return nil
+}
+
+type ListVoicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Engine        string                 `protobuf:"bytes,1,opt,name=engine,proto3" json:"engine,omitempty"`
+	Model         string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVoicesRequest) Reset() {
+	*x = ListVoicesRequest{}
+	mi := &file_proto_narration_studio_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVoicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVoicesRequest) ProtoMessage() {}
+
+func (x *ListVoicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVoicesRequest.ProtoReflect.Descriptor instead.
+func (*ListVoicesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListVoicesRequest) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+func (x *ListVoicesRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+type ListVoicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Voices        []*Voice               `protobuf:"bytes,1,rep,name=voices,proto3" json:"voices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVoicesResponse) Reset() {
+	*x = ListVoicesResponse{}
+	mi := &file_proto_narration_studio_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVoicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVoicesResponse) ProtoMessage() {}
+
+func (x *ListVoicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVoicesResponse.ProtoReflect.Descriptor instead.
+func (*ListVoicesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListVoicesResponse) GetVoices() []*Voice {
+	if x != nil {
+		return x.Voices
+	}
+	return nil
+}
+
+type GetVoiceTreeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVoiceTreeRequest) Reset() {
+	*x = GetVoiceTreeRequest{}
+	mi := &file_proto_narration_studio_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVoiceTreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVoiceTreeRequest) ProtoMessage() {}
+
+func (x *GetVoiceTreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVoiceTreeRequest.ProtoReflect.Descriptor instead.
+func (*GetVoiceTreeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{14}
+}
+
+type ModelVoices struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Voices        []*Voice               `protobuf:"bytes,3,rep,name=voices,proto3" json:"voices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModelVoices) Reset() {
+	*x = ModelVoices{}
+	mi := &file_proto_narration_studio_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModelVoices) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModelVoices) ProtoMessage() {}
+
+func (x *ModelVoices) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModelVoices.ProtoReflect.Descriptor instead.
+func (*ModelVoices) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ModelVoices) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ModelVoices) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ModelVoices) GetVoices() []*Voice {
+	if x != nil {
+		return x.Voices
+	}
+	return nil
+}
+
+type EngineVoices struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type          EngineType             `protobuf:"varint,3,opt,name=type,proto3,enum=nstudio.v1.EngineType" json:"type,omitempty"`
+	Tags          []string               `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	Models        []*ModelVoices         `protobuf:"bytes,5,rep,name=models,proto3" json:"models,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EngineVoices) Reset() {
+	*x = EngineVoices{}
+	mi := &file_proto_narration_studio_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EngineVoices) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EngineVoices) ProtoMessage() {}
+
+func (x *EngineVoices) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EngineVoices.ProtoReflect.Descriptor instead.
+func (*EngineVoices) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *EngineVoices) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *EngineVoices) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EngineVoices) GetType() EngineType {
+	if x != nil {
+		return x.Type
+	}
+	return EngineType_ENGINE_TYPE_UNSPECIFIED
+}
+
+func (x *EngineVoices) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *EngineVoices) GetModels() []*ModelVoices {
+	if x != nil {
+		return x.Models
+	}
+	return nil
+}
+
+type GetVoiceTreeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Engines       []*EngineVoices        `protobuf:"bytes,1,rep,name=engines,proto3" json:"engines,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVoiceTreeResponse) Reset() {
+	*x = GetVoiceTreeResponse{}
+	mi := &file_proto_narration_studio_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVoiceTreeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVoiceTreeResponse) ProtoMessage() {}
+
+func (x *GetVoiceTreeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVoiceTreeResponse.ProtoReflect.Descriptor instead.
+func (*GetVoiceTreeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetVoiceTreeResponse) GetEngines() []*EngineVoices {
+	if x != nil {
+		return x.Engines
+	}
+	return nil
+}
+
+type CharacterVoice struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Engine        string                 `protobuf:"bytes,2,opt,name=engine,proto3" json:"engine,omitempty"`
+	Model         string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	Voice         string                 `protobuf:"bytes,4,opt,name=voice,proto3" json:"voice,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CharacterVoice) Reset() {
+	*x = CharacterVoice{}
+	mi := &file_proto_narration_studio_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CharacterVoice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CharacterVoice) ProtoMessage() {}
+
+func (x *CharacterVoice) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CharacterVoice.ProtoReflect.Descriptor instead.
+func (*CharacterVoice) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CharacterVoice) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CharacterVoice) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+func (x *CharacterVoice) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *CharacterVoice) GetVoice() string {
+	if x != nil {
+		return x.Voice
+	}
+	return ""
+}
+
+type ProfileSettings struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ModelToggles  map[string]bool        `protobuf:"bytes,1,rep,name=model_toggles,json=modelToggles,proto3" json:"model_toggles,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	CacheEnabled  *bool                  `protobuf:"varint,2,opt,name=cache_enabled,json=cacheEnabled,proto3,oneof" json:"cache_enabled,omitempty"` // unset uses the global setting
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProfileSettings) Reset() {
+	*x = ProfileSettings{}
+	mi := &file_proto_narration_studio_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProfileSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProfileSettings) ProtoMessage() {}
+
+func (x *ProfileSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProfileSettings.ProtoReflect.Descriptor instead.
+func (*ProfileSettings) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ProfileSettings) GetModelToggles() map[string]bool {
+	if x != nil {
+		return x.ModelToggles
+	}
+	return nil
+}
+
+func (x *ProfileSettings) GetCacheEnabled() bool {
+	if x != nil && x.CacheEnabled != nil {
+		return *x.CacheEnabled
+	}
+	return false
+}
+
+type Profile struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Id            string                     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                     `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                     `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	CreatedAt     string                     `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string                     `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Voices        map[string]*CharacterVoice `protobuf:"bytes,6,rep,name=voices,proto3" json:"voices,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Settings      *ProfileSettings           `protobuf:"bytes,7,opt,name=settings,proto3" json:"settings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Profile) Reset() {
+	*x = Profile{}
+	mi := &file_proto_narration_studio_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Profile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Profile) ProtoMessage() {}
+
+func (x *Profile) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Profile.ProtoReflect.Descriptor instead.
+func (*Profile) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Profile) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Profile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Profile) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Profile) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Profile) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *Profile) GetVoices() map[string]*CharacterVoice {
+	if x != nil {
+		return x.Voices
+	}
+	return nil
+}
+
+func (x *Profile) GetSettings() *ProfileSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+type ProfileSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	VoiceCount    int32                  `protobuf:"varint,6,opt,name=voice_count,json=voiceCount,proto3" json:"voice_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProfileSummary) Reset() {
+	*x = ProfileSummary{}
+	mi := &file_proto_narration_studio_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProfileSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProfileSummary) ProtoMessage() {}
+
+func (x *ProfileSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProfileSummary.ProtoReflect.Descriptor instead.
+func (*ProfileSummary) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ProfileSummary) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ProfileSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProfileSummary) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ProfileSummary) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *ProfileSummary) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *ProfileSummary) GetVoiceCount() int32 {
+	if x != nil {
+		return x.VoiceCount
+	}
+	return 0
+}
+
+type ListProfilesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProfilesRequest) Reset() {
+	*x = ListProfilesRequest{}
+	mi := &file_proto_narration_studio_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProfilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProfilesRequest) ProtoMessage() {}
+
+func (x *ListProfilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProfilesRequest.ProtoReflect.Descriptor instead.
+func (*ListProfilesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{22}
+}
+
+type ListProfilesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profiles      []*ProfileSummary      `protobuf:"bytes,1,rep,name=profiles,proto3" json:"profiles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProfilesResponse) Reset() {
+	*x = ListProfilesResponse{}
+	mi := &file_proto_narration_studio_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProfilesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProfilesResponse) ProtoMessage() {}
+
+func (x *ListProfilesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProfilesResponse.ProtoReflect.Descriptor instead.
+func (*ListProfilesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListProfilesResponse) GetProfiles() []*ProfileSummary {
+	if x != nil {
+		return x.Profiles
+	}
+	return nil
+}
+
+type GetProfileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profile       string                 `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProfileRequest) Reset() {
+	*x = GetProfileRequest{}
+	mi := &file_proto_narration_studio_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProfileRequest) ProtoMessage() {}
+
+func (x *GetProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProfileRequest.ProtoReflect.Descriptor instead.
+func (*GetProfileRequest) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetProfileRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+type CreateProfileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateProfileRequest) Reset() {
+	*x = CreateProfileRequest{}
+	mi := &file_proto_narration_studio_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateProfileRequest) ProtoMessage() {}
+
+func (x *CreateProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateProfileRequest.ProtoReflect.Descriptor instead.
+func (*CreateProfileRequest) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CreateProfileRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateProfileRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateProfileRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type DeleteProfileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profile       string                 `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteProfileRequest) Reset() {
+	*x = DeleteProfileRequest{}
+	mi := &file_proto_narration_studio_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteProfileRequest) ProtoMessage() {}
+
+func (x *DeleteProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteProfileRequest.ProtoReflect.Descriptor instead.
+func (*DeleteProfileRequest) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DeleteProfileRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+type DeleteProfileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteProfileResponse) Reset() {
+	*x = DeleteProfileResponse{}
+	mi := &file_proto_narration_studio_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteProfileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteProfileResponse) ProtoMessage() {}
+
+func (x *DeleteProfileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteProfileResponse.ProtoReflect.Descriptor instead.
+func (*DeleteProfileResponse) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{27}
+}
+
+type ListProfileVoicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profile       string                 `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProfileVoicesRequest) Reset() {
+	*x = ListProfileVoicesRequest{}
+	mi := &file_proto_narration_studio_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProfileVoicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProfileVoicesRequest) ProtoMessage() {}
+
+func (x *ListProfileVoicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProfileVoicesRequest.ProtoReflect.Descriptor instead.
+func (*ListProfileVoicesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListProfileVoicesRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+type ListProfileVoicesResponse struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Profile       string                     `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	Voices        map[string]*CharacterVoice `protobuf:"bytes,2,rep,name=voices,proto3" json:"voices,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Characters    []string                   `protobuf:"bytes,3,rep,name=characters,proto3" json:"characters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProfileVoicesResponse) Reset() {
+	*x = ListProfileVoicesResponse{}
+	mi := &file_proto_narration_studio_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProfileVoicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProfileVoicesResponse) ProtoMessage() {}
+
+func (x *ListProfileVoicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProfileVoicesResponse.ProtoReflect.Descriptor instead.
+func (*ListProfileVoicesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListProfileVoicesResponse) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *ListProfileVoicesResponse) GetVoices() map[string]*CharacterVoice {
+	if x != nil {
+		return x.Voices
+	}
+	return nil
+}
+
+func (x *ListProfileVoicesResponse) GetCharacters() []string {
+	if x != nil {
+		return x.Characters
+	}
+	return nil
+}
+
+type CharacterVoiceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profile       string                 `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	Character     string                 `protobuf:"bytes,2,opt,name=character,proto3" json:"character,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CharacterVoiceRequest) Reset() {
+	*x = CharacterVoiceRequest{}
+	mi := &file_proto_narration_studio_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CharacterVoiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CharacterVoiceRequest) ProtoMessage() {}
+
+func (x *CharacterVoiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CharacterVoiceRequest.ProtoReflect.Descriptor instead.
+func (*CharacterVoiceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *CharacterVoiceRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *CharacterVoiceRequest) GetCharacter() string {
+	if x != nil {
+		return x.Character
+	}
+	return ""
+}
+
+type SetCharacterVoiceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profile       string                 `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	Character     string                 `protobuf:"bytes,2,opt,name=character,proto3" json:"character,omitempty"`
+	Voice         *CharacterVoice        `protobuf:"bytes,3,opt,name=voice,proto3" json:"voice,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetCharacterVoiceRequest) Reset() {
+	*x = SetCharacterVoiceRequest{}
+	mi := &file_proto_narration_studio_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetCharacterVoiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetCharacterVoiceRequest) ProtoMessage() {}
+
+func (x *SetCharacterVoiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetCharacterVoiceRequest.ProtoReflect.Descriptor instead.
+func (*SetCharacterVoiceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *SetCharacterVoiceRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *SetCharacterVoiceRequest) GetCharacter() string {
+	if x != nil {
+		return x.Character
+	}
+	return ""
+}
+
+func (x *SetCharacterVoiceRequest) GetVoice() *CharacterVoice {
+	if x != nil {
+		return x.Voice
+	}
+	return nil
+}
+
+type CharacterVoiceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profile       string                 `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	Character     string                 `protobuf:"bytes,2,opt,name=character,proto3" json:"character,omitempty"`
+	Voice         *CharacterVoice        `protobuf:"bytes,3,opt,name=voice,proto3" json:"voice,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CharacterVoiceResponse) Reset() {
+	*x = CharacterVoiceResponse{}
+	mi := &file_proto_narration_studio_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CharacterVoiceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CharacterVoiceResponse) ProtoMessage() {}
+
+func (x *CharacterVoiceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CharacterVoiceResponse.ProtoReflect.Descriptor instead.
+func (*CharacterVoiceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *CharacterVoiceResponse) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *CharacterVoiceResponse) GetCharacter() string {
+	if x != nil {
+		return x.Character
+	}
+	return ""
+}
+
+func (x *CharacterVoiceResponse) GetVoice() *CharacterVoice {
+	if x != nil {
+		return x.Voice
+	}
+	return nil
+}
+
+type DeleteCharacterVoiceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCharacterVoiceResponse) Reset() {
+	*x = DeleteCharacterVoiceResponse{}
+	mi := &file_proto_narration_studio_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCharacterVoiceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCharacterVoiceResponse) ProtoMessage() {}
+
+func (x *DeleteCharacterVoiceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCharacterVoiceResponse.ProtoReflect.Descriptor instead.
+func (*DeleteCharacterVoiceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{33}
+}
+
+type GetConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConfigRequest) Reset() {
+	*x = GetConfigRequest{}
+	mi := &file_proto_narration_studio_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigRequest) ProtoMessage() {}
+
+func (x *GetConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigRequest.ProtoReflect.Descriptor instead.
+func (*GetConfigRequest) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{34}
+}
+
+type UpdateConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConfigJson    string                 `protobuf:"bytes,1,opt,name=config_json,json=configJson,proto3" json:"config_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateConfigRequest) Reset() {
+	*x = UpdateConfigRequest{}
+	mi := &file_proto_narration_studio_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateConfigRequest) ProtoMessage() {}
+
+func (x *UpdateConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateConfigRequest.ProtoReflect.Descriptor instead.
+func (*UpdateConfigRequest) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *UpdateConfigRequest) GetConfigJson() string {
+	if x != nil {
+		return x.ConfigJson
+	}
+	return ""
+}
+
+type ConfigResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConfigJson    string                 `protobuf:"bytes,1,opt,name=config_json,json=configJson,proto3" json:"config_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigResponse) Reset() {
+	*x = ConfigResponse{}
+	mi := &file_proto_narration_studio_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigResponse) ProtoMessage() {}
+
+func (x *ConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigResponse.ProtoReflect.Descriptor instead.
+func (*ConfigResponse) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ConfigResponse) GetConfigJson() string {
+	if x != nil {
+		return x.ConfigJson
+	}
+	return ""
+}
+
+type PatchConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // dot separated JSON field names, e.g. "settings.server.auth.key"
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PatchConfigRequest) Reset() {
+	*x = PatchConfigRequest{}
+	mi := &file_proto_narration_studio_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatchConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatchConfigRequest) ProtoMessage() {}
+
+func (x *PatchConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatchConfigRequest.ProtoReflect.Descriptor instead.
+func (*PatchConfigRequest) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *PatchConfigRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *PatchConfigRequest) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type PatchConfigResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PatchConfigResponse) Reset() {
+	*x = PatchConfigResponse{}
+	mi := &file_proto_narration_studio_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatchConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatchConfigResponse) ProtoMessage() {}
+
+func (x *PatchConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatchConfigResponse.ProtoReflect.Descriptor instead.
+func (*PatchConfigResponse) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *PatchConfigResponse) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *PatchConfigResponse) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type GetConfigValueRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConfigValueRequest) Reset() {
+	*x = GetConfigValueRequest{}
+	mi := &file_proto_narration_studio_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConfigValueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigValueRequest) ProtoMessage() {}
+
+func (x *GetConfigValueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigValueRequest.ProtoReflect.Descriptor instead.
+func (*GetConfigValueRequest) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *GetConfigValueRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type GetConfigValueResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	ValueJson     string                 `protobuf:"bytes,2,opt,name=value_json,json=valueJson,proto3" json:"value_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConfigValueResponse) Reset() {
+	*x = GetConfigValueResponse{}
+	mi := &file_proto_narration_studio_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConfigValueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigValueResponse) ProtoMessage() {}
+
+func (x *GetConfigValueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigValueResponse.ProtoReflect.Descriptor instead.
+func (*GetConfigValueResponse) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *GetConfigValueResponse) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *GetConfigValueResponse) GetValueJson() string {
+	if x != nil {
+		return x.ValueJson
+	}
+	return ""
+}
+
+type GetConfigSchemaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConfigSchemaRequest) Reset() {
+	*x = GetConfigSchemaRequest{}
+	mi := &file_proto_narration_studio_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConfigSchemaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigSchemaRequest) ProtoMessage() {}
+
+func (x *GetConfigSchemaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigSchemaRequest.ProtoReflect.Descriptor instead.
+func (*GetConfigSchemaRequest) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{41}
+}
+
+type GetConfigSchemaResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SchemaJson    string                 `protobuf:"bytes,1,opt,name=schema_json,json=schemaJson,proto3" json:"schema_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConfigSchemaResponse) Reset() {
+	*x = GetConfigSchemaResponse{}
+	mi := &file_proto_narration_studio_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConfigSchemaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigSchemaResponse) ProtoMessage() {}
+
+func (x *GetConfigSchemaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_narration_studio_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigSchemaResponse.ProtoReflect.Descriptor instead.
+func (*GetConfigSchemaResponse) Descriptor() ([]byte, []int) {
+	return file_proto_narration_studio_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *GetConfigSchemaResponse) GetSchemaJson() string {
+	if x != nil {
+		return x.SchemaJson
+	}
+	return ""
+}
+
+var File_proto_narration_studio_proto protoreflect.FileDescriptor
+
+const file_proto_narration_studio_proto_rawDesc = "" +
+	"\n" +
+	"\x1cproto/narration_studio.proto\x12\n" +
+	"nstudio.v1\"\xf3\x02\n" +
+	"\fAudioOptions\x12/\n" +
+	"\x06format\x18\x01 \x01(\x0e2\x17.nstudio.v1.AudioFormatR\x06format\x12\x1f\n" +
+	"\vsample_rate\x18\x02 \x01(\x05R\n" +
+	"sampleRate\x12\x1a\n" +
+	"\bchannels\x18\x03 \x01(\x05R\bchannels\x12\x1b\n" +
+	"\tbit_depth\x18\x04 \x01(\x05R\bbitDepth\x12\x1d\n" +
+	"\aquality\x18\x05 \x01(\x01H\x00R\aquality\x88\x01\x01\x12\x1d\n" +
+	"\abitrate\x18\x06 \x01(\x05H\x01R\abitrate\x88\x01\x01\x12:\n" +
+	"\fbitrate_mode\x18\a \x01(\x0e2\x17.nstudio.v1.BitrateModeR\vbitrateMode\x120\n" +
+	"\x11compression_level\x18\b \x01(\x05H\x02R\x10compressionLevel\x88\x01\x01B\n" +
+	"\n" +
+	"\b_qualityB\n" +
+	"\n" +
+	"\b_bitrateB\x14\n" +
+	"\x12_compression_level\"\x8f\x01\n" +
+	"\x11SynthesizeRequest\x12\x18\n" +
+	"\aprofile\x18\x01 \x01(\tR\aprofile\x12\x1c\n" +
+	"\tcharacter\x18\x02 \x01(\tR\tcharacter\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\x12.\n" +
+	"\x05audio\x18\x04 \x01(\v2\x18.nstudio.v1.AudioOptionsR\x05audio\"\xa0\x01\n" +
+	"\x16SynthesizeVoiceRequest\x12\x16\n" +
+	"\x06engine\x18\x01 \x01(\tR\x06engine\x12\x14\n" +
+	"\x05model\x18\x02 \x01(\tR\x05model\x12\x14\n" +
+	"\x05voice\x18\x03 \x01(\tR\x05voice\x12\x12\n" +
+	"\x04text\x18\x04 \x01(\tR\x04text\x12.\n" +
+	"\x05audio\x18\x05 \x01(\v2\x18.nstudio.v1.AudioOptionsR\x05audio\"\xd8\x01\n" +
+	"\x12SynthesizeResponse\x12\x14\n" +
+	"\x05audio\x18\x01 \x01(\fR\x05audio\x12/\n" +
+	"\x06format\x18\x02 \x01(\x0e2\x17.nstudio.v1.AudioFormatR\x06format\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\x12\x1f\n" +
+	"\vsample_rate\x18\x04 \x01(\x05R\n" +
+	"sampleRate\x12\x1a\n" +
+	"\bchannels\x18\x05 \x01(\x05R\bchannels\x12\x1b\n" +
+	"\tbit_depth\x18\x06 \x01(\x05R\bbitDepth\"\xc2\x01\n" +
+	"\n" +
+	"AudioChunk\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x05R\bsequence\x12\x10\n" +
+	"\x03pcm\x18\x02 \x01(\fR\x03pcm\x12\x16\n" +
+	"\x04text\x18\x03 \x01(\tB\x02\x18\x01R\x04text\x12\x14\n" +
+	"\x05final\x18\x04 \x01(\bR\x05final\x12\x1f\n" +
+	"\vsample_rate\x18\x05 \x01(\x05R\n" +
+	"sampleRate\x12\x1a\n" +
+	"\bchannels\x18\x06 \x01(\x05R\bchannels\x12\x1b\n" +
+	"\tbit_depth\x18\a \x01(\x05R\bbitDepth\"\x8d\x01\n" +
+	"\x06Engine\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12*\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x16.nstudio.v1.EngineTypeR\x04type\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\x12\x1f\n" +
+	"\vmodel_count\x18\x05 \x01(\x05R\n" +
+	"modelCount\"C\n" +
+	"\x05Model\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06engine\x18\x03 \x01(\tR\x06engine\"C\n" +
+	"\x05Voice\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06gender\x18\x03 \x01(\tR\x06gender\"\x14\n" +
+	"\x12ListEnginesRequest\"C\n" +
+	"\x13ListEnginesResponse\x12,\n" +
+	"\aengines\x18\x01 \x03(\v2\x12.nstudio.v1.EngineR\aengines\"+\n" +
+	"\x11ListModelsRequest\x12\x16\n" +
+	"\x06engine\x18\x01 \x01(\tR\x06engine\"W\n" +
+	"\x12ListModelsResponse\x12\x16\n" +
+	"\x06engine\x18\x01 \x01(\tR\x06engine\x12)\n" +
+	"\x06models\x18\x02 \x03(\v2\x11.nstudio.v1.ModelR\x06models\"A\n" +
+	"\x11ListVoicesRequest\x12\x16\n" +
+	"\x06engine\x18\x01 \x01(\tR\x06engine\x12\x14\n" +
+	"\x05model\x18\x02 \x01(\tR\x05model\"?\n" +
+	"\x12ListVoicesResponse\x12)\n" +
+	"\x06voices\x18\x01 \x03(\v2\x11.nstudio.v1.VoiceR\x06voices\"\x15\n" +
+	"\x13GetVoiceTreeRequest\"\\\n" +
+	"\vModelVoices\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12)\n" +
+	"\x06voices\x18\x03 \x03(\v2\x11.nstudio.v1.VoiceR\x06voices\"\xa3\x01\n" +
+	"\fEngineVoices\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12*\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x16.nstudio.v1.EngineTypeR\x04type\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\x12/\n" +
+	"\x06models\x18\x05 \x03(\v2\x17.nstudio.v1.ModelVoicesR\x06models\"J\n" +
+	"\x14GetVoiceTreeResponse\x122\n" +
+	"\aengines\x18\x01 \x03(\v2\x18.nstudio.v1.EngineVoicesR\aengines\"h\n" +
+	"\x0eCharacterVoice\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06engine\x18\x02 \x01(\tR\x06engine\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\x12\x14\n" +
+	"\x05voice\x18\x04 \x01(\tR\x05voice\"\xe2\x01\n" +
+	"\x0fProfileSettings\x12R\n" +
+	"\rmodel_toggles\x18\x01 \x03(\v2-.nstudio.v1.ProfileSettings.ModelTogglesEntryR\fmodelToggles\x12(\n" +
+	"\rcache_enabled\x18\x02 \x01(\bH\x00R\fcacheEnabled\x88\x01\x01\x1a?\n" +
+	"\x11ModelTogglesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\bR\x05value:\x028\x01B\x10\n" +
+	"\x0e_cache_enabled\"\xd6\x02\n" +
+	"\aProfile\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\tR\tupdatedAt\x127\n" +
+	"\x06voices\x18\x06 \x03(\v2\x1f.nstudio.v1.Profile.VoicesEntryR\x06voices\x127\n" +
+	"\bsettings\x18\a \x01(\v2\x1b.nstudio.v1.ProfileSettingsR\bsettings\x1aU\n" +
+	"\vVoicesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.nstudio.v1.CharacterVoiceR\x05value:\x028\x01\"\xb5\x01\n" +
+	"\x0eProfileSummary\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\tR\tupdatedAt\x12\x1f\n" +
+	"\vvoice_count\x18\x06 \x01(\x05R\n" +
+	"voiceCount\"\x15\n" +
+	"\x13ListProfilesRequest\"N\n" +
+	"\x14ListProfilesResponse\x126\n" +
+	"\bprofiles\x18\x01 \x03(\v2\x1a.nstudio.v1.ProfileSummaryR\bprofiles\"-\n" +
+	"\x11GetProfileRequest\x12\x18\n" +
+	"\aprofile\x18\x01 \x01(\tR\aprofile\"\\\n" +
+	"\x14CreateProfileRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\"0\n" +
+	"\x14DeleteProfileRequest\x12\x18\n" +
+	"\aprofile\x18\x01 \x01(\tR\aprofile\"\x17\n" +
+	"\x15DeleteProfileResponse\"4\n" +
+	"\x18ListProfileVoicesRequest\x12\x18\n" +
+	"\aprofile\x18\x01 \x01(\tR\aprofile\"\xf7\x01\n" +
+	"\x19ListProfileVoicesResponse\x12\x18\n" +
+	"\aprofile\x18\x01 \x01(\tR\aprofile\x12I\n" +
+	"\x06voices\x18\x02 \x03(\v21.nstudio.v1.ListProfileVoicesResponse.VoicesEntryR\x06voices\x12\x1e\n" +
+	"\n" +
+	"characters\x18\x03 \x03(\tR\n" +
+	"characters\x1aU\n" +
+	"\vVoicesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.nstudio.v1.CharacterVoiceR\x05value:\x028\x01\"O\n" +
+	"\x15CharacterVoiceRequest\x12\x18\n" +
+	"\aprofile\x18\x01 \x01(\tR\aprofile\x12\x1c\n" +
+	"\tcharacter\x18\x02 \x01(\tR\tcharacter\"\x84\x01\n" +
+	"\x18SetCharacterVoiceRequest\x12\x18\n" +
+	"\aprofile\x18\x01 \x01(\tR\aprofile\x12\x1c\n" +
+	"\tcharacter\x18\x02 \x01(\tR\tcharacter\x120\n" +
+	"\x05voice\x18\x03 \x01(\v2\x1a.nstudio.v1.CharacterVoiceR\x05voice\"\x82\x01\n" +
+	"\x16CharacterVoiceResponse\x12\x18\n" +
+	"\aprofile\x18\x01 \x01(\tR\aprofile\x12\x1c\n" +
+	"\tcharacter\x18\x02 \x01(\tR\tcharacter\x120\n" +
+	"\x05voice\x18\x03 \x01(\v2\x1a.nstudio.v1.CharacterVoiceR\x05voice\"\x1e\n" +
+	"\x1cDeleteCharacterVoiceResponse\"\x12\n" +
+	"\x10GetConfigRequest\"6\n" +
+	"\x13UpdateConfigRequest\x12\x1f\n" +
+	"\vconfig_json\x18\x01 \x01(\tR\n" +
+	"configJson\"1\n" +
+	"\x0eConfigResponse\x12\x1f\n" +
+	"\vconfig_json\x18\x01 \x01(\tR\n" +
+	"configJson\">\n" +
+	"\x12PatchConfigRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"?\n" +
+	"\x13PatchConfigResponse\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"+\n" +
+	"\x15GetConfigValueRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"K\n" +
+	"\x16GetConfigValueResponse\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1d\n" +
+	"\n" +
+	"value_json\x18\x02 \x01(\tR\tvalueJson\"\x18\n" +
+	"\x16GetConfigSchemaRequest\":\n" +
+	"\x17GetConfigSchemaResponse\x12\x1f\n" +
+	"\vschema_json\x18\x01 \x01(\tR\n" +
+	"schemaJson*\xa0\x01\n" +
+	"\vAudioFormat\x12\x1c\n" +
+	"\x18AUDIO_FORMAT_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10AUDIO_FORMAT_WAV\x10\x01\x12\x15\n" +
+	"\x11AUDIO_FORMAT_FLAC\x10\x02\x12\x14\n" +
+	"\x10AUDIO_FORMAT_OGG\x10\x03\x12\x14\n" +
+	"\x10AUDIO_FORMAT_MP3\x10\x04\x12\x1a\n" +
+	"\x16AUDIO_FORMAT_PCM_S16LE\x10\x05*W\n" +
+	"\vBitrateMode\x12\x1c\n" +
+	"\x18BITRATE_MODE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10BITRATE_MODE_CBR\x10\x01\x12\x14\n" +
+	"\x10BITRATE_MODE_VBR\x10\x02*U\n" +
+	"\n" +
+	"EngineType\x12\x1b\n" +
+	"\x17ENGINE_TYPE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11ENGINE_TYPE_LOCAL\x10\x01\x12\x13\n" +
+	"\x0fENGINE_TYPE_API\x10\x022\x83\x02\n" +
+	"\x10SynthesisService\x12K\n" +
+	"\n" +
+	"Synthesize\x12\x1d.nstudio.v1.SynthesizeRequest\x1a\x1e.nstudio.v1.SynthesizeResponse\x12U\n" +
+	"\x0fSynthesizeVoice\x12\".nstudio.v1.SynthesizeVoiceRequest\x1a\x1e.nstudio.v1.SynthesizeResponse\x12K\n" +
+	"\x10SynthesizeStream\x12\x1d.nstudio.v1.SynthesizeRequest\x1a\x16.nstudio.v1.AudioChunk0\x012\xcc\x02\n" +
+	"\rEngineService\x12N\n" +
+	"\vListEngines\x12\x1e.nstudio.v1.ListEnginesRequest\x1a\x1f.nstudio.v1.ListEnginesResponse\x12K\n" +
+	"\n" +
+	"ListModels\x12\x1d.nstudio.v1.ListModelsRequest\x1a\x1e.nstudio.v1.ListModelsResponse\x12K\n" +
+	"\n" +
+	"ListVoices\x12\x1d.nstudio.v1.ListVoicesRequest\x1a\x1e.nstudio.v1.ListVoicesResponse\x12Q\n" +
+	"\fGetVoiceTree\x12\x1f.nstudio.v1.GetVoiceTreeRequest\x1a .nstudio.v1.GetVoiceTreeResponse2\xc5\x05\n" +
+	"\x0eProfileService\x12Q\n" +
+	"\fListProfiles\x12\x1f.nstudio.v1.ListProfilesRequest\x1a .nstudio.v1.ListProfilesResponse\x12@\n" +
+	"\n" +
+	"GetProfile\x12\x1d.nstudio.v1.GetProfileRequest\x1a\x13.nstudio.v1.Profile\x12F\n" +
+	"\rCreateProfile\x12 .nstudio.v1.CreateProfileRequest\x1a\x13.nstudio.v1.Profile\x12T\n" +
+	"\rDeleteProfile\x12 .nstudio.v1.DeleteProfileRequest\x1a!.nstudio.v1.DeleteProfileResponse\x12`\n" +
+	"\x11ListProfileVoices\x12$.nstudio.v1.ListProfileVoicesRequest\x1a%.nstudio.v1.ListProfileVoicesResponse\x12Z\n" +
+	"\x11GetCharacterVoice\x12!.nstudio.v1.CharacterVoiceRequest\x1a\".nstudio.v1.CharacterVoiceResponse\x12]\n" +
+	"\x11SetCharacterVoice\x12$.nstudio.v1.SetCharacterVoiceRequest\x1a\".nstudio.v1.CharacterVoiceResponse\x12c\n" +
+	"\x14DeleteCharacterVoice\x12!.nstudio.v1.CharacterVoiceRequest\x1a(.nstudio.v1.DeleteCharacterVoiceResponse2\xa8\x03\n" +
+	"\rConfigService\x12E\n" +
+	"\tGetConfig\x12\x1c.nstudio.v1.GetConfigRequest\x1a\x1a.nstudio.v1.ConfigResponse\x12K\n" +
+	"\fUpdateConfig\x12\x1f.nstudio.v1.UpdateConfigRequest\x1a\x1a.nstudio.v1.ConfigResponse\x12N\n" +
+	"\vPatchConfig\x12\x1e.nstudio.v1.PatchConfigRequest\x1a\x1f.nstudio.v1.PatchConfigResponse\x12W\n" +
+	"\x0eGetConfigValue\x12!.nstudio.v1.GetConfigValueRequest\x1a\".nstudio.v1.GetConfigValueResponse\x12Z\n" +
+	"\x0fGetConfigSchema\x12\".nstudio.v1.GetConfigSchemaRequest\x1a#.nstudio.v1.GetConfigSchemaResponseB1Z\x1anstudio/app/server/grpc/pb\xaa\x02\x12NarrationStudio.V1b\x06proto3"
+
+var (
+	file_proto_narration_studio_proto_rawDescOnce sync.Once
+	file_proto_narration_studio_proto_rawDescData []byte
+)
+
+func file_proto_narration_studio_proto_rawDescGZIP() []byte {
+	file_proto_narration_studio_proto_rawDescOnce.Do(func() {
+		file_proto_narration_studio_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_narration_studio_proto_rawDesc), len(file_proto_narration_studio_proto_rawDesc)))
+	})
+	return file_proto_narration_studio_proto_rawDescData
+}
+
+var file_proto_narration_studio_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_proto_narration_studio_proto_msgTypes = make([]protoimpl.MessageInfo, 46)
+var file_proto_narration_studio_proto_goTypes = []any{
+	(AudioFormat)(0),                     // 0: nstudio.v1.AudioFormat
+	(BitrateMode)(0),                     // 1: nstudio.v1.BitrateMode
+	(EngineType)(0),                      // 2: nstudio.v1.EngineType
+	(*AudioOptions)(nil),                 // 3: nstudio.v1.AudioOptions
+	(*SynthesizeRequest)(nil),            // 4: nstudio.v1.SynthesizeRequest
+	(*SynthesizeVoiceRequest)(nil),       // 5: nstudio.v1.SynthesizeVoiceRequest
+	(*SynthesizeResponse)(nil),           // 6: nstudio.v1.SynthesizeResponse
+	(*AudioChunk)(nil),                   // 7: nstudio.v1.AudioChunk
+	(*Engine)(nil),                       // 8: nstudio.v1.Engine
+	(*Model)(nil),                        // 9: nstudio.v1.Model
+	(*Voice)(nil),                        // 10: nstudio.v1.Voice
+	(*ListEnginesRequest)(nil),           // 11: nstudio.v1.ListEnginesRequest
+	(*ListEnginesResponse)(nil),          // 12: nstudio.v1.ListEnginesResponse
+	(*ListModelsRequest)(nil),            // 13: nstudio.v1.ListModelsRequest
+	(*ListModelsResponse)(nil),           // 14: nstudio.v1.ListModelsResponse
+	(*ListVoicesRequest)(nil),            // 15: nstudio.v1.ListVoicesRequest
+	(*ListVoicesResponse)(nil),           // 16: nstudio.v1.ListVoicesResponse
+	(*GetVoiceTreeRequest)(nil),          // 17: nstudio.v1.GetVoiceTreeRequest
+	(*ModelVoices)(nil),                  // 18: nstudio.v1.ModelVoices
+	(*EngineVoices)(nil),                 // 19: nstudio.v1.EngineVoices
+	(*GetVoiceTreeResponse)(nil),         // 20: nstudio.v1.GetVoiceTreeResponse
+	(*CharacterVoice)(nil),               // 21: nstudio.v1.CharacterVoice
+	(*ProfileSettings)(nil),              // 22: nstudio.v1.ProfileSettings
+	(*Profile)(nil),                      // 23: nstudio.v1.Profile
+	(*ProfileSummary)(nil),               // 24: nstudio.v1.ProfileSummary
+	(*ListProfilesRequest)(nil),          // 25: nstudio.v1.ListProfilesRequest
+	(*ListProfilesResponse)(nil),         // 26: nstudio.v1.ListProfilesResponse
+	(*GetProfileRequest)(nil),            // 27: nstudio.v1.GetProfileRequest
+	(*CreateProfileRequest)(nil),         // 28: nstudio.v1.CreateProfileRequest
+	(*DeleteProfileRequest)(nil),         // 29: nstudio.v1.DeleteProfileRequest
+	(*DeleteProfileResponse)(nil),        // 30: nstudio.v1.DeleteProfileResponse
+	(*ListProfileVoicesRequest)(nil),     // 31: nstudio.v1.ListProfileVoicesRequest
+	(*ListProfileVoicesResponse)(nil),    // 32: nstudio.v1.ListProfileVoicesResponse
+	(*CharacterVoiceRequest)(nil),        // 33: nstudio.v1.CharacterVoiceRequest
+	(*SetCharacterVoiceRequest)(nil),     // 34: nstudio.v1.SetCharacterVoiceRequest
+	(*CharacterVoiceResponse)(nil),       // 35: nstudio.v1.CharacterVoiceResponse
+	(*DeleteCharacterVoiceResponse)(nil), // 36: nstudio.v1.DeleteCharacterVoiceResponse
+	(*GetConfigRequest)(nil),             // 37: nstudio.v1.GetConfigRequest
+	(*UpdateConfigRequest)(nil),          // 38: nstudio.v1.UpdateConfigRequest
+	(*ConfigResponse)(nil),               // 39: nstudio.v1.ConfigResponse
+	(*PatchConfigRequest)(nil),           // 40: nstudio.v1.PatchConfigRequest
+	(*PatchConfigResponse)(nil),          // 41: nstudio.v1.PatchConfigResponse
+	(*GetConfigValueRequest)(nil),        // 42: nstudio.v1.GetConfigValueRequest
+	(*GetConfigValueResponse)(nil),       // 43: nstudio.v1.GetConfigValueResponse
+	(*GetConfigSchemaRequest)(nil),       // 44: nstudio.v1.GetConfigSchemaRequest
+	(*GetConfigSchemaResponse)(nil),      // 45: nstudio.v1.GetConfigSchemaResponse
+	nil,                                  // 46: nstudio.v1.ProfileSettings.ModelTogglesEntry
+	nil,                                  // 47: nstudio.v1.Profile.VoicesEntry
+	nil,                                  // 48: nstudio.v1.ListProfileVoicesResponse.VoicesEntry
+}
+var file_proto_narration_studio_proto_depIdxs = []int32{
+	0,  // 0: nstudio.v1.AudioOptions.format:type_name -> nstudio.v1.AudioFormat
+	1,  // 1: nstudio.v1.AudioOptions.bitrate_mode:type_name -> nstudio.v1.BitrateMode
+	3,  // 2: nstudio.v1.SynthesizeRequest.audio:type_name -> nstudio.v1.AudioOptions
+	3,  // 3: nstudio.v1.SynthesizeVoiceRequest.audio:type_name -> nstudio.v1.AudioOptions
+	0,  // 4: nstudio.v1.SynthesizeResponse.format:type_name -> nstudio.v1.AudioFormat
+	2,  // 5: nstudio.v1.Engine.type:type_name -> nstudio.v1.EngineType
+	8,  // 6: nstudio.v1.ListEnginesResponse.engines:type_name -> nstudio.v1.Engine
+	9,  // 7: nstudio.v1.ListModelsResponse.models:type_name -> nstudio.v1.Model
+	10, // 8: nstudio.v1.ListVoicesResponse.voices:type_name -> nstudio.v1.Voice
+	10, // 9: nstudio.v1.ModelVoices.voices:type_name -> nstudio.v1.Voice
+	2,  // 10: nstudio.v1.EngineVoices.type:type_name -> nstudio.v1.EngineType
+	18, // 11: nstudio.v1.EngineVoices.models:type_name -> nstudio.v1.ModelVoices
+	19, // 12: nstudio.v1.GetVoiceTreeResponse.engines:type_name -> nstudio.v1.EngineVoices
+	46, // 13: nstudio.v1.ProfileSettings.model_toggles:type_name -> nstudio.v1.ProfileSettings.ModelTogglesEntry
+	47, // 14: nstudio.v1.Profile.voices:type_name -> nstudio.v1.Profile.VoicesEntry
+	22, // 15: nstudio.v1.Profile.settings:type_name -> nstudio.v1.ProfileSettings
+	24, // 16: nstudio.v1.ListProfilesResponse.profiles:type_name -> nstudio.v1.ProfileSummary
+	48, // 17: nstudio.v1.ListProfileVoicesResponse.voices:type_name -> nstudio.v1.ListProfileVoicesResponse.VoicesEntry
+	21, // 18: nstudio.v1.SetCharacterVoiceRequest.voice:type_name -> nstudio.v1.CharacterVoice
+	21, // 19: nstudio.v1.CharacterVoiceResponse.voice:type_name -> nstudio.v1.CharacterVoice
+	21, // 20: nstudio.v1.Profile.VoicesEntry.value:type_name -> nstudio.v1.CharacterVoice
+	21, // 21: nstudio.v1.ListProfileVoicesResponse.VoicesEntry.value:type_name -> nstudio.v1.CharacterVoice
+	4,  // 22: nstudio.v1.SynthesisService.Synthesize:input_type -> nstudio.v1.SynthesizeRequest
+	5,  // 23: nstudio.v1.SynthesisService.SynthesizeVoice:input_type -> nstudio.v1.SynthesizeVoiceRequest
+	4,  // 24: nstudio.v1.SynthesisService.SynthesizeStream:input_type -> nstudio.v1.SynthesizeRequest
+	11, // 25: nstudio.v1.EngineService.ListEngines:input_type -> nstudio.v1.ListEnginesRequest
+	13, // 26: nstudio.v1.EngineService.ListModels:input_type -> nstudio.v1.ListModelsRequest
+	15, // 27: nstudio.v1.EngineService.ListVoices:input_type -> nstudio.v1.ListVoicesRequest
+	17, // 28: nstudio.v1.EngineService.GetVoiceTree:input_type -> nstudio.v1.GetVoiceTreeRequest
+	25, // 29: nstudio.v1.ProfileService.ListProfiles:input_type -> nstudio.v1.ListProfilesRequest
+	27, // 30: nstudio.v1.ProfileService.GetProfile:input_type -> nstudio.v1.GetProfileRequest
+	28, // 31: nstudio.v1.ProfileService.CreateProfile:input_type -> nstudio.v1.CreateProfileRequest
+	29, // 32: nstudio.v1.ProfileService.DeleteProfile:input_type -> nstudio.v1.DeleteProfileRequest
+	31, // 33: nstudio.v1.ProfileService.ListProfileVoices:input_type -> nstudio.v1.ListProfileVoicesRequest
+	33, // 34: nstudio.v1.ProfileService.GetCharacterVoice:input_type -> nstudio.v1.CharacterVoiceRequest
+	34, // 35: nstudio.v1.ProfileService.SetCharacterVoice:input_type -> nstudio.v1.SetCharacterVoiceRequest
+	33, // 36: nstudio.v1.ProfileService.DeleteCharacterVoice:input_type -> nstudio.v1.CharacterVoiceRequest
+	37, // 37: nstudio.v1.ConfigService.GetConfig:input_type -> nstudio.v1.GetConfigRequest
+	38, // 38: nstudio.v1.ConfigService.UpdateConfig:input_type -> nstudio.v1.UpdateConfigRequest
+	40, // 39: nstudio.v1.ConfigService.PatchConfig:input_type -> nstudio.v1.PatchConfigRequest
+	42, // 40: nstudio.v1.ConfigService.GetConfigValue:input_type -> nstudio.v1.GetConfigValueRequest
+	44, // 41: nstudio.v1.ConfigService.GetConfigSchema:input_type -> nstudio.v1.GetConfigSchemaRequest
+	6,  // 42: nstudio.v1.SynthesisService.Synthesize:output_type -> nstudio.v1.SynthesizeResponse
+	6,  // 43: nstudio.v1.SynthesisService.SynthesizeVoice:output_type -> nstudio.v1.SynthesizeResponse
+	7,  // 44: nstudio.v1.SynthesisService.SynthesizeStream:output_type -> nstudio.v1.AudioChunk
+	12, // 45: nstudio.v1.EngineService.ListEngines:output_type -> nstudio.v1.ListEnginesResponse
+	14, // 46: nstudio.v1.EngineService.ListModels:output_type -> nstudio.v1.ListModelsResponse
+	16, // 47: nstudio.v1.EngineService.ListVoices:output_type -> nstudio.v1.ListVoicesResponse
+	20, // 48: nstudio.v1.EngineService.GetVoiceTree:output_type -> nstudio.v1.GetVoiceTreeResponse
+	26, // 49: nstudio.v1.ProfileService.ListProfiles:output_type -> nstudio.v1.ListProfilesResponse
+	23, // 50: nstudio.v1.ProfileService.GetProfile:output_type -> nstudio.v1.Profile
+	23, // 51: nstudio.v1.ProfileService.CreateProfile:output_type -> nstudio.v1.Profile
+	30, // 52: nstudio.v1.ProfileService.DeleteProfile:output_type -> nstudio.v1.DeleteProfileResponse
+	32, // 53: nstudio.v1.ProfileService.ListProfileVoices:output_type -> nstudio.v1.ListProfileVoicesResponse
+	35, // 54: nstudio.v1.ProfileService.GetCharacterVoice:output_type -> nstudio.v1.CharacterVoiceResponse
+	35, // 55: nstudio.v1.ProfileService.SetCharacterVoice:output_type -> nstudio.v1.CharacterVoiceResponse
+	36, // 56: nstudio.v1.ProfileService.DeleteCharacterVoice:output_type -> nstudio.v1.DeleteCharacterVoiceResponse
+	39, // 57: nstudio.v1.ConfigService.GetConfig:output_type -> nstudio.v1.ConfigResponse
+	39, // 58: nstudio.v1.ConfigService.UpdateConfig:output_type -> nstudio.v1.ConfigResponse
+	41, // 59: nstudio.v1.ConfigService.PatchConfig:output_type -> nstudio.v1.PatchConfigResponse
+	43, // 60: nstudio.v1.ConfigService.GetConfigValue:output_type -> nstudio.v1.GetConfigValueResponse
+	45, // 61: nstudio.v1.ConfigService.GetConfigSchema:output_type -> nstudio.v1.GetConfigSchemaResponse
+	42, // [42:62] is the sub-list for method output_type
+	22, // [22:42] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
+}
+
+func init() { file_proto_narration_studio_proto_init() }
+func file_proto_narration_studio_proto_init() {
+	if File_proto_narration_studio_proto != nil {
+		return
+	}
+	file_proto_narration_studio_proto_msgTypes[0].OneofWrappers = []any{}
+	file_proto_narration_studio_proto_msgTypes[19].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_narration_studio_proto_rawDesc), len(file_proto_narration_studio_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   46,
+			NumExtensions: 0,
+			NumServices:   4,
+		},
+		GoTypes:           file_proto_narration_studio_proto_goTypes,
+		DependencyIndexes: file_proto_narration_studio_proto_depIdxs,
+		EnumInfos:         file_proto_narration_studio_proto_enumTypes,
+		MessageInfos:      file_proto_narration_studio_proto_msgTypes,
+	}.Build()
+	File_proto_narration_studio_proto = out.File
+	file_proto_narration_studio_proto_goTypes = nil
+	file_proto_narration_studio_proto_depIdxs = nil
+}
@@ -0,0 +1,200 @@
+package grpc
+
+import (
+	"context"
+	"nstudio/app/common/util"
+	"nstudio/app/server/grpc/pb"
+	"nstudio/app/tts/profile"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type profileServer struct {
+	pb.UnimplementedProfileServiceServer
+}
+
+func (server *profileServer) ListProfiles(ctx context.Context, request *pb.ListProfilesRequest) (*pb.ListProfilesResponse, error) {
+	profiles, err := profile.GetManager().GetAllProfiles()
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to list profiles: "+err.Error())
+	}
+
+	summaries := make([]*pb.ProfileSummary, 0, len(profiles))
+	for _, metadata := range profiles {
+		summaries = append(summaries, &pb.ProfileSummary{
+			Id:          metadata.ID,
+			Name:        metadata.Name,
+			Description: metadata.Description,
+			CreatedAt:   metadata.CreatedAt,
+			UpdatedAt:   metadata.UpdatedAt,
+			VoiceCount:  int32(metadata.VoiceCount),
+		})
+	}
+
+	return &pb.ListProfilesResponse{Profiles: summaries}, nil
+}
+
+func (server *profileServer) GetProfile(ctx context.Context, request *pb.GetProfileRequest) (*pb.Profile, error) {
+	if request.GetProfile() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Profile ID is required")
+	}
+
+	requestedProfile, err := profile.GetManager().GetProfile(request.GetProfile())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "Profile not found: "+err.Error())
+	}
+
+	return profileToProto(requestedProfile), nil
+}
+
+func (server *profileServer) CreateProfile(ctx context.Context, request *pb.CreateProfileRequest) (*pb.Profile, error) {
+	if request.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Profile ID is required")
+	}
+
+	newProfile, err := profile.GetManager().CreateProfile(request.GetId(), request.GetName(), request.GetDescription())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Failed to create profile: "+err.Error())
+	}
+
+	return profileToProto(newProfile), nil
+}
+
+func (server *profileServer) DeleteProfile(ctx context.Context, request *pb.DeleteProfileRequest) (*pb.DeleteProfileResponse, error) {
+	if request.GetProfile() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Profile ID is required")
+	}
+
+	if err := profile.GetManager().DeleteProfile(request.GetProfile()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Failed to delete profile: "+err.Error())
+	}
+
+	return &pb.DeleteProfileResponse{}, nil
+}
+
+func (server *profileServer) ListProfileVoices(ctx context.Context, request *pb.ListProfileVoicesRequest) (*pb.ListProfileVoicesResponse, error) {
+	if request.GetProfile() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Profile ID is required")
+	}
+
+	requestedProfile, err := profile.GetManager().GetProfile(request.GetProfile())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "Profile not found: "+err.Error())
+	}
+
+	return &pb.ListProfileVoicesResponse{
+		Profile:    request.GetProfile(),
+		Voices:     profileVoicesToProto(requestedProfile),
+		Characters: requestedProfile.GetCharacters(),
+	}, nil
+}
+
+func (server *profileServer) GetCharacterVoice(ctx context.Context, request *pb.CharacterVoiceRequest) (*pb.CharacterVoiceResponse, error) {
+	if request.GetProfile() == "" || request.GetCharacter() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Profile ID and character name are required")
+	}
+
+	voice, err := profile.GetManager().GetVoiceConfig(request.GetProfile(), request.GetCharacter())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return &pb.CharacterVoiceResponse{
+		Profile:   request.GetProfile(),
+		Character: request.GetCharacter(),
+		Voice:     characterVoiceToProto(voice),
+	}, nil
+}
+
+func (server *profileServer) SetCharacterVoice(ctx context.Context, request *pb.SetCharacterVoiceRequest) (*pb.CharacterVoiceResponse, error) {
+	if request.GetProfile() == "" || request.GetCharacter() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Profile ID and character name are required")
+	}
+
+	manager := profile.GetManager()
+
+	var voice *util.CharacterVoice
+	if requested := request.GetVoice(); requested != nil {
+		if requested.GetEngine() == "" || requested.GetModel() == "" || requested.GetVoice() == "" {
+			return nil, status.Error(codes.InvalidArgument, "Voice engine, model and voice are required")
+		}
+
+		voice = &util.CharacterVoice{
+			Name:   request.GetCharacter(),
+			Engine: requested.GetEngine(),
+			Model:  requested.GetModel(),
+			Voice:  requested.GetVoice(),
+		}
+		if err := manager.SetVoiceConfig(request.GetProfile(), request.GetCharacter(), voice); err != nil {
+			return nil, status.Error(codes.NotFound, "Failed to set voice: "+err.Error())
+		}
+	} else {
+		allocated, err := manager.GetOrAllocateVoice(request.GetProfile(), request.GetCharacter())
+		if err != nil {
+			return nil, status.Error(codes.Internal, "Failed to set voice: "+err.Error())
+		}
+		voice = allocated
+	}
+
+	return &pb.CharacterVoiceResponse{
+		Profile:   request.GetProfile(),
+		Character: request.GetCharacter(),
+		Voice:     characterVoiceToProto(voice),
+	}, nil
+}
+
+func (server *profileServer) DeleteCharacterVoice(ctx context.Context, request *pb.CharacterVoiceRequest) (*pb.DeleteCharacterVoiceResponse, error) {
+	if request.GetProfile() == "" || request.GetCharacter() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Profile ID and character name are required")
+	}
+
+	if err := profile.GetManager().RemoveVoiceConfig(request.GetProfile(), request.GetCharacter()); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return &pb.DeleteCharacterVoiceResponse{}, nil
+}
+
+func profileToProto(source *profile.Profile) *pb.Profile {
+	result := &pb.Profile{
+		Id:          source.ID,
+		Name:        source.Name,
+		Description: source.Description,
+		CreatedAt:   source.CreatedAt,
+		UpdatedAt:   source.UpdatedAt,
+		Voices:      profileVoicesToProto(source),
+	}
+
+	if settings := source.GetSettings(); settings != nil {
+		result.Settings = &pb.ProfileSettings{
+			ModelToggles: settings.ModelToggles,
+			CacheEnabled: settings.CacheEnabled,
+		}
+	}
+
+	return result
+}
+
+func profileVoicesToProto(source *profile.Profile) map[string]*pb.CharacterVoice {
+	voices := make(map[string]*pb.CharacterVoice)
+	for _, character := range source.GetCharacters() {
+		if voice, exists := source.GetVoice(character); exists {
+			voices[character] = characterVoiceToProto(voice)
+		}
+	}
+	return voices
+}
+
+func characterVoiceToProto(voice *util.CharacterVoice) *pb.CharacterVoice {
+	if voice == nil {
+		return nil
+	}
+
+	return &pb.CharacterVoice{
+		Name:   voice.Name,
+		Engine: voice.Engine,
+		Model:  voice.Model,
+		Voice:  voice.Voice,
+	}
+}
@@ -0,0 +1,199 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"nstudio/app/common/audio"
+	"nstudio/app/common/util"
+	"nstudio/app/server/grpc/pb"
+	"nstudio/app/server/stats"
+	"nstudio/app/server/synthesis"
+	"nstudio/app/tts"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type synthesisServer struct {
+	pb.UnimplementedSynthesisServiceServer
+}
+
+var audioFormats = map[pb.AudioFormat]string{
+	pb.AudioFormat_AUDIO_FORMAT_UNSPECIFIED: "wav",
+	pb.AudioFormat_AUDIO_FORMAT_WAV:         "wav",
+	pb.AudioFormat_AUDIO_FORMAT_FLAC:        "flac",
+	pb.AudioFormat_AUDIO_FORMAT_OGG:         "ogg",
+	pb.AudioFormat_AUDIO_FORMAT_MP3:         "mp3",
+	pb.AudioFormat_AUDIO_FORMAT_PCM_S16LE:   "pcm_s16le",
+}
+
+func (server *synthesisServer) Synthesize(ctx context.Context, request *pb.SynthesizeRequest) (*pb.SynthesizeResponse, error) {
+	options, err := audioOptionsFromProto(request.GetAudio())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid audio options: "+err.Error())
+	}
+
+	synthesisRequest := synthesis.Request{
+		Profile:   request.GetProfile(),
+		Character: request.GetCharacter(),
+		Text:      request.GetText(),
+		Audio:     options,
+	}
+	if err := synthesisRequest.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	audioObject, err := synthesis.Generate(ctx, synthesisRequest)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	data, err := synthesis.Encode(audioObject, options)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return synthesizeResponse(data, request.GetAudio().GetFormat(), audioObject.Metadata), nil
+}
+
+func (server *synthesisServer) SynthesizeVoice(ctx context.Context, request *pb.SynthesizeVoiceRequest) (*pb.SynthesizeResponse, error) {
+	if request.GetEngine() == "" || request.GetModel() == "" || request.GetVoice() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Engine, model and voice are required")
+	}
+	if strings.TrimSpace(request.GetText()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "Text field is required")
+	}
+	if len(request.GetText()) > synthesis.MaxTextLength {
+		return nil, status.Errorf(codes.InvalidArgument, "Text too long (max %d characters)", synthesis.MaxTextLength)
+	}
+
+	options, err := audioOptionsFromProto(request.GetAudio())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid audio options: "+err.Error())
+	}
+
+	voice := &util.CharacterVoice{
+		Engine: request.GetEngine(),
+		Model:  request.GetModel(),
+		Voice:  request.GetVoice(),
+	}
+
+	audioObject, err := tts.GenerateAudio(ctx, voice, request.GetText())
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		return nil, status.Error(codes.Internal, "Failed to generate speech: "+err.Error())
+	}
+
+	stats.IncrementMessages()
+
+	data, err := synthesis.Encode(audioObject, options)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return synthesizeResponse(data, request.GetAudio().GetFormat(), audioObject.Metadata), nil
+}
+
+func (server *synthesisServer) SynthesizeStream(request *pb.SynthesizeRequest, stream pb.SynthesisService_SynthesizeStreamServer) error {
+	options, err := audioOptionsFromProto(request.GetAudio())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "Invalid audio options: "+err.Error())
+	}
+	if options == nil {
+		options = &synthesis.AudioOptions{Encoding: audio.DefaultEncodeOptions()}
+	}
+	options.Format = "pcm_s16le"
+
+	synthesisRequest := synthesis.Request{
+		Profile:   request.GetProfile(),
+		Character: request.GetCharacter(),
+		Text:      request.GetText(),
+		Audio:     options,
+	}
+	if err := synthesisRequest.Validate(); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx := stream.Context()
+	err = synthesis.Stream(ctx, synthesisRequest, func(chunk synthesis.Chunk) error {
+		return stream.Send(&pb.AudioChunk{
+			Sequence:   int32(chunk.Sequence),
+			Pcm:        chunk.Data,
+			Final:      chunk.Final,
+			SampleRate: int32(chunk.Metadata.SampleRate),
+			Channels:   int32(chunk.Metadata.Channels),
+			BitDepth:   int32(chunk.Metadata.BitDepth),
+		})
+	})
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+// audioOptionsFromProto converts request options, validating encoder settings the same way as the JSON APIs
+func audioOptionsFromProto(options *pb.AudioOptions) (*synthesis.AudioOptions, error) {
+	if options == nil {
+		return nil, nil
+	}
+
+	format, exists := audioFormats[options.GetFormat()]
+	if !exists {
+		return nil, fmt.Errorf("unknown format %d", options.GetFormat())
+	}
+
+	values := make(map[string]interface{})
+	if options.Quality != nil {
+		values["quality"] = options.GetQuality()
+	}
+	if options.Bitrate != nil {
+		values["bitrate"] = float64(options.GetBitrate())
+	}
+	switch options.GetBitrateMode() {
+	case pb.BitrateMode_BITRATE_MODE_CBR:
+		values["bitrate_mode"] = "cbr"
+	case pb.BitrateMode_BITRATE_MODE_VBR:
+		values["bitrate_mode"] = "vbr"
+	}
+	if options.CompressionLevel != nil {
+		values["compression_level"] = float64(options.GetCompressionLevel())
+	}
+
+	encoding, err := audio.ParseEncodeOptions(values)
+	if err != nil {
+		return nil, err
+	}
+
+	return &synthesis.AudioOptions{
+		Format:     format,
+		SampleRate: int(options.GetSampleRate()),
+		Channels:   int(options.GetChannels()),
+		BitDepth:   int(options.GetBitDepth()),
+		Encoding:   encoding,
+	}, nil
+}
+
+func synthesizeResponse(data []byte, format pb.AudioFormat, metadata audio.AudioMetadata) *pb.SynthesizeResponse {
+	if format == pb.AudioFormat_AUDIO_FORMAT_UNSPECIFIED {
+		format = pb.AudioFormat_AUDIO_FORMAT_WAV
+	}
+
+	return &pb.SynthesizeResponse{
+		Audio:       data,
+		Format:      format,
+		ContentType: audio.GetContentType(audioFormats[format]),
+		SampleRate:  int32(metadata.SampleRate),
+		Channels:    int32(metadata.Channels),
+		BitDepth:    int32(metadata.BitDepth),
+	}
+}
@@ -0,0 +1,215 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"nstudio/app/cache"
+	"nstudio/app/server/http/responses"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// handleCacheStats returns the entries, size and hit ratio of every profile's cache, or of one with ?profile=
+func handleCacheStats(context echo.Context) error {
+	statistics, err := cache.GetManager().Stats(context.QueryParam("profile"))
+	if err != nil {
+		return context.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to read cache statistics: " + err.Error(),
+			Code:    500,
+		})
+	}
+
+	return context.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"cache":   statistics,
+	})
+}
+
+// handleClearCache removes cached lines. ?profile=, ?character= and ?olderThanDays= narrow down which ones, without
+// any of them the whole cache is cleared.
+func handleClearCache(context echo.Context) error {
+	options := cache.ClearOptions{
+		Profile:   context.QueryParam("profile"),
+		Character: context.QueryParam("character"),
+	}
+
+	if olderThan := context.QueryParam("olderThanDays"); olderThan != "" {
+		days, err := strconv.ParseFloat(olderThan, 64)
+		if err != nil || days < 0 {
+			return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+				Success: false,
+				Error:   "olderThanDays must be a positive number of days",
+				Code:    400,
+			})
+		}
+		options.OlderThan = time.Duration(days * float64(24*time.Hour))
+	}
+
+	removed, err := cache.GetManager().Clear(options)
+	if errors.Is(err, cache.ErrDisabled) {
+		return context.JSON(http.StatusConflict, responses.ErrorResponse{
+			Success: false,
+			Error:   "The audio cache is disabled",
+			Code:    409,
+		})
+	}
+	if err != nil {
+		return context.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to clear cache: " + err.Error(),
+			Code:    500,
+		})
+	}
+
+	return context.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"removed": removed,
+	})
+}
+
+// handleVerifyCache checks the cache for orphaned files, missing audio and corrupt entries without changing anything
+func handleVerifyCache(context echo.Context) error {
+	return verifyCache(context, false)
+}
+
+// handleRepairCache removes what handleVerifyCache finds
+func handleRepairCache(context echo.Context) error {
+	return verifyCache(context, true)
+}
+
+func verifyCache(context echo.Context, repair bool) error {
+	report, err := cache.GetManager().Verify(repair)
+	if errors.Is(err, cache.ErrDisabled) {
+		return context.JSON(http.StatusConflict, responses.ErrorResponse{
+			Success: false,
+			Error:   "The audio cache is disabled",
+			Code:    409,
+		})
+	}
+	if err != nil {
+		return context.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to verify cache: " + err.Error(),
+			Code:    500,
+		})
+	}
+
+	return context.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"report":  report,
+	})
+}
+
+// isBundleRequest reports whether a request exports or imports a cache bundle
+func isBundleRequest(context echo.Context) bool {
+	return strings.HasPrefix(context.Path(), "/cache/") && strings.HasSuffix(context.Path(), "/bundle")
+}
+
+func isBundleImport(context echo.Context) bool {
+	return context.Request().Method == http.MethodPost && context.Path() == "/cache/bundle"
+}
+
+// handleExportCacheBundle sends every line the ?profile= references as a bundle another install can import
+func handleExportCacheBundle(context echo.Context) error {
+	profileID := context.QueryParam("profile")
+	if profileID == "" {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "profile is required",
+			Code:    400,
+		})
+	}
+
+	if !cache.GetManager().IsEnabled() {
+		return context.JSON(http.StatusConflict, responses.ErrorResponse{
+			Success: false,
+			Error:   "The audio cache is disabled",
+			Code:    409,
+		})
+	}
+
+	return writeBundle(context, "cache_"+profileID, func(writer io.Writer) (*cache.BundleSummary, error) {
+		return cache.GetManager().ExportBundle(writer, profileID, nil)
+	})
+}
+
+// writeBundle streams a bundle as a zip attachment. Once the first byte went out, errors can only end the response.
+func writeBundle(context echo.Context, name string, export func(writer io.Writer) (*cache.BundleSummary, error)) error {
+	writer := context.Response()
+	writer.Header().Set(echo.HeaderContentType, "application/zip")
+	writer.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"%s.zip\"", name))
+	writer.WriteHeader(http.StatusOK)
+
+	_, err := export(writer)
+	return err
+}
+
+// handleImportCacheBundle imports the bundle in the request body into ?profile=, or into the profile it was
+// exported from
+func handleImportCacheBundle(context echo.Context) error {
+	// The zip reader needs to seek, so the bundle is kept in a temporary file while it is imported
+	bundleFile, err := os.CreateTemp("", "nstudio-bundle-*.zip")
+	if err != nil {
+		return context.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to import cache bundle: " + err.Error(),
+			Code:    500,
+		})
+	}
+	defer os.Remove(bundleFile.Name())
+	defer bundleFile.Close()
+
+	size, err := io.Copy(bundleFile, context.Request().Body)
+	if err != nil {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to read cache bundle: " + err.Error(),
+			Code:    400,
+		})
+	}
+
+	summary, err := cache.GetManager().ImportBundle(bundleFile, size, context.QueryParam("profile"))
+	if errors.Is(err, cache.ErrDisabled) {
+		return context.JSON(http.StatusConflict, responses.ErrorResponse{
+			Success: false,
+			Error:   "The audio cache is disabled",
+			Code:    409,
+		})
+	}
+	if err != nil {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to import cache bundle: " + err.Error(),
+			Code:    400,
+		})
+	}
+
+	return context.JSON(http.StatusOK, map[string]interface{}{
+		"success":  true,
+		"imported": summary,
+	})
+}
+
+// handleEvictCache applies the cache's maximum size and age right away instead of on the next cached line
+func handleEvictCache(context echo.Context) error {
+	removed, err := cache.GetManager().Evict()
+	if err != nil {
+		return context.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to evict cached audio: " + err.Error(),
+			Code:    500,
+		})
+	}
+
+	return context.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"removed": removed,
+	})
+}
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"net/http"
+	"nstudio/app/server/http/responses"
+	"nstudio/app/tts"
+
+	"github.com/labstack/echo/v4"
+)
+
+// trackGeneration ties the request's context to its X-Request-ID, so DELETE /generations/:requestId can stop the
+// generation. Clients may send their own X-Request-ID to know it before the response arrives. The returned
+// function must be called once the generation finished.
+func trackGeneration(context echo.Context) func() {
+	requestID := context.Response().Header().Get(echo.HeaderXRequestID)
+	ctx, done := tts.Track(context.Request().Context(), tts.HTTPPrefix+requestID)
+	context.SetRequest(context.Request().WithContext(ctx))
+	return done
+}
+
+// handleCancelGeneration stops the generation of an HTTP request that is still running. Socket requests, jobs and
+// warm-ups are tracked under IDs of their own and can't be cancelled here.
+func handleCancelGeneration(context echo.Context) error {
+	requestID := context.Param("requestId")
+
+	if !tts.Cancel(tts.HTTPPrefix + requestID) {
+		return context.JSON(http.StatusNotFound, responses.ErrorResponse{
+			Success: false,
+			Error:   "No generation is running for request " + requestID,
+			Code:    404,
+		})
+	}
+
+	return context.JSON(http.StatusOK, map[string]interface{}{
+		"success":   true,
+		"requestId": requestID,
+	})
+}
+
+// handleCancelAllGenerations stops every running generation, whoever requested it
+func handleCancelAllGenerations(context echo.Context) error {
+	return context.JSON(http.StatusOK, map[string]interface{}{
+		"success":   true,
+		"cancelled": tts.CancelAll(),
+	})
+}
@@ -7,6 +7,7 @@ import (
 	configRoute "nstudio/app/server/http/routes/config"
 	"nstudio/app/server/http/routes/engines"
 	"nstudio/app/server/http/routes/profiles"
+	"nstudio/app/server/jobs"
 	"os"
 	"os/signal"
 	"syscall"
@@ -32,12 +33,25 @@ func StartHTTPServer(config ServerConfig) error {
 	echoServer.Use(middleware.CORS())
 	echoServer.Use(middleware.RequestID())
 
-	echoServer.Use(middleware.BodyLimit("10M"))
+	echoServer.Use(middleware.BodyLimitWithConfig(middleware.BodyLimitConfig{
+		// Cache bundles hold a whole cache
+		Skipper: isBundleImport,
+		Limit:   "10M",
+	}))
 
 	echoServer.Use(middleware.TimeoutWithConfig(middleware.TimeoutConfig{
+		// Streamed responses are flushed as they are generated, which the timeout's buffered writer can't do.
+		// Cache bundles are written straight to the response too.
+		Skipper: func(context echo.Context) bool {
+			return isStreamRequest(context) || isBundleRequest(context)
+		},
 		Timeout: 60 * time.Second,
 	}))
 
+	if err := jobs.GetManager().Start(); err != nil {
+		return err
+	}
+
 	setupRoutes(echoServer)
 
 	address := fmt.Sprintf("%s:%d", config.Host, config.Port)
@@ -77,6 +91,10 @@ func setupRoutes(server *echo.Echo) {
 	api.POST("/tts", handleProfileTTSRequest)
 	api.POST("/tts/:engineId/:modelId/:voiceId", handleSimpleTTS)
 
+	// Script endpoints
+	api.POST("/script/parse", handleScriptParse)
+	api.POST("/script/import", handleScriptImport)
+
 	// Engine endpoints
 	api.GET("/engines", engines.GetEngines)
 	api.GET("/engines/:engineId/models", engines.GetModels)
@@ -96,6 +114,25 @@ func setupRoutes(server *echo.Echo) {
 	api.POST("/profiles/:profileId/voices/:character", profiles.SetCharacterVoice)
 	api.DELETE("/profiles/:profileId/voices/:character", profiles.DeleteCharacterVoice)
 
+	// Project endpoints
+	api.GET("/projects", handleListProjects)
+	api.POST("/projects", handleCreateProject)
+	api.GET("/projects/:projectId", handleGetProject)
+	api.PUT("/projects/:projectId", handleSaveProject)
+	api.POST("/projects/:projectId/render", handleRenderProject)
+	api.GET("/projects/:projectId/audio", handleGetProjectAudio)
+	api.PUT("/projects/:projectId/lines/:lineId/take", handleSelectProjectTake)
+
+	// Job endpoints
+	api.GET("/jobs", handleListJobs)
+	api.POST("/jobs", handleCreateJob)
+	api.GET("/jobs/:jobId", handleGetJob)
+	api.GET("/jobs/:jobId/result", handleGetJobResult)
+	api.DELETE("/jobs/:jobId", handleDeleteJob)
+
+	// Generation endpoints
+	api.DELETE("/generations/:requestId", handleCancelGeneration)
+
 	// Admin-only endpoints
 	admin := server.Group("")
 	admin.Use(customMiddleware.AdminAuthMiddleware)
@@ -106,4 +143,21 @@ func setupRoutes(server *echo.Echo) {
 	admin.PATCH("/config", configRoute.Patch)
 	admin.GET("/config/value", configRoute.GetValue)
 	admin.GET("/config/schema", configRoute.GetSchema)
+
+	admin.DELETE("/generations", handleCancelAllGenerations)
+
+	// Cache endpoints
+	cacheRoutes := admin.Group("/cache")
+	cacheRoutes.GET("/stats", handleCacheStats)
+	cacheRoutes.DELETE("", handleClearCache)
+	cacheRoutes.POST("/evict", handleEvictCache)
+	cacheRoutes.GET("/verify", handleVerifyCache)
+	cacheRoutes.POST("/repair", handleRepairCache)
+	cacheRoutes.GET("/bundle", handleExportCacheBundle)
+	cacheRoutes.POST("/bundle", handleImportCacheBundle)
+	cacheRoutes.GET("/warm", handleListWarmups)
+	cacheRoutes.POST("/warm", handleStartWarmup)
+	cacheRoutes.GET("/warm/:warmupId", handleGetWarmup)
+	cacheRoutes.GET("/warm/:warmupId/bundle", handleExportWarmupBundle)
+	cacheRoutes.DELETE("/warm/:warmupId", handleCancelWarmup)
 }
@@ -0,0 +1,202 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"nstudio/app/common/script"
+	"nstudio/app/server/http/responses"
+	"nstudio/app/server/jobs"
+	"os"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Jobs render whole scripts in the background: POST /jobs answers with the job right away, clients poll
+// GET /jobs/:jobId for progress and download GET /jobs/:jobId/result once it completed.
+
+const defaultJobPause = 1.0
+
+func handleListJobs(context echo.Context) error {
+	list := jobs.GetManager().List()
+
+	return context.JSON(http.StatusOK, map[string]interface{}{
+		"jobs":  list,
+		"count": len(list),
+	})
+}
+
+// handleCreateJob queues a script. Script errors answer 400 with every error and its line.
+func handleCreateJob(context echo.Context) error {
+	var request JobRequest
+
+	if err := context.Bind(&request); err != nil {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request format",
+			Code:    400,
+		})
+	}
+
+	if request.Profile == "" {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Profile field is required",
+			Code:    400,
+		})
+	}
+
+	if strings.TrimSpace(request.Script) == "" {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Script field is required",
+			Code:    400,
+		})
+	}
+
+	pause := defaultJobPause
+	if request.Pause != nil {
+		pause = *request.Pause
+	}
+
+	job, err := jobs.GetManager().Submit(request.Profile, request.Script, pause)
+	if err != nil {
+		var scriptErrors script.Errors
+		if errors.As(err, &scriptErrors) {
+			return context.JSON(http.StatusBadRequest, map[string]interface{}{
+				"success": false,
+				"error":   "Script has errors",
+				"code":    400,
+				"errors":  []script.Error(scriptErrors),
+			})
+		}
+
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to queue job: " + err.Error(),
+			Code:    400,
+		})
+	}
+
+	return context.JSON(http.StatusAccepted, map[string]interface{}{
+		"success": true,
+		"job":     job,
+	})
+}
+
+// handleGetJob returns the state of a job and of each of its lines
+func handleGetJob(context echo.Context) error {
+	job, ok := getJob(context)
+	if !ok {
+		return nil
+	}
+
+	return context.JSON(http.StatusOK, job)
+}
+
+// handleGetJobResult sends the combined file of a completed job, or a zip of its lines with ?type=zip
+func handleGetJobResult(context echo.Context) error {
+	job, ok := getJob(context)
+	if !ok {
+		return nil
+	}
+
+	if job.State != jobs.Completed {
+		return context.JSON(http.StatusConflict, responses.ErrorResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Job is %s, the result is available once it completed", job.State),
+			Code:    409,
+		})
+	}
+
+	manager := jobs.GetManager()
+
+	switch context.QueryParam("type") {
+	case "", "combined":
+		output := manager.ResultPath(job.ID)
+		if _, err := os.Stat(output); err != nil {
+			return context.JSON(http.StatusNotFound, responses.ErrorResponse{
+				Success: false,
+				Error:   "The job's combined file is missing",
+				Code:    404,
+			})
+		}
+		return context.Attachment(output, fmt.Sprintf("job_%s.wav", job.ID))
+
+	case "zip":
+		if job.Project != "" {
+			return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+				Success: false,
+				Error:   "A project job's lines are takes of the project, only its combined file is available",
+				Code:    400,
+			})
+		}
+
+		writer := context.Response()
+		writer.Header().Set(echo.HeaderContentType, "application/zip")
+		writer.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"job_%s.zip\"", job.ID))
+		writer.WriteHeader(http.StatusOK)
+		return manager.WriteZip(job.ID, writer)
+
+	default:
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Invalid type. Supported: combined, zip",
+			Code:    400,
+		})
+	}
+}
+
+// handleDeleteJob cancels a queued or running job. A job that already finished is deleted along with its audio.
+func handleDeleteJob(context echo.Context) error {
+	job, ok := getJob(context)
+	if !ok {
+		return nil
+	}
+
+	manager := jobs.GetManager()
+
+	if job.Active() {
+		cancelled, err := manager.Cancel(job.ID)
+		if err != nil {
+			return context.JSON(http.StatusConflict, responses.ErrorResponse{
+				Success: false,
+				Error:   "Failed to cancel job: " + err.Error(),
+				Code:    409,
+			})
+		}
+
+		return context.JSON(http.StatusOK, map[string]interface{}{
+			"success": true,
+			"job":     cancelled,
+		})
+	}
+
+	if err := manager.Remove(job.ID); err != nil {
+		return context.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to delete job: " + err.Error(),
+			Code:    500,
+		})
+	}
+
+	return context.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"deleted": job.ID,
+	})
+}
+
+// getJob returns the job named in the URL. When there is none, the error response has been sent and ok is false.
+func getJob(context echo.Context) (*jobs.Job, bool) {
+	job := jobs.GetManager().Get(context.Param("jobId"))
+	if job == nil {
+		context.JSON(http.StatusNotFound, responses.ErrorResponse{
+			Success: false,
+			Error:   "Job not found",
+			Code:    404,
+		})
+		return nil, false
+	}
+	return job, true
+}
@@ -8,25 +8,40 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func AuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(context echo.Context) error {
-		authSettings := config.GetSettings().Server.Auth
+// IsAuthorized reports whether token grants API access; every request is allowed until a key is configured
+func IsAuthorized(token string) bool {
+	authSettings := config.GetSettings().Server.Auth
 
-		if authSettings.Key == "" && authSettings.AdminKey == "" {
-			return next(context)
-		}
+	if authSettings.Key == "" && authSettings.AdminKey == "" {
+		return true
+	}
 
-		authHeader := context.Request().Header.Get("Authorization")
-		if strings.HasPrefix(authHeader, "Bearer ") {
-			token := strings.TrimPrefix(authHeader, "Bearer ")
+	return token != "" && (token == authSettings.Key || token == authSettings.AdminKey)
+}
 
-			if token == authSettings.Key || token == authSettings.AdminKey {
-				return next(context)
-			}
-		}
+// IsAdminAuthorized reports whether token grants access to admin endpoints
+func IsAdminAuthorized(token string) bool {
+	authSettings := config.GetSettings().Server.Auth
+
+	if authSettings.AdminKey == "" {
+		return true
+	}
+
+	return token == authSettings.AdminKey
+}
 
-		queryAuth := context.QueryParam("auth")
-		if queryAuth != "" && (queryAuth == authSettings.Key || queryAuth == authSettings.AdminKey) {
+// BearerToken extracts the token from an "Authorization: Bearer <token>" header value
+func BearerToken(header string) string {
+	if !strings.HasPrefix(header, "Bearer ") {
+		return ""
+	}
+	return strings.TrimPrefix(header, "Bearer ")
+}
+
+func AuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(context echo.Context) error {
+		token := BearerToken(context.Request().Header.Get("Authorization"))
+		if IsAuthorized(token) || IsAuthorized(context.QueryParam("auth")) {
 			return next(context)
 		}
 
@@ -39,26 +54,8 @@ func AuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 
 func AdminAuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(context echo.Context) error {
-		authSettings := config.GetSettings().Server.Auth
-
-		if authSettings.AdminKey == "" {
-			return next(context)
-			//return context.JSON(http.StatusForbidden, map[string]string{
-			//	"error":   "Forbidden",
-			//	"message": "Admin key not configured",
-			//})
-		}
-
-		authHeader := context.Request().Header.Get("Authorization")
-		if strings.HasPrefix(authHeader, "Bearer ") {
-			token := strings.TrimPrefix(authHeader, "Bearer ")
-			if token == authSettings.AdminKey {
-				return next(context)
-			}
-		}
-
-		queryAuth := context.QueryParam("auth")
-		if queryAuth == authSettings.AdminKey {
+		token := BearerToken(context.Request().Header.Get("Authorization"))
+		if IsAdminAuthorized(token) || IsAdminAuthorized(context.QueryParam("auth")) {
 			return next(context)
 		}
 
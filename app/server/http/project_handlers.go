@@ -0,0 +1,295 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"nstudio/app/common/script"
+	"nstudio/app/project"
+	"nstudio/app/server/http/responses"
+	"nstudio/app/server/jobs"
+	"os"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Projects live in project.Root(), clients only name them by ID
+
+func handleListProjects(context echo.Context) error {
+	root, err := project.Root()
+	if err != nil {
+		return context.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to find the projects directory: " + err.Error(),
+			Code:    500,
+		})
+	}
+
+	ids, err := project.List(root)
+	if err != nil {
+		return context.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to list projects: " + err.Error(),
+			Code:    500,
+		})
+	}
+
+	return context.JSON(http.StatusOK, map[string]interface{}{
+		"projects": ids,
+		"count":    len(ids),
+	})
+}
+
+func handleCreateProject(context echo.Context) error {
+	var request ProjectCreateRequest
+
+	if err := context.Bind(&request); err != nil {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request format",
+			Code:    400,
+		})
+	}
+
+	path, ok := projectPath(context, request.ID)
+	if !ok {
+		return nil
+	}
+
+	created, err := project.Create(path, request.Name, request.Profile)
+	if err != nil {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to create project: " + err.Error(),
+			Code:    400,
+		})
+	}
+
+	if request.Script != "" {
+		created.Script = request.Script
+		if err := created.Save(); err != nil {
+			return context.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+				Success: false,
+				Error:   "Failed to save project: " + err.Error(),
+				Code:    500,
+			})
+		}
+	}
+
+	return context.JSON(http.StatusCreated, map[string]interface{}{
+		"success": true,
+		"project": created,
+	})
+}
+
+func handleGetProject(context echo.Context) error {
+	opened, ok := openProject(context)
+	if !ok {
+		return nil
+	}
+
+	return context.JSON(http.StatusOK, opened)
+}
+
+// handleSaveProject replaces the fields given in the request: script, profile and render settings
+func handleSaveProject(context echo.Context) error {
+	var request ProjectSaveRequest
+
+	if err := context.Bind(&request); err != nil {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request format",
+			Code:    400,
+		})
+	}
+
+	opened, ok := openProject(context)
+	if !ok {
+		return nil
+	}
+
+	if request.Script != nil {
+		opened.Script = *request.Script
+	}
+	if request.Profile != "" {
+		opened.Manifest.Profile = request.Profile
+	}
+	if request.Render != nil {
+		opened.Manifest.Render = *request.Render
+	}
+
+	if err := opened.Save(); err != nil {
+		return context.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to save project: " + err.Error(),
+			Code:    500,
+		})
+	}
+
+	return context.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"project": opened,
+	})
+}
+
+// handleRenderProject queues a job that generates the changed lines and rebuilds the combined file, so long renders
+// don't run into the request timeout: it answers 202 with the job, clients poll GET /jobs/:jobId for progress and
+// the render's result. Dry runs only plan the render and answer right away. Script errors answer 400 with every
+// error and its line.
+func handleRenderProject(context echo.Context) error {
+	var request project.RenderOptions
+
+	if err := context.Bind(&request); err != nil {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request format",
+			Code:    400,
+		})
+	}
+
+	opened, ok := openProject(context)
+	if !ok {
+		return nil
+	}
+
+	if !request.DryRun {
+		job, err := jobs.GetManager().SubmitProject(opened.Path, request)
+		if err != nil {
+			var scriptErrors script.Errors
+			if errors.As(err, &scriptErrors) {
+				return context.JSON(http.StatusBadRequest, map[string]interface{}{
+					"success": false,
+					"error":   "Script has errors",
+					"code":    400,
+					"errors":  []script.Error(scriptErrors),
+				})
+			}
+
+			return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+				Success: false,
+				Error:   "Failed to queue render: " + err.Error(),
+				Code:    400,
+			})
+		}
+
+		return context.JSON(http.StatusAccepted, map[string]interface{}{
+			"success": true,
+			"job":     job,
+		})
+	}
+
+	result, err := opened.Render(context.Request().Context(), request)
+	if err != nil {
+		var scriptErrors script.Errors
+		if errors.As(err, &scriptErrors) {
+			return context.JSON(http.StatusBadRequest, map[string]interface{}{
+				"success": false,
+				"error":   "Script has errors",
+				"code":    400,
+				"errors":  []script.Error(scriptErrors),
+			})
+		}
+
+		return context.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to render project: " + err.Error(),
+			Code:    500,
+		})
+	}
+
+	return context.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"result":  result,
+	})
+}
+
+// handleGetProjectAudio sends the combined file of the last render
+func handleGetProjectAudio(context echo.Context) error {
+	opened, ok := openProject(context)
+	if !ok {
+		return nil
+	}
+
+	output := opened.OutputPath()
+	if _, err := os.Stat(output); err != nil {
+		return context.JSON(http.StatusNotFound, responses.ErrorResponse{
+			Success: false,
+			Error:   "The project has not been rendered yet",
+			Code:    404,
+		})
+	}
+
+	return context.File(output)
+}
+
+func handleSelectProjectTake(context echo.Context) error {
+	var request ProjectTakeRequest
+
+	if err := context.Bind(&request); err != nil {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request format",
+			Code:    400,
+		})
+	}
+
+	opened, ok := openProject(context)
+	if !ok {
+		return nil
+	}
+
+	if err := opened.SelectTake(context.Param("lineId"), request.Take); err != nil {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to select take: " + err.Error(),
+			Code:    400,
+		})
+	}
+
+	return context.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"project": opened,
+	})
+}
+
+// projectPath resolves a project ID. When it can't, the error response has been sent and ok is false.
+func projectPath(context echo.Context, id string) (string, bool) {
+	root, err := project.Root()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to find the projects directory: " + err.Error(),
+			Code:    500,
+		})
+		return "", false
+	}
+
+	path, err := project.Resolve(root, id)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+			Code:    400,
+		})
+		return "", false
+	}
+	return path, true
+}
+
+// openProject opens the project named in the URL. When it can't, the error response has been sent and ok is false.
+func openProject(context echo.Context) (*project.Project, bool) {
+	path, ok := projectPath(context, context.Param("projectId"))
+	if !ok {
+		return nil, false
+	}
+
+	opened, err := project.Open(path)
+	if err != nil {
+		context.JSON(http.StatusNotFound, responses.ErrorResponse{
+			Success: false,
+			Error:   "Project not found: " + err.Error(),
+			Code:    404,
+		})
+		return nil, false
+	}
+	return opened, true
+}
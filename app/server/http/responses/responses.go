@@ -1,5 +1,10 @@
 package responses
 
+import (
+	"nstudio/app/common/script"
+	"nstudio/app/common/util"
+)
+
 type TTSRequest struct {
 	Text    string                 `json:"text" validate:"required,min=1,max=10000"`
 	Options map[string]interface{} `json:"options,omitempty"`
@@ -19,6 +24,23 @@ type ErrorResponse struct {
 	Code    int    `json:"code"`
 }
 
+type ScriptParseResponse struct {
+	Success bool           `json:"success"`
+	Error   string         `json:"error,omitempty"`
+	Code    int            `json:"code,omitempty"`
+	Entries int            `json:"entries"`
+	Script  *script.Script `json:"script"`
+	Errors  []script.Error `json:"errors"`
+}
+
+type ScriptImportResponse struct {
+	Success  bool                    `json:"success"`
+	Error    string                  `json:"error,omitempty"`
+	Code     int                     `json:"code,omitempty"`
+	Messages []util.CharacterMessage `json:"messages"`
+	Errors   []script.Error          `json:"errors"`
+}
+
 type HealthResponse struct {
 	Status            string             `json:"status"`
 	Version           string             `json:"version"`
@@ -95,7 +95,10 @@ func handleInfo(context echo.Context) error {
 			"health":              "/health",
 			"info":                "/info",
 			"profile-tts":         "/tts",
+			"profile-tts-stream":  "/tts?stream=true",
 			"simple-tts":          "/tts/:engineId/:modelId/:voiceId",
+			"script-parse":        "/script/parse",
+			"script-import":       "/script/import",
 			"engines":             "/engines",
 			"engine-models":       "/engines/:engineId/models",
 			"engine-model-voices": "/engines/:engineId/models/:modelId/voices",
@@ -107,6 +110,39 @@ func handleInfo(context echo.Context) error {
 				"delete": "/profiles/:profileId",
 				"voices": "/profiles/:profileId/voices",
 			},
+			"projects": map[string]string{
+				"list":   "/projects",
+				"get":    "/projects/:projectId",
+				"create": "/projects",
+				"save":   "/projects/:projectId",
+				"render": "/projects/:projectId/render",
+				"audio":  "/projects/:projectId/audio",
+				"take":   "/projects/:projectId/lines/:lineId/take",
+			},
+			"jobs": map[string]string{
+				"list":   "/jobs",
+				"create": "/jobs",
+				"get":    "/jobs/:jobId",
+				"result": "/jobs/:jobId/result",
+				"delete": "/jobs/:jobId",
+			},
+			"generations": map[string]string{
+				"cancel":     "/generations/:requestId",
+				"cancel-all": "/generations",
+			},
+			"cache": map[string]string{
+				"stats":       "/cache/stats",
+				"clear":       "/cache",
+				"evict":       "/cache/evict",
+				"verify":      "/cache/verify",
+				"repair":      "/cache/repair",
+				"export":      "/cache/bundle",
+				"import":      "/cache/bundle",
+				"warm":        "/cache/warm",
+				"warm-status": "/cache/warm/:warmupId",
+				"warm-cancel": "/cache/warm/:warmupId",
+				"warm-bundle": "/cache/warm/:warmupId/bundle",
+			},
 		},
 	})
 }
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"nstudio/app/common/script"
+	"nstudio/app/common/util"
+	"nstudio/app/server/http/responses"
+
+	"github.com/labstack/echo/v4"
+)
+
+// handleScriptParse parses a script without generating anything, so clients can check it and show the
+// structure. Parse errors answer 400 with every error and its line, next to whatever could still be parsed.
+func handleScriptParse(context echo.Context) error {
+	var request ScriptParseRequest
+
+	if err := context.Bind(&request); err != nil {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request format",
+			Code:    400,
+		})
+	}
+
+	parsed, err := script.Parse(request.Script)
+
+	var scriptErrors script.Errors
+	if err != nil && !errors.As(err, &scriptErrors) {
+		return context.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to parse script: " + err.Error(),
+			Code:    500,
+		})
+	}
+
+	result := responses.ScriptParseResponse{
+		Success: len(scriptErrors) == 0,
+		Entries: len(parsed.Entries()),
+		Script:  parsed,
+		Errors:  []script.Error(scriptErrors),
+	}
+	if result.Errors == nil {
+		result.Errors = []script.Error{}
+	}
+
+	if !result.Success {
+		result.Error = "Script has errors"
+		result.Code = 400
+		return context.JSON(http.StatusBadRequest, result)
+	}
+
+	return context.JSON(http.StatusOK, result)
+}
+
+// handleScriptImport converts a Fountain, SRT, WebVTT or CSV file into messages for /tts, keeping each line's cue
+// ID and timing. Lines that can't be read answer 400 with their line numbers.
+func handleScriptImport(context echo.Context) error {
+	var request ScriptImportRequest
+
+	if err := context.Bind(&request); err != nil {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request format",
+			Code:    400,
+		})
+	}
+
+	if request.Format == "" || request.Content == "" {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Format and content are required",
+			Code:    400,
+		})
+	}
+
+	messages, err := script.Import(request.Format, []byte(request.Content), script.ImportOptions{
+		DefaultCharacter: request.DefaultCharacter,
+		IncludeAction:    request.IncludeAction,
+	})
+	if err != nil {
+		result := responses.ScriptImportResponse{
+			Success:  false,
+			Error:    "Failed to import script: " + err.Error(),
+			Code:     400,
+			Messages: []util.CharacterMessage{},
+			Errors:   []script.Error{},
+		}
+
+		var scriptErrors script.Errors
+		if errors.As(err, &scriptErrors) {
+			result.Error = "Script has errors"
+			result.Errors = scriptErrors
+		}
+		return context.JSON(http.StatusBadRequest, result)
+	}
+
+	return context.JSON(http.StatusOK, responses.ScriptImportResponse{
+		Success:  true,
+		Messages: messages,
+		Errors:   []script.Error{},
+	})
+}
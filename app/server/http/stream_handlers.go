@@ -0,0 +1,116 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"nstudio/app/common/audio"
+	"nstudio/app/server/http/responses"
+	"nstudio/app/server/synthesis"
+	"slices"
+	"strconv"
+
+	"github.com/charmbracelet/log"
+	"github.com/labstack/echo/v4"
+)
+
+// POST /tts?stream=true answers with Transfer-Encoding: chunked and writes audio as it is generated, so playback
+// can start before the whole passage is done. Only formats that can be written incrementally are allowed: raw
+// PCM, or WAV with a header of unknown length. Streamed requests skip the 60 second request timeout and stop
+// generating when the client disconnects.
+//
+// Once the first chunk is sent the status code can no longer change, so the outcome is reported in the
+// X-Stream-Status ("complete" or "error") and X-Stream-Error trailers.
+
+var streamFormats = []string{"pcm", "pcm_s16le", "wav"}
+
+func isStreamRequest(context echo.Context) bool {
+	stream, err := strconv.ParseBool(context.QueryParam("stream"))
+	return err == nil && stream
+}
+
+func streamProfileTTS(context echo.Context, request ProfileTTSRequest, audioOpts *AudioOptions) error {
+	outputFormat := audioOpts.OutputFormat()
+	if !slices.Contains(streamFormats, outputFormat) {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Invalid format for streaming. Supported: pcm, pcm_s16le, wav",
+			Code:    400,
+		})
+	}
+
+	// Every chunk is encoded as raw PCM, a WAV header goes in front of the first one
+	chunkOptions := &AudioOptions{Format: "pcm", Encoding: audio.DefaultEncodeOptions()}
+	if audioOpts != nil {
+		copied := *audioOpts
+		copied.Format = "pcm"
+		chunkOptions = &copied
+	}
+
+	synthesisRequest := synthesis.Request{
+		Profile:   request.Profile,
+		Character: request.Character,
+		Text:      request.Text,
+		Audio:     chunkOptions,
+	}
+
+	writer := context.Response()
+	started := false
+
+	err := synthesis.Stream(context.Request().Context(), synthesisRequest, func(chunk synthesis.Chunk) error {
+		if !started {
+			if len(chunk.Data) == 0 {
+				return nil
+			}
+			metadata := chunk.Metadata
+
+			fileExtension := outputFormat
+			if outputFormat == "pcm_s16le" {
+				fileExtension = "pcm"
+			}
+			filename := fmt.Sprintf("tts_%s_%s.%s", request.Profile, request.Character, fileExtension)
+
+			header := writer.Header()
+			header.Set(echo.HeaderContentType, audio.GetContentType(outputFormat))
+			header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+			header.Set("X-Sample-Rate", strconv.Itoa(metadata.SampleRate))
+			header.Set("X-Channels", strconv.Itoa(metadata.Channels))
+			header.Set("X-Bit-Depth", strconv.Itoa(metadata.BitDepth))
+			header.Set("Trailer", "X-Stream-Status, X-Stream-Error")
+			writer.WriteHeader(http.StatusOK)
+			started = true
+
+			if outputFormat == "wav" {
+				if _, err := writer.Write(audio.StreamingWAVHeader(metadata.SampleRate, metadata.Channels, metadata.BitDepth)); err != nil {
+					return err
+				}
+			}
+		}
+
+		if _, err := writer.Write(chunk.Data); err != nil {
+			return err
+		}
+		writer.Flush()
+		return nil
+	})
+
+	if !started {
+		if err == nil {
+			err = fmt.Errorf("no audio was generated")
+		}
+		return context.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to generate speech: " + err.Error(),
+			Code:    500,
+		})
+	}
+
+	if err != nil {
+		log.Warn("streamed tts response ended early", "profile", request.Profile, "character", request.Character, "error", err)
+		writer.Header().Set("X-Stream-Status", "error")
+		writer.Header().Set("X-Stream-Error", err.Error())
+		return nil
+	}
+
+	writer.Header().Set("X-Stream-Status", "complete")
+	return nil
+}
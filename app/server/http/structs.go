@@ -1,6 +1,9 @@
 package http
 
-import "fmt"
+import (
+	"nstudio/app/project"
+	"nstudio/app/server/synthesis"
+)
 
 type ProfileTTSRequest struct {
 	Profile   string                 `json:"profile" validate:"required"`
@@ -10,53 +13,49 @@ type ProfileTTSRequest struct {
 }
 
 func (r *ProfileTTSRequest) GetAudioOptions() (*AudioOptions, error) {
-	if r.Options == nil {
-		return nil, nil
-	}
+	return synthesis.ParseAudioOptions(r.Options)
+}
+
+type AudioOptions = synthesis.AudioOptions
 
-	audioOpts, exists := r.Options["audio"]
-	if !exists {
-		return nil, nil
-	}
+type SimpleTTSRequest struct {
+	Text    string                 `json:"text" validate:"required,min=1,max=10000"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
 
-	audioMap, ok := audioOpts.(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid audio options format")
-	}
+type ScriptParseRequest struct {
+	Script string `json:"script"`
+}
 
-	opts := &AudioOptions{
-		Format:     "wav", // default
-		SampleRate: 0,     // 0 means use engine default
-		Channels:   0,     // 0 means use engine default
-		BitDepth:   0,     // 0 means use engine default
-	}
+type ScriptImportRequest struct {
+	Format           string `json:"format"` // file name or extension: fountain, srt, vtt or csv
+	Content          string `json:"content"`
+	DefaultCharacter string `json:"default_character,omitempty"`
+	IncludeAction    bool   `json:"include_action,omitempty"`
+}
 
-	if format, ok := audioMap["format"].(string); ok {
-		opts.Format = format
-	}
-	if sampleRate, ok := audioMap["sample_rate"].(float64); ok {
-		opts.SampleRate = int(sampleRate)
-	}
-	if channels, ok := audioMap["channels"].(float64); ok {
-		opts.Channels = int(channels)
-	}
-	if bitDepth, ok := audioMap["bit_depth"].(float64); ok {
-		opts.BitDepth = int(bitDepth)
-	}
+type ProjectCreateRequest struct {
+	ID      string `json:"id" validate:"required"`
+	Name    string `json:"name"`
+	Profile string `json:"profile"`
+	Script  string `json:"script,omitempty"`
+}
 
-	return opts, nil
+// ProjectSaveRequest only changes the fields it has
+type ProjectSaveRequest struct {
+	Script  *string                 `json:"script,omitempty"`
+	Profile string                  `json:"profile,omitempty"`
+	Render  *project.RenderSettings `json:"render,omitempty"`
 }
 
-type AudioOptions struct {
-	Format     string `json:"format"`      // "pcm_s16le", "wav", "flac", etc.
-	SampleRate int    `json:"sample_rate"` // 22050, 24000, 44100, etc.
-	Channels   int    `json:"channels"`
-	BitDepth   int    `json:"bit_depth"` // 16, 24, 32
+type ProjectTakeRequest struct {
+	Take int `json:"take"`
 }
 
-type SimpleTTSRequest struct {
-	Text    string                 `json:"text" validate:"required,min=1,max=10000"`
-	Options map[string]interface{} `json:"options,omitempty"`
+type JobRequest struct {
+	Profile string   `json:"profile"`
+	Script  string   `json:"script"`
+	Pause   *float64 `json:"pause,omitempty"` // seconds between lines in the combined file, 1 when not set
 }
 
 type ProfileCreateRequest struct {
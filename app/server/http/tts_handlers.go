@@ -3,12 +3,11 @@ package http
 import (
 	"fmt"
 	"net/http"
-	"nstudio/app/common/response"
+	"nstudio/app/common/ssml"
 	"nstudio/app/server/http/responses"
 	"nstudio/app/server/stats"
 	"strings"
 
-	"nstudio/app/cache"
 	"nstudio/app/common/audio"
 	"nstudio/app/common/util"
 	"nstudio/app/tts"
@@ -18,6 +17,15 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+// validateSSML rejects malformed SSML up front, so it answers 400 instead of failing during generation
+func validateSSML(text string) error {
+	if !ssml.IsSSML(text) {
+		return nil
+	}
+	_, err := ssml.Parse(text)
+	return err
+}
+
 func handleProfileTTSRequest(context echo.Context) error {
 	var request ProfileTTSRequest
 
@@ -61,6 +69,14 @@ func handleProfileTTSRequest(context echo.Context) error {
 		})
 	}
 
+	if err := validateSSML(request.Text); err != nil {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+			Code:    400,
+		})
+	}
+
 	audioOpts, err := request.GetAudioOptions()
 	if err != nil {
 		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
@@ -91,6 +107,12 @@ func handleProfileTTSRequest(context echo.Context) error {
 		})
 	}
 
+	defer trackGeneration(context)()
+
+	if isStreamRequest(context) {
+		return streamProfileTTS(context, request, audioOpts)
+	}
+
 	log.Info("about to get manager")
 
 	manager := profile.GetManager()
@@ -103,44 +125,13 @@ func handleProfileTTSRequest(context echo.Context) error {
 		})
 	}
 
-	voiceKey := fmt.Sprintf("%s:%s:%s", voice.Engine, voice.Model, voice.Voice)
-
-	cacheEnabled := false
-	cacheManager := cache.GetManager()
-	if cacheManager.IsEnabled() {
-		selectedProfile, err := manager.GetProfile(request.Profile)
-		if err == nil {
-			settings := selectedProfile.GetSettings()
-			if settings != nil && settings.CacheEnabled != nil {
-				cacheEnabled = *settings.CacheEnabled
-			}
-		}
-	}
-
-	var audioObject *audio.Audio
-	if cacheEnabled {
-		cachedAudio, found := cacheManager.GetCachedAudio(request.Profile, request.Character, request.Text)
-		if found {
-			audioObject = audio.NewAudioFromPCM(cachedAudio, 22050, 1, 16)
-		}
-	}
-
-	if audioObject == nil {
-		audioObject, err = tts.GenerateAudio(voice, request.Text)
-		if err != nil {
-			return context.JSON(http.StatusInternalServerError, responses.ErrorResponse{
-				Success: false,
-				Error:   "Failed to generate speech: " + err.Error(),
-				Code:    500,
-			})
-		}
-
-		if cacheEnabled {
-			pcmData, _ := audioObject.ToPCM()
-			if err := cacheManager.CacheAudio(request.Profile, request.Character, request.Text, voiceKey, pcmData); err != nil {
-				response.Warn("failed to cache audio: %v", err)
-			}
-		}
+	audioObject, _, err := tts.GenerateCachedAudio(context.Request().Context(), request.Profile, request.Character, voice, request.Text)
+	if err != nil {
+		return context.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to generate speech: " + err.Error(),
+			Code:    500,
+		})
 	}
 
 	stats.IncrementMessages()
@@ -167,7 +158,12 @@ func handleProfileTTSRequest(context echo.Context) error {
 		}
 	}
 
-	audioData, err := audioObject.ToFormat(outputFormat)
+	encodeOptions := audio.DefaultEncodeOptions()
+	if audioOpts != nil {
+		encodeOptions = audioOpts.Encoding
+	}
+
+	audioData, err := audioObject.ToFormatWithOptions(outputFormat, encodeOptions)
 	if err != nil {
 		return context.JSON(http.StatusInternalServerError, responses.ErrorResponse{
 			Success: false,
@@ -246,6 +242,14 @@ func handleSimpleTTS(context echo.Context) error {
 		})
 	}
 
+	if err := validateSSML(request.Text); err != nil {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+			Code:    400,
+		})
+	}
+
 	format := "wav"
 	if request.Options != nil {
 		if formatOption, exists := request.Options["format"]; exists {
@@ -255,7 +259,7 @@ func handleSimpleTTS(context echo.Context) error {
 		}
 	}
 
-	validFormats := []string{"wav", "flac", "ogg"}
+	validFormats := []string{"wav", "flac", "ogg", "mp3"}
 	formatValid := false
 	for _, validFormat := range validFormats {
 		if format == validFormat {
@@ -266,7 +270,16 @@ func handleSimpleTTS(context echo.Context) error {
 	if !formatValid {
 		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
 			Success: false,
-			Error:   "Invalid format. Supported formats: wav, flac, ogg",
+			Error:   "Invalid format. Supported formats: wav, flac, ogg, mp3",
+			Code:    400,
+		})
+	}
+
+	encodeOptions, err := audio.ParseEncodeOptions(request.Options)
+	if err != nil {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Invalid audio options: " + err.Error(),
 			Code:    400,
 		})
 	}
@@ -277,7 +290,9 @@ func handleSimpleTTS(context echo.Context) error {
 		Voice:  voiceId,
 	}
 
-	audioObj, err := tts.GenerateAudio(voice, request.Text)
+	defer trackGeneration(context)()
+
+	audioObj, err := tts.GenerateAudio(context.Request().Context(), voice, request.Text)
 	if err != nil {
 		return context.JSON(http.StatusInternalServerError, responses.ErrorResponse{
 			Success: false,
@@ -288,7 +303,7 @@ func handleSimpleTTS(context echo.Context) error {
 
 	stats.IncrementMessages()
 
-	audioData, err := audioObj.ToFormat(format)
+	audioData, err := audioObj.ToFormatWithOptions(format, encodeOptions)
 	if err != nil {
 		return context.JSON(http.StatusInternalServerError, responses.ErrorResponse{
 			Success: false,
@@ -0,0 +1,135 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"nstudio/app/cache"
+	"nstudio/app/cache/warmup"
+	"nstudio/app/common/script"
+	"nstudio/app/server/http/responses"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Warm-ups fill the cache with known lines in the background: POST /cache/warm answers with the warm-up right away,
+// clients poll GET /cache/warm/:warmupId for progress and can download what it warmed from
+// GET /cache/warm/:warmupId/bundle once it finished.
+
+func handleListWarmups(context echo.Context) error {
+	list := warmup.GetManager().List()
+
+	return context.JSON(http.StatusOK, map[string]interface{}{
+		"warmups": list,
+		"count":   len(list),
+	})
+}
+
+// handleStartWarmup starts warming a script or a list of character and text pairs. Script errors answer 400 with
+// every error and its line.
+func handleStartWarmup(context echo.Context) error {
+	var request warmup.Request
+
+	if err := context.Bind(&request); err != nil {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request format",
+			Code:    400,
+		})
+	}
+
+	if request.Profile == "" {
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Profile field is required",
+			Code:    400,
+		})
+	}
+
+	started, err := warmup.GetManager().Start(request, nil)
+	if err != nil {
+		var scriptErrors script.Errors
+		switch {
+		case errors.As(err, &scriptErrors):
+			return context.JSON(http.StatusBadRequest, map[string]interface{}{
+				"success": false,
+				"error":   "Script has errors",
+				"code":    400,
+				"errors":  []script.Error(scriptErrors),
+			})
+		case errors.Is(err, cache.ErrDisabled):
+			return context.JSON(http.StatusConflict, responses.ErrorResponse{
+				Success: false,
+				Error:   "The profile doesn't use the audio cache",
+				Code:    409,
+			})
+		}
+
+		return context.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Success: false,
+			Error:   "Failed to start warm-up: " + err.Error(),
+			Code:    400,
+		})
+	}
+
+	return context.JSON(http.StatusAccepted, map[string]interface{}{
+		"success": true,
+		"warmup":  started,
+	})
+}
+
+// handleGetWarmup returns the progress of a warm-up
+func handleGetWarmup(context echo.Context) error {
+	warmed, ok := getWarmup(context)
+	if !ok {
+		return nil
+	}
+
+	return context.JSON(http.StatusOK, warmed)
+}
+
+// handleCancelWarmup stops a running warm-up. The lines it already warmed stay in the cache.
+func handleCancelWarmup(context echo.Context) error {
+	warmed, ok := getWarmup(context)
+	if !ok {
+		return nil
+	}
+
+	return context.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"warmup":  warmup.GetManager().Cancel(warmed.ID),
+	})
+}
+
+// handleExportWarmupBundle sends the lines of a finished warm-up as a bundle another install can import
+func handleExportWarmupBundle(context echo.Context) error {
+	warmed, ok := getWarmup(context)
+	if !ok {
+		return nil
+	}
+
+	if warmed.Active() {
+		return context.JSON(http.StatusConflict, responses.ErrorResponse{
+			Success: false,
+			Error:   "The warm-up is still running, its bundle is available once it finished",
+			Code:    409,
+		})
+	}
+
+	return writeBundle(context, "warmup_"+warmed.ID, func(writer io.Writer) (*cache.BundleSummary, error) {
+		return warmup.GetManager().ExportBundle(warmed.ID, writer)
+	})
+}
+
+func getWarmup(context echo.Context) (*warmup.Warmup, bool) {
+	warmed := warmup.GetManager().Get(context.Param("warmupId"))
+	if warmed == nil {
+		context.JSON(http.StatusNotFound, responses.ErrorResponse{
+			Success: false,
+			Error:   "Warm-up not found",
+			Code:    404,
+		})
+		return nil, false
+	}
+	return warmed, true
+}
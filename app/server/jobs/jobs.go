@@ -0,0 +1,447 @@
+// Package jobs renders whole scripts in the background for the HTTP server, so long scripts don't run into the
+// request timeout.
+//
+// Every job is a directory inside Root():
+//
+//	job.json      the record: script, profile, state and the state of every line
+//	lines/        one WAV per line, numbered in script order
+//	combined.wav  every line joined, written once all of them are done
+//
+// Project jobs render a project instead: the record names the project and its render options, the takes and the
+// combined file are written inside the project like any other render, and the record keeps the result.
+//
+// Jobs run one at a time, in the order they were submitted. The record is saved after every line, so the jobs
+// that were queued or running when the server stopped continue where they left off when it starts again.
+package jobs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"nstudio/app/common/response"
+	"nstudio/app/common/script"
+	"nstudio/app/common/util"
+	"nstudio/app/config"
+	"nstudio/app/project"
+	"nstudio/app/server/stats"
+	"nstudio/app/tts"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	recordFilename   = "job.json"
+	linesDirectory   = "lines"
+	combinedFilename = "combined.wav"
+)
+
+type State string
+
+const (
+	Queued    State = "queued"
+	Running   State = "running"
+	Completed State = "completed"
+	Failed    State = "failed"
+	Cancelled State = "cancelled"
+)
+
+type Job struct {
+	ID      string  `json:"id"`
+	Profile string  `json:"profile"`
+	Script  string  `json:"script,omitempty"`
+	Pause   float64 `json:"pause"` // seconds between lines in the combined file
+
+	// Project is the directory of the project a project job renders, with Render its options and Result what
+	// the render did
+	Project string                 `json:"project,omitempty"`
+	Render  *project.RenderOptions `json:"render,omitempty"`
+	Result  *project.RenderResult  `json:"result,omitempty"`
+
+	State    State      `json:"state"`
+	Error    string     `json:"error,omitempty"`
+	Created  time.Time  `json:"created"`
+	Started  *time.Time `json:"started,omitempty"`
+	Finished *time.Time `json:"finished,omitempty"`
+	Done     int        `json:"done"`
+	Total    int        `json:"total"`
+	Lines    []Line     `json:"lines,omitempty"`
+}
+
+// Line is an entry of the job's script. Its state is Queued until it is generated, then Completed or Failed.
+type Line struct {
+	Line      int    `json:"line"`
+	Character string `json:"character"`
+	Text      string `json:"text"`
+	State     State  `json:"state"`
+	File      string `json:"file,omitempty"` // relative to the job directory
+	Error     string `json:"error,omitempty"`
+}
+
+// Active reports whether the job is still queued or running
+func (job *Job) Active() bool {
+	return job.State == Queued || job.State == Running
+}
+
+func (job *Job) copy() *Job {
+	copied := *job
+	copied.Lines = slices.Clone(job.Lines)
+	return &copied
+}
+
+type Manager struct {
+	mutex sync.Mutex
+	root  string
+	jobs  map[string]*Job
+	queue []string
+	wake  chan struct{}
+}
+
+var (
+	manager     *Manager
+	managerOnce sync.Once
+)
+
+func GetManager() *Manager {
+	managerOnce.Do(func() {
+		manager = &Manager{
+			jobs: map[string]*Job{},
+			wake: make(chan struct{}, 1),
+		}
+	})
+	return manager
+}
+
+// Root is the directory jobs are kept in: "jobs" next to the output directory
+func Root() (string, error) {
+	err, outputPath := util.ExpandPath(config.GetSettings().OutputPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(outputPath), "jobs"), nil
+}
+
+// Start loads the jobs kept in Root() and starts running the ones that didn't finish
+func (manager *Manager) Start() error {
+	root, err := Root()
+	if err != nil {
+		return response.Err(err)
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return response.Err(err)
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	manager.mutex.Lock()
+	manager.root = root
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		job, err := readRecord(filepath.Join(root, entry.Name()))
+		if err != nil {
+			response.Warn("Skipping job: %v", fmt.Errorf("%s: %w", entry.Name(), err))
+			continue
+		}
+
+		// A job that was running when the server stopped starts over from its first unfinished line
+		if job.State == Running {
+			job.State = Queued
+		}
+		manager.jobs[job.ID] = job
+		if job.State == Queued {
+			manager.queue = append(manager.queue, job.ID)
+		}
+	}
+	slices.SortFunc(manager.queue, func(first, second string) int {
+		return manager.jobs[first].Created.Compare(manager.jobs[second].Created)
+	})
+	manager.report()
+	manager.mutex.Unlock()
+
+	go manager.work()
+	manager.signal()
+	return nil
+}
+
+// Submit queues a script. Scripts with errors are refused with a script.Errors.
+func (manager *Manager) Submit(profileID, text string, pause float64) (*Job, error) {
+	parsed, err := script.Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	entries := parsed.Entries()
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("the script has no lines to render")
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, response.Err(err)
+	}
+
+	job := &Job{
+		ID:      id,
+		Profile: profileID,
+		Script:  text,
+		Pause:   max(pause, 0),
+		State:   Queued,
+		Created: time.Now(),
+		Total:   len(entries),
+		Lines:   make([]Line, len(entries)),
+	}
+	for index, entry := range entries {
+		job.Lines[index] = Line{
+			Line:      entry.Line,
+			Character: entry.Character,
+			Text:      entry.Text,
+			State:     Queued,
+		}
+	}
+
+	return manager.queueJob(job)
+}
+
+// queueJob saves a new job and puts it at the end of the queue
+func (manager *Manager) queueJob(job *Job) (*Job, error) {
+	manager.mutex.Lock()
+	if manager.root == "" {
+		manager.mutex.Unlock()
+		return nil, fmt.Errorf("the job queue isn't running")
+	}
+	if err := manager.save(job); err != nil {
+		manager.mutex.Unlock()
+		return nil, err
+	}
+	manager.jobs[job.ID] = job
+	manager.queue = append(manager.queue, job.ID)
+	manager.report()
+	copied := job.copy()
+	manager.mutex.Unlock()
+
+	manager.signal()
+	return copied, nil
+}
+
+// SubmitProject queues the render of the project in path. Projects whose script has errors are refused with a
+// script.Errors.
+func (manager *Manager) SubmitProject(path string, options project.RenderOptions) (*Job, error) {
+	opened, err := project.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := script.Parse(opened.Script)
+	if err != nil {
+		return nil, err
+	}
+	entries := parsed.Entries()
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("the script has no lines to render")
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, response.Err(err)
+	}
+
+	options.DryRun = false
+	job := &Job{
+		ID:      id,
+		Profile: opened.Manifest.Profile,
+		Project: path,
+		Render:  &options,
+		State:   Queued,
+		Created: time.Now(),
+		Total:   len(entries),
+		Lines:   make([]Line, len(entries)),
+	}
+	for index, entry := range entries {
+		job.Lines[index] = Line{
+			Line:      entry.Line,
+			Character: entry.Character,
+			Text:      entry.Text,
+			State:     Queued,
+		}
+	}
+
+	return manager.queueJob(job)
+}
+
+// Get returns a copy of a job, or nil when there is no job with that ID
+func (manager *Manager) Get(id string) *Job {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	job, ok := manager.jobs[id]
+	if !ok {
+		return nil
+	}
+	return job.copy()
+}
+
+// List returns every job, oldest first, without their scripts and lines
+func (manager *Manager) List() []*Job {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	list := make([]*Job, 0, len(manager.jobs))
+	for _, job := range manager.jobs {
+		summary := *job
+		summary.Script, summary.Lines = "", nil
+		list = append(list, &summary)
+	}
+	slices.SortFunc(list, func(first, second *Job) int {
+		return first.Created.Compare(second.Created)
+	})
+	return list
+}
+
+// Cancel stops a queued or running job. The lines generated before it stopped are kept.
+func (manager *Manager) Cancel(id string) (*Job, error) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	job, ok := manager.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("no job %s", id)
+	}
+
+	switch job.State {
+	case Queued:
+		manager.finish(job, Cancelled, "")
+	case Running:
+		// The worker records the cancellation once the line being generated stops
+		tts.Cancel(tts.JobPrefix + job.ID)
+	default:
+		return nil, fmt.Errorf("job %s already %s", id, job.State)
+	}
+	return job.copy(), nil
+}
+
+// Remove deletes a finished job and its audio
+func (manager *Manager) Remove(id string) error {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	job, ok := manager.jobs[id]
+	if !ok {
+		return fmt.Errorf("no job %s", id)
+	}
+	if job.Active() {
+		return fmt.Errorf("job %s is still %s", id, job.State)
+	}
+
+	if err := os.RemoveAll(manager.path(id)); err != nil {
+		return response.Err(err)
+	}
+	delete(manager.jobs, id)
+	return nil
+}
+
+// ResultPath returns the combined file of a completed job, inside the project for a project job
+func (manager *Manager) ResultPath(id string) string {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	if job, ok := manager.jobs[id]; ok && job.Result != nil {
+		return job.Result.Output
+	}
+	return filepath.Join(manager.path(id), combinedFilename)
+}
+
+func (manager *Manager) path(id string) string {
+	return filepath.Join(manager.root, id)
+}
+
+// finish records the end of a job. The manager must be locked.
+func (manager *Manager) finish(job *Job, state State, message string) {
+	now := time.Now()
+	job.State = state
+	job.Error = message
+	job.Finished = &now
+
+	if err := manager.save(job); err != nil {
+		response.Warn("Failed to save job: %v", fmt.Errorf("%s: %w", job.ID, err))
+	}
+	manager.report()
+}
+
+// save writes the record of a job through a temporary file. The manager must be locked.
+func (manager *Manager) save(job *Job) error {
+	path := manager.path(job.ID)
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return response.Err(err)
+	}
+
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return response.Err(err)
+	}
+	return writeFile(filepath.Join(path, recordFilename), data)
+}
+
+// report puts the number of queued and running jobs in the daemon status file. The manager must be locked.
+func (manager *Manager) report() {
+	queued, running := 0, 0
+	for _, job := range manager.jobs {
+		switch job.State {
+		case Queued:
+			queued++
+		case Running:
+			running++
+		}
+	}
+	stats.SetJobs(queued, running)
+}
+
+func (manager *Manager) signal() {
+	select {
+	case manager.wake <- struct{}{}:
+	default:
+	}
+}
+
+func readRecord(path string) (*Job, error) {
+	data, err := os.ReadFile(filepath.Join(path, recordFilename))
+	if err != nil {
+		return nil, err
+	}
+
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", recordFilename, err)
+	}
+	if job.ID != filepath.Base(path) {
+		return nil, fmt.Errorf("%s belongs to job %s", recordFilename, job.ID)
+	}
+	return &job, nil
+}
+
+func newID() (string, error) {
+	bytes := make([]byte, 8)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+func writeFile(path string, data []byte) error {
+	temporary := path + ".tmp"
+	if err := os.WriteFile(temporary, data, 0644); err != nil {
+		return response.Err(err)
+	}
+	if err := os.Rename(temporary, path); err != nil {
+		os.Remove(temporary)
+		return response.Err(err)
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package jobs
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"nstudio/app/common/audio"
+	"nstudio/app/common/script"
+	"nstudio/app/common/status"
+	"nstudio/app/project"
+	"nstudio/app/server/synthesis"
+	"nstudio/app/tts"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+var unsafeFilenameRegex = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func (manager *Manager) work() {
+	for range manager.wake {
+		for {
+			id, ok := manager.next()
+			if !ok {
+				break
+			}
+			manager.run(id)
+		}
+	}
+}
+
+// next takes the oldest queued job off the queue
+func (manager *Manager) next() (string, bool) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	for len(manager.queue) > 0 {
+		id := manager.queue[0]
+		manager.queue = manager.queue[1:]
+		if job, ok := manager.jobs[id]; ok && job.State == Queued {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// run generates every line of a job that isn't done yet and joins them. Lines go through synthesis.GenerateEntry
+// like project renders, so they use the profile's cache.
+func (manager *Manager) run(id string) {
+	// Tracked under the job's ID, so Cancel stops the line being generated
+	ctx, done := tts.Track(context.Background(), tts.JobPrefix+id)
+	defer done()
+	defer status.Set(status.Ready, "")
+
+	manager.mutex.Lock()
+	job := manager.jobs[id]
+	if job.State != Queued {
+		// Cancelled after it was taken off the queue
+		manager.mutex.Unlock()
+		return
+	}
+	now := time.Now()
+	job.State = Running
+	job.Started = &now
+	manager.report()
+	jobPath := manager.path(id)
+	profileID, text, total := job.Profile, job.Script, job.Total
+	projectPath, options := job.Project, job.Render
+	manager.mutex.Unlock()
+
+	log.Info("running job", "id", id, "lines", total)
+
+	if projectPath != "" {
+		manager.runProject(ctx, job, projectPath, *options)
+		return
+	}
+
+	parsed, err := script.Parse(text)
+	if err != nil {
+		manager.end(id, Failed, err.Error())
+		return
+	}
+	entries := parsed.Entries()
+	if len(entries) != total {
+		manager.end(id, Failed, "the script no longer matches the job's lines")
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Join(jobPath, linesDirectory), 0755); err != nil {
+		manager.end(id, Failed, err.Error())
+		return
+	}
+
+	for index, entry := range entries {
+		manager.mutex.Lock()
+		line := job.Lines[index]
+		manager.mutex.Unlock()
+
+		if line.State == Completed {
+			if _, err := os.Stat(filepath.Join(jobPath, filepath.FromSlash(line.File))); err == nil {
+				continue
+			}
+		}
+
+		status.Set(status.Generating, fmt.Sprintf("Job %s: line %d of %d", id, index+1, len(entries)))
+
+		file, err := generateLine(ctx, jobPath, index, profileID, entry)
+		if ctx.Err() != nil {
+			manager.end(id, Cancelled, "")
+			return
+		}
+
+		manager.mutex.Lock()
+		if err != nil {
+			job.Lines[index].State = Failed
+			job.Lines[index].Error = err.Error()
+			manager.finish(job, Failed, fmt.Sprintf("line %d (%s): %v", entry.Line, entry.Character, err))
+			manager.mutex.Unlock()
+			return
+		}
+
+		job.Lines[index].State = Completed
+		job.Lines[index].File = file
+		job.Done = countDone(job.Lines)
+		if err := manager.save(job); err != nil {
+			log.Warn("failed to save job", "id", id, "error", err)
+		}
+		manager.mutex.Unlock()
+	}
+
+	if ctx.Err() != nil {
+		manager.end(id, Cancelled, "")
+		return
+	}
+
+	if err := combine(jobPath, manager.Get(id)); err != nil {
+		manager.end(id, Failed, err.Error())
+		return
+	}
+
+	manager.end(id, Completed, "")
+	log.Info("job completed", "id", id)
+}
+
+// runProject renders a project job. The project's render records the takes and the combined file, the job follows
+// its progress line by line.
+func (manager *Manager) runProject(ctx context.Context, job *Job, path string, options project.RenderOptions) {
+	opened, err := project.Open(path)
+	if err != nil {
+		manager.end(job.ID, Failed, err.Error())
+		return
+	}
+
+	options.Progress = func(index, total int) {
+		manager.mutex.Lock()
+		defer manager.mutex.Unlock()
+
+		if index >= len(job.Lines) || total != len(job.Lines) {
+			// The script changed since the job was queued, the render follows the project as it is now
+			job.Lines = make([]Line, total)
+			job.Total = total
+		}
+		job.Lines[index].State = Completed
+		job.Done = countDone(job.Lines)
+		if err := manager.save(job); err != nil {
+			log.Warn("failed to save job", "id", job.ID, "error", err)
+		}
+	}
+
+	result, err := opened.Render(ctx, options)
+	if ctx.Err() != nil {
+		manager.end(job.ID, Cancelled, "")
+		return
+	}
+	if err != nil {
+		manager.end(job.ID, Failed, err.Error())
+		return
+	}
+
+	manager.mutex.Lock()
+	job.Result = result
+	manager.finish(job, Completed, "")
+	manager.mutex.Unlock()
+	log.Info("job completed", "id", job.ID, "project", path)
+}
+
+func generateLine(ctx context.Context, jobPath string, index int, profileID string, entry *script.Entry) (string, error) {
+	audioObject, err := synthesis.GenerateEntry(ctx, profileID, entry)
+	if err != nil {
+		return "", err
+	}
+
+	wavData, err := audioObject.ToWAV()
+	if err != nil {
+		return "", err
+	}
+
+	file := filepath.Join(linesDirectory, lineFilename(index, entry.Character))
+	if err := writeFile(filepath.Join(jobPath, file), wavData); err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(file), nil
+}
+
+// combine joins the lines into combined.wav in the format of a project's default render settings
+func combine(jobPath string, job *Job) error {
+	files := make([]string, len(job.Lines))
+	for index, line := range job.Lines {
+		files[index] = filepath.Join(jobPath, filepath.FromSlash(line.File))
+	}
+
+	settings := project.DefaultRenderSettings()
+	pause := time.Duration(job.Pause * float64(time.Second))
+	_, err := audio.JoinWAVFiles(files, filepath.Join(jobPath, combinedFilename), pause, settings.SampleRate, settings.Channels, settings.BitDepth)
+	return err
+}
+
+// WriteZip writes the generated lines of a job as a zip archive
+func (manager *Manager) WriteZip(id string, writer io.Writer) error {
+	job := manager.Get(id)
+	if job == nil {
+		return fmt.Errorf("no job %s", id)
+	}
+
+	archive := zip.NewWriter(writer)
+	for _, line := range job.Lines {
+		if line.State != Completed {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(manager.path(id), filepath.FromSlash(line.File)))
+		if err != nil {
+			return err
+		}
+		file, err := archive.Create(filepath.Base(line.File))
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(data); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func (manager *Manager) end(id string, state State, message string) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	manager.finish(manager.jobs[id], state, message)
+}
+
+func lineFilename(index int, character string) string {
+	return fmt.Sprintf("%04d-%s.wav", index+1, unsafeFilenameRegex.ReplaceAllString(character, "_"))
+}
+
+func countDone(lines []Line) int {
+	done := 0
+	for _, line := range lines {
+		if line.State == Completed {
+			done++
+		}
+	}
+	return done
+}
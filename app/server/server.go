@@ -3,8 +3,14 @@ package server
 import (
 	"fmt"
 	"nstudio/app/common/response"
+	serverFileSystem "nstudio/app/server/filesystem"
+	serverGRPC "nstudio/app/server/grpc"
 	serverHTTP "nstudio/app/server/http"
+	serverSocket "nstudio/app/server/socket"
 	"nstudio/app/server/stats"
+	serverWebSocket "nstudio/app/server/websocket"
+	"os"
+	"time"
 )
 
 type ServerMode string
@@ -25,6 +31,12 @@ type ServerConfig struct {
 	Port       int
 	Host       string
 	ConfigFile string
+	SocketPath string      // namedpipe mode, defaults to daemon.GetSocketFilePath()
+	SocketMode os.FileMode // namedpipe mode permissions, defaults to 0600
+
+	Inbox        string        // filesystem mode request directory
+	Outbox       string        // filesystem mode output directory
+	PollInterval time.Duration // filesystem mode inbox polling interval
 }
 
 type ServerAppInterface interface {
@@ -65,21 +77,47 @@ func startHTTPServer(config ServerConfig) error {
 }
 
 func startWebSocketServer(config ServerConfig) error {
-	return response.Err(fmt.Errorf("WebSocket server not yet implemented"))
+	webSocketConfig := serverWebSocket.ServerConfig{
+		Host: config.Host,
+		Port: config.Port,
+	}
+
+	return serverWebSocket.StartWebSocketServer(webSocketConfig)
 }
 
 func startGRPCServer(config ServerConfig) error {
-	return response.Err(fmt.Errorf("gRPC server not yet implemented"))
+	grpcConfig := serverGRPC.ServerConfig{
+		Host: config.Host,
+		Port: config.Port,
+	}
+
+	return serverGRPC.StartGRPCServer(grpcConfig)
 }
 
 func startTCPServer(config ServerConfig) error {
-	return response.Err(fmt.Errorf("TCP server not yet implemented"))
+	socketConfig := serverSocket.ServerConfig{
+		Host: config.Host,
+		Port: config.Port,
+	}
+
+	return serverSocket.StartTCPServer(socketConfig)
 }
 
 func startNamedPipeServer(config ServerConfig) error {
-	return response.Err(fmt.Errorf("Named pipe server not yet implemented"))
+	socketConfig := serverSocket.ServerConfig{
+		SocketPath: config.SocketPath,
+		SocketMode: config.SocketMode,
+	}
+
+	return serverSocket.StartUnixServer(socketConfig)
 }
 
 func startFileSystemServer(config ServerConfig) error {
-	return response.Err(fmt.Errorf("File system server not yet implemented"))
+	fileSystemConfig := serverFileSystem.ServerConfig{
+		Inbox:        config.Inbox,
+		Outbox:       config.Outbox,
+		PollInterval: config.PollInterval,
+	}
+
+	return serverFileSystem.StartFileSystemServer(fileSystemConfig)
 }
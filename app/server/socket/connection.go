@@ -0,0 +1,151 @@
+package socket
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"nstudio/app/common/audio"
+	customMiddleware "nstudio/app/server/http/middleware"
+	"nstudio/app/server/synthesis"
+	"nstudio/app/tts"
+
+	"github.com/charmbracelet/log"
+)
+
+// connection serves one client. Requests are read and started as they arrive, up to maxPipelined at once,
+// while a writer goroutine sends the results back in the order the requests came in.
+type connection struct {
+	conn          net.Conn
+	authenticated bool
+
+	// ctx is cancelled when the server shuts down, stopping the generations still running
+	ctx context.Context
+}
+
+func (c *connection) serve() {
+	defer c.conn.Close()
+
+	results := make(chan chan response, maxPipelined)
+	written := make(chan struct{})
+	go c.writeResponses(results, written)
+
+	reader := bufio.NewReader(c.conn)
+	for {
+		payload, err := readFrame(reader)
+		if err != nil {
+			if errors.Is(err, errFrameTooLarge) {
+				result := make(chan response, 1)
+				result <- errorResponse("", err.Error(), 413)
+				results <- result
+			} else if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				log.Warn("socket read failed", "remote", c.conn.RemoteAddr(), "error", err)
+			}
+			break
+		}
+
+		result := make(chan response, 1)
+		results <- result
+
+		var header requestHeader
+		if err := json.Unmarshal(payload, &header); err != nil {
+			result <- errorResponse("", "Invalid request format", 400)
+			continue
+		}
+
+		if !c.authenticated {
+			if !customMiddleware.IsAuthorized(header.Key) {
+				result <- errorResponse(header.ID, "Valid authentication required", 401)
+				continue
+			}
+			c.authenticated = true
+		}
+
+		go func() {
+			// Tracked under the request's id, so it can be cancelled like any other generation
+			ctx, done := tts.Track(c.ctx, tts.SocketPrefix+header.ID)
+			defer done()
+			result <- synthesize(ctx, header)
+		}()
+	}
+
+	// A half-closed client still receives the responses to everything it sent
+	close(results)
+	<-written
+}
+
+func (c *connection) writeResponses(results chan chan response, written chan struct{}) {
+	defer close(written)
+
+	writer := bufio.NewWriter(c.conn)
+	failed := false
+
+	for result := range results {
+		resp := <-result
+		if failed {
+			continue
+		}
+
+		err := writeResponse(writer, resp)
+		if err == nil && len(results) == 0 {
+			err = writer.Flush()
+		}
+		if err != nil {
+			failed = true
+			_ = c.conn.Close()
+		}
+	}
+
+	if !failed {
+		_ = writer.Flush()
+	}
+}
+
+func synthesize(ctx context.Context, header requestHeader) response {
+	options, err := synthesis.ParseAudioOptions(header.Options)
+	if err != nil {
+		return errorResponse(header.ID, "Invalid audio options: "+err.Error(), 400)
+	}
+	if header.Format != "" {
+		if options == nil {
+			options = &synthesis.AudioOptions{Encoding: audio.DefaultEncodeOptions()}
+		}
+		options.Format = header.Format
+	}
+
+	request := synthesis.Request{
+		Profile:   header.Profile,
+		Character: header.Character,
+		Text:      header.Text,
+		Audio:     options,
+	}
+	if err := request.Validate(); err != nil {
+		return errorResponse(header.ID, err.Error(), 400)
+	}
+
+	audioObject, err := synthesis.Generate(ctx, request)
+	if err != nil {
+		return errorResponse(header.ID, err.Error(), 500)
+	}
+
+	data, err := synthesis.Encode(audioObject, options)
+	if err != nil {
+		return errorResponse(header.ID, err.Error(), 500)
+	}
+
+	format := options.OutputFormat()
+	return response{
+		header: responseHeader{
+			ID:          header.ID,
+			Success:     true,
+			Format:      format,
+			ContentType: audio.GetContentType(format),
+			SampleRate:  audioObject.Metadata.SampleRate,
+			Channels:    audioObject.Metadata.Channels,
+			BitDepth:    audioObject.Metadata.BitDepth,
+		},
+		audio: data,
+	}
+}
@@ -0,0 +1,26 @@
+//go:build !windows
+// +build !windows
+
+package socket
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+// listenUnix creates the socket with the umask narrowed to mode, so it is never reachable with looser permissions
+func listenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	previous := syscall.Umask(int(^mode & 0777))
+	listener, err := net.Listen("unix", path)
+	syscall.Umask(previous)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(path, mode); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
@@ -0,0 +1,21 @@
+//go:build windows
+// +build windows
+
+package socket
+
+import (
+	"net"
+	"os"
+)
+
+// listenUnix creates an AF_UNIX socket, supported since Windows 10 1803. Access follows the
+// directory's ACLs; only the read-only bit of mode has any effect on Windows.
+func listenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = os.Chmod(path, mode)
+	return listener, nil
+}
@@ -0,0 +1,94 @@
+package socket
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// The socket modes exchange length-prefixed frames: a uint32 big-endian byte count followed by that many bytes.
+//
+// A request is one frame holding a JSON request header. A response is one frame holding a JSON response
+// header, followed by exactly header.size bytes of audio (nothing when the request failed). Clients may
+// pipeline requests; responses always come back in request order.
+
+const (
+	maxHeaderSize = 1 << 20
+	maxPipelined  = 16
+)
+
+var errFrameTooLarge = errors.New("frame too large")
+
+type requestHeader struct {
+	ID        string                 `json:"id,omitempty"`
+	Key       string                 `json:"key,omitempty"`
+	Profile   string                 `json:"profile"`
+	Character string                 `json:"character"`
+	Text      string                 `json:"text"`
+	Format    string                 `json:"format,omitempty"`
+	Options   map[string]interface{} `json:"options,omitempty"`
+}
+
+type responseHeader struct {
+	ID          string `json:"id,omitempty"`
+	Success     bool   `json:"success"`
+	Error       string `json:"error,omitempty"`
+	Code        int    `json:"code,omitempty"`
+	Format      string `json:"format,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	SampleRate  int    `json:"sample_rate,omitempty"`
+	Channels    int    `json:"channels,omitempty"`
+	BitDepth    int    `json:"bit_depth,omitempty"`
+	Size        int    `json:"size"`
+}
+
+type response struct {
+	header responseHeader
+	audio  []byte
+}
+
+func errorResponse(requestID, message string, code int) response {
+	return response{header: responseHeader{ID: requestID, Success: false, Error: message, Code: code}}
+}
+
+func readFrame(reader io.Reader) ([]byte, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(reader, length[:]); err != nil {
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(length[:])
+	if size > maxHeaderSize {
+		return nil, fmt.Errorf("%w: %d bytes (max %d)", errFrameTooLarge, size, maxHeaderSize)
+	}
+
+	frame := make([]byte, size)
+	if _, err := io.ReadFull(reader, frame); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+func writeResponse(writer io.Writer, resp response) error {
+	resp.header.Size = len(resp.audio)
+
+	header, err := json.Marshal(resp.header)
+	if err != nil {
+		return err
+	}
+
+	frame := binary.BigEndian.AppendUint32(make([]byte, 0, 4+len(header)), uint32(len(header)))
+	frame = append(frame, header...)
+
+	if _, err := writer.Write(frame); err != nil {
+		return err
+	}
+	if len(resp.audio) > 0 {
+		if _, err := writer.Write(resp.audio); err != nil {
+			return err
+		}
+	}
+	return nil
+}
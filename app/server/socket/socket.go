@@ -0,0 +1,145 @@
+package socket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"nstudio/app/common/daemon"
+	"nstudio/app/server/stats"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+type ServerConfig struct {
+	Host string
+	Port int
+
+	SocketPath string
+	SocketMode os.FileMode
+}
+
+const defaultSocketMode os.FileMode = 0600
+
+// StartTCPServer serves the framed socket protocol over TCP. When an auth key is configured, the first
+// request on each connection must carry it in "key".
+func StartTCPServer(config ServerConfig) error {
+	address := fmt.Sprintf("%s:%d", config.Host, config.Port)
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("TCP server listening on %s\n", address)
+
+	return serve(listener, false)
+}
+
+// StartUnixServer serves the framed socket protocol on a Unix domain socket. Access is controlled by the
+// socket file's permissions instead of keys, so connections are trusted.
+func StartUnixServer(config ServerConfig) error {
+	path := config.SocketPath
+	if path == "" {
+		path = daemon.GetSocketFilePath()
+	}
+
+	mode := config.SocketMode
+	if mode == 0 {
+		mode = defaultSocketMode
+	}
+
+	if removed, err := daemon.RemoveStaleSocket(path); err != nil {
+		return err
+	} else if removed {
+		log.Info("removed stale socket", "path", path)
+	}
+
+	listener, err := listenUnix(path, mode)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	stats.SetSocketPath(path)
+
+	fmt.Printf("Unix socket server listening on %s (mode %04o)\n", path, mode)
+
+	return serve(listener, true)
+}
+
+// serve accepts connections until SIGINT/SIGTERM, then closes the listener and every open connection.
+// trusted connections skip key authentication.
+func serve(listener net.Listener, trusted bool) error {
+	var (
+		mutex       sync.Mutex
+		connections = make(map[net.Conn]struct{})
+		wait        sync.WaitGroup
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					log.Error("socket accept failed", "error", err)
+				}
+				return
+			}
+
+			mutex.Lock()
+			connections[conn] = struct{}{}
+			mutex.Unlock()
+
+			wait.Add(1)
+			go func() {
+				defer wait.Done()
+
+				(&connection{conn: conn, authenticated: trusted, ctx: ctx}).serve()
+
+				mutex.Lock()
+				delete(connections, conn)
+				mutex.Unlock()
+			}()
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	fmt.Println("\nShutting down server...")
+
+	_ = listener.Close()
+
+	mutex.Lock()
+	for conn := range connections {
+		_ = conn.Close()
+	}
+	mutex.Unlock()
+
+	// Stop the generations still running, the grace period of the HTTP server covers engines that only notice
+	// between chunks
+	cancel()
+	stopped := make(chan struct{})
+	go func() {
+		wait.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(10 * time.Second):
+	}
+
+	fmt.Println("Server stopped")
+	return nil
+}
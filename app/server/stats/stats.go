@@ -11,6 +11,9 @@ import (
 var (
 	processedMessages int64
 	startTime         time.Time
+	socketPath        atomic.Value
+	queuedJobs        atomic.Int64
+	runningJobs       atomic.Int64
 )
 
 func Initialize() {
@@ -31,12 +34,30 @@ func IncrementMessages() {
 	atomic.AddInt64(&processedMessages, 1)
 }
 
+// SetSocketPath records the Unix socket the server listens on so --status and --stop can find it
+func SetSocketPath(path string) {
+	socketPath.Store(path)
+	updateDaemonStatusFile()
+}
+
+// SetJobs records how many background jobs are waiting and running so --status can show them
+func SetJobs(queued, running int) {
+	queuedJobs.Store(int64(queued))
+	runningJobs.Store(int64(running))
+	updateDaemonStatusFile()
+}
+
 func updateDaemonStatusFile() {
 	status := daemon.DaemonStatusInfo{
 		PID:               os.Getpid(),
 		Version:           config.GetInfo().Version,
 		StartTime:         startTime,
 		ProcessedMessages: atomic.LoadInt64(&processedMessages),
+		QueuedJobs:        int(queuedJobs.Load()),
+		RunningJobs:       int(runningJobs.Load()),
+	}
+	if path, ok := socketPath.Load().(string); ok {
+		status.SocketPath = path
 	}
 
 	go daemon.WriteDaemonStatus(status)
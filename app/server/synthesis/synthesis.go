@@ -0,0 +1,271 @@
+package synthesis
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"nstudio/app/cache"
+	"nstudio/app/common/audio"
+	"nstudio/app/common/response"
+	"nstudio/app/common/script"
+	"nstudio/app/common/ssml"
+	"nstudio/app/server/stats"
+	"nstudio/app/tts"
+	"nstudio/app/tts/profile"
+	"slices"
+	"strings"
+)
+
+const MaxTextLength = 10000
+
+var SupportedFormats = []string{"wav", "flac", "ogg", "pcm", "pcm_s16le", "mp3"}
+
+type AudioOptions struct {
+	Format     string `json:"format"`      // "pcm_s16le", "wav", "flac", etc.
+	SampleRate int    `json:"sample_rate"` // 22050, 24000, 44100, etc.
+	Channels   int    `json:"channels"`
+	BitDepth   int    `json:"bit_depth"` // 16, 24, 32
+
+	// Encoding carries "quality", "bitrate" (kbps) and "bitrate_mode" for ogg/mp3, and "compression_level" for flac
+	Encoding audio.EncodeOptions `json:"-"`
+}
+
+// Request is a profile TTS request as accepted by every server mode
+type Request struct {
+	Profile   string
+	Character string
+	Text      string
+	Audio     *AudioOptions
+}
+
+// Chunk is one encoded piece of a streamed request. Chunks are sent as soon as the engine produces them, so the
+// end of a stream is marked by a final chunk that carries no data.
+type Chunk struct {
+	Sequence int
+	Data     []byte
+	Final    bool
+	Metadata audio.AudioMetadata
+}
+
+// ParseAudioOptions reads the "audio" entry of a request's options map, returning nil when none was given
+func ParseAudioOptions(options map[string]interface{}) (*AudioOptions, error) {
+	if options == nil {
+		return nil, nil
+	}
+
+	audioOpts, exists := options["audio"]
+	if !exists {
+		return nil, nil
+	}
+
+	audioMap, ok := audioOpts.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid audio options format")
+	}
+
+	opts := &AudioOptions{
+		Format:     "wav", // default
+		SampleRate: 0,     // 0 means use engine default
+		Channels:   0,     // 0 means use engine default
+		BitDepth:   0,     // 0 means use engine default
+	}
+
+	if format, ok := audioMap["format"].(string); ok {
+		opts.Format = format
+	}
+	if sampleRate, ok := audioMap["sample_rate"].(float64); ok {
+		opts.SampleRate = int(sampleRate)
+	}
+	if channels, ok := audioMap["channels"].(float64); ok {
+		opts.Channels = int(channels)
+	}
+	if bitDepth, ok := audioMap["bit_depth"].(float64); ok {
+		opts.BitDepth = int(bitDepth)
+	}
+
+	encoding, err := audio.ParseEncodeOptions(audioMap)
+	if err != nil {
+		return nil, err
+	}
+	opts.Encoding = encoding
+
+	return opts, nil
+}
+
+// OutputFormat returns the lowercased requested format, defaulting to wav
+func (options *AudioOptions) OutputFormat() string {
+	if options == nil || options.Format == "" {
+		return "wav"
+	}
+	return strings.ToLower(options.Format)
+}
+
+func IsSupportedFormat(format string) bool {
+	return slices.Contains(SupportedFormats, format)
+}
+
+// Validate checks the fields every mode requires, using the same messages as the HTTP API
+func (request *Request) Validate() error {
+	if request.Profile == "" {
+		return fmt.Errorf("Profile field is required")
+	}
+	if request.Character == "" {
+		return fmt.Errorf("Character field is required")
+	}
+	if strings.TrimSpace(request.Text) == "" {
+		return fmt.Errorf("Text field is required")
+	}
+	if len(request.Text) > MaxTextLength {
+		return fmt.Errorf("Text too long (max %d characters)", MaxTextLength)
+	}
+	if ssml.IsSSML(request.Text) {
+		if _, err := ssml.Parse(request.Text); err != nil {
+			return err
+		}
+	}
+	if !IsSupportedFormat(request.Audio.OutputFormat()) {
+		return fmt.Errorf("Invalid format. Supported: %s", strings.Join(SupportedFormats, ", "))
+	}
+	return nil
+}
+
+// Encode applies the requested sample rate and channel count, then converts to the output format
+func Encode(audioObject *audio.Audio, options *AudioOptions) ([]byte, error) {
+	encodeOptions := audio.DefaultEncodeOptions()
+
+	if options != nil {
+		if options.SampleRate > 0 && options.SampleRate != audioObject.Metadata.SampleRate {
+			if err := audioObject.Resample(options.SampleRate); err != nil {
+				return nil, fmt.Errorf("failed to resample audio: %w", err)
+			}
+		}
+
+		if options.Channels > 0 && options.Channels != audioObject.Metadata.Channels {
+			if err := audioObject.ChangeChannels(options.Channels); err != nil {
+				return nil, fmt.Errorf("failed to change channels: %w", err)
+			}
+		}
+
+		encodeOptions = options.Encoding
+	}
+
+	data, err := audioObject.ToFormatWithOptions(options.OutputFormat(), encodeOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert audio format: %w", err)
+	}
+	return data, nil
+}
+
+// Generate synthesizes the whole request at once, going through the profile's cache like POST /tts
+func Generate(ctx context.Context, request Request) (*audio.Audio, error) {
+	voice, err := profile.GetManager().GetOrAllocateVoice(request.Profile, request.Character)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get voice allocation: %w", err)
+	}
+
+	audioObject, _, err := tts.GenerateCachedAudio(ctx, request.Profile, request.Character, voice, request.Text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate speech: %w", err)
+	}
+
+	stats.IncrementMessages()
+	return audioObject, nil
+}
+
+// GenerateEntry synthesizes one parsed script entry. Entries without directives go through Generate and the cache,
+// the others are rendered with their pauses, rate, volume and voice override applied.
+func GenerateEntry(ctx context.Context, profileID string, entry *script.Entry) (*audio.Audio, error) {
+	if !entry.HasDirectives() {
+		return Generate(ctx, Request{Profile: profileID, Character: entry.Character, Text: entry.Text})
+	}
+
+	voice := &entry.Voice
+	if !entry.HasVoiceOverride() {
+		allocated, err := profile.GetManager().GetOrAllocateVoice(profileID, entry.Character)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get voice allocation: %w", err)
+		}
+		voice = allocated
+	}
+
+	audioObject, err := script.Render(entry, func(text string) (*audio.Audio, error) {
+		return tts.GenerateAudio(ctx, voice, text)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate speech: %w", err)
+	}
+
+	stats.IncrementMessages()
+	return audioObject, nil
+}
+
+// Stream synthesizes the request through tts.GenerateAudioStream and hands each encoded chunk to emit as soon as
+// it is ready. A cache hit for the full text is sent as a single final chunk. Cancelling ctx stops the chunk
+// being generated and the stream with it.
+func Stream(ctx context.Context, request Request, emit func(Chunk) error) error {
+	voice, err := profile.GetManager().GetOrAllocateVoice(request.Profile, request.Character)
+	if err != nil {
+		return fmt.Errorf("failed to get voice allocation: %w", err)
+	}
+
+	cacheManager := cache.GetManager()
+	cacheEnabled := cacheManager.IsEnabledFor(request.Profile)
+
+	if cacheEnabled {
+		if audioObject, found := cacheManager.GetCachedAudio(request.Profile, request.Character, request.Text, voice.Key()); found {
+			data, err := Encode(audioObject, request.Audio)
+			if err != nil {
+				return err
+			}
+			stats.IncrementMessages()
+			return emit(Chunk{Sequence: 0, Data: data, Final: true, Metadata: audioObject.Metadata})
+		}
+	}
+
+	var generated bytes.Buffer
+	var generatedMetadata, metadata audio.AudioMetadata
+	sequence := 0
+
+	for audioObject, err := range tts.GenerateAudioStream(ctx, voice, request.Text) {
+		if err != nil {
+			return fmt.Errorf("failed to generate speech: %w", err)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if cacheEnabled {
+			pcmData, err := audioObject.ToPCM()
+			if err != nil {
+				return err
+			}
+			generated.Write(pcmData)
+			generatedMetadata = audioObject.Metadata
+		}
+
+		data, err := Encode(audioObject, request.Audio)
+		if err != nil {
+			return err
+		}
+
+		metadata = audioObject.Metadata
+		if err := emit(Chunk{Sequence: sequence, Data: data, Metadata: metadata}); err != nil {
+			return err
+		}
+		sequence++
+	}
+
+	if err := emit(Chunk{Sequence: sequence, Final: true, Metadata: metadata}); err != nil {
+		return err
+	}
+
+	if cacheEnabled {
+		generatedAudio := audio.NewAudioFromPCM(generated.Bytes(), generatedMetadata.SampleRate, generatedMetadata.Channels, generatedMetadata.BitDepth)
+		if err := cacheManager.CacheAudio(request.Profile, request.Character, request.Text, voice.Key(), generatedAudio); err != nil {
+			response.Warn("failed to cache audio: %v", err)
+		}
+	}
+
+	stats.IncrementMessages()
+	return nil
+}
@@ -0,0 +1,269 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"nstudio/app/common/audio"
+	"nstudio/app/server/synthesis"
+	"nstudio/app/tts/profile"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	gorilla "github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = pongWait * 9 / 10
+	maxMessageSize = 1 << 20
+	maxRequestID   = 256
+)
+
+// connection serves one client. Each synthesize request runs in its own goroutine so that
+// later requests and cancellations are read while earlier ones are still streaming.
+type connection struct {
+	socket     *gorilla.Conn
+	writeMutex sync.Mutex
+
+	// profile is the default for requests that omit one; only the read loop touches it
+	profile string
+	nextID  int
+
+	context context.Context
+	cancel  context.CancelFunc
+
+	requestMutex sync.Mutex
+	requests     map[string]context.CancelFunc
+	wait         sync.WaitGroup
+}
+
+func newConnection(socket *gorilla.Conn, profileID string) *connection {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &connection{
+		socket:   socket,
+		profile:  profileID,
+		context:  ctx,
+		cancel:   cancel,
+		requests: make(map[string]context.CancelFunc),
+	}
+}
+
+func (conn *connection) serve() {
+	defer conn.close()
+
+	conn.socket.SetReadLimit(maxMessageSize)
+	_ = conn.socket.SetReadDeadline(time.Now().Add(pongWait))
+	conn.socket.SetPongHandler(func(string) error {
+		return conn.socket.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	go conn.keepAlive()
+
+	conn.sendEvent(serverEvent{Type: eventReady, Profile: conn.profile})
+
+	for {
+		messageType, data, err := conn.socket.ReadMessage()
+		if err != nil {
+			if gorilla.IsUnexpectedCloseError(err, gorilla.CloseNormalClosure, gorilla.CloseGoingAway) {
+				log.Warn("websocket connection closed", "error", err)
+			}
+			return
+		}
+
+		if messageType != gorilla.TextMessage {
+			conn.sendError("", "Only JSON text messages are accepted", 400)
+			continue
+		}
+
+		var message clientMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			conn.sendError("", "Invalid message format", 400)
+			continue
+		}
+
+		conn.handleMessage(message)
+	}
+}
+
+// close cancels every in-flight request, waits for them to wind down and closes the socket
+func (conn *connection) close() {
+	conn.cancel()
+	conn.wait.Wait()
+	_ = conn.socket.Close()
+}
+
+// shutdown tells the client the server is going away and drops the connection, which ends the read loop
+func (conn *connection) shutdown() {
+	message := gorilla.FormatCloseMessage(gorilla.CloseGoingAway, "server shutting down")
+	_ = conn.socket.WriteControl(gorilla.CloseMessage, message, time.Now().Add(writeWait))
+	conn.cancel()
+	_ = conn.socket.Close()
+}
+
+func (conn *connection) keepAlive() {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-conn.context.Done():
+			return
+		case <-ticker.C:
+			if err := conn.socket.WriteControl(gorilla.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (conn *connection) handleMessage(message clientMessage) {
+	switch strings.ToLower(message.Type) {
+	case "", messageSynthesize:
+		conn.startRequest(message)
+	case messageCancel:
+		conn.cancelRequest(message.ID)
+	case messageProfile:
+		conn.selectProfile(message.Profile)
+	default:
+		conn.sendError(message.ID, "Unknown message type: "+message.Type, 400)
+	}
+}
+
+func (conn *connection) selectProfile(profileID string) {
+	if profileID == "" {
+		conn.sendError("", "Profile field is required", 400)
+		return
+	}
+
+	if _, err := profile.GetManager().GetProfile(profileID); err != nil {
+		conn.sendError("", "Profile not found: "+profileID, 404)
+		return
+	}
+
+	conn.profile = profileID
+	conn.sendEvent(serverEvent{Type: eventProfile, Profile: profileID})
+}
+
+func (conn *connection) startRequest(message clientMessage) {
+	if message.Profile == "" {
+		message.Profile = conn.profile
+	}
+
+	requestID := message.ID
+	if requestID == "" {
+		conn.nextID++
+		requestID = strconv.Itoa(conn.nextID)
+	}
+	if len(requestID) > maxRequestID {
+		conn.sendError("", "Request id too long (max 256 bytes)", 400)
+		return
+	}
+
+	audioOptions, err := message.GetAudioOptions()
+	if err != nil {
+		conn.sendError(requestID, "Invalid audio options: "+err.Error(), 400)
+		return
+	}
+
+	request := synthesis.Request{
+		Profile:   message.Profile,
+		Character: message.Character,
+		Text:      message.Text,
+		Audio:     audioOptions,
+	}
+	if err := request.Validate(); err != nil {
+		conn.sendError(requestID, err.Error(), 400)
+		return
+	}
+
+	conn.requestMutex.Lock()
+	if _, exists := conn.requests[requestID]; exists {
+		conn.requestMutex.Unlock()
+		conn.sendError(requestID, "A request with this id is already in progress", 409)
+		return
+	}
+	ctx, cancel := context.WithCancel(conn.context)
+	conn.requests[requestID] = cancel
+	conn.requestMutex.Unlock()
+
+	conn.wait.Add(1)
+	go conn.synthesize(ctx, requestID, request)
+}
+
+func (conn *connection) cancelRequest(requestID string) {
+	conn.requestMutex.Lock()
+	cancel, exists := conn.requests[requestID]
+	conn.requestMutex.Unlock()
+
+	if !exists {
+		conn.sendError(requestID, "No request in progress with this id", 404)
+		return
+	}
+
+	// The request goroutine reports the cancellation once synthesis stops
+	cancel()
+}
+
+func (conn *connection) synthesize(ctx context.Context, requestID string, request synthesis.Request) {
+	defer conn.wait.Done()
+	defer func() {
+		conn.requestMutex.Lock()
+		if cancel, exists := conn.requests[requestID]; exists {
+			cancel()
+			delete(conn.requests, requestID)
+		}
+		conn.requestMutex.Unlock()
+	}()
+
+	format := request.Audio.OutputFormat()
+	conn.sendEvent(serverEvent{
+		Type:        eventStart,
+		ID:          requestID,
+		Profile:     request.Profile,
+		Format:      format,
+		ContentType: audio.GetContentType(format),
+	})
+
+	chunks := 0
+	err := synthesis.Stream(ctx, request, func(chunk synthesis.Chunk) error {
+		chunks++
+		return conn.write(gorilla.BinaryMessage, encodeChunkFrame(requestID, chunk.Sequence, chunk.Final, chunk.Data))
+	})
+
+	switch {
+	case err != nil && ctx.Err() != nil:
+		if conn.context.Err() == nil {
+			conn.sendEvent(serverEvent{Type: eventCancelled, ID: requestID, Chunks: chunks})
+		}
+	case err != nil:
+		conn.sendError(requestID, err.Error(), 500)
+	default:
+		conn.sendEvent(serverEvent{Type: eventDone, ID: requestID, Chunks: chunks})
+	}
+}
+
+func (conn *connection) sendEvent(event serverEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Error("failed to encode websocket event", "error", err)
+		return
+	}
+	_ = conn.write(gorilla.TextMessage, data)
+}
+
+func (conn *connection) sendError(requestID, message string, code int) {
+	conn.sendEvent(serverEvent{Type: eventError, ID: requestID, Error: message, Code: code})
+}
+
+// write sends one frame; gorilla allows only a single concurrent writer per connection
+func (conn *connection) write(messageType int, data []byte) error {
+	conn.writeMutex.Lock()
+	defer conn.writeMutex.Unlock()
+
+	_ = conn.socket.SetWriteDeadline(time.Now().Add(writeWait))
+	return conn.socket.WriteMessage(messageType, data)
+}
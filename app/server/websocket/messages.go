@@ -0,0 +1,63 @@
+package websocket
+
+import (
+	"encoding/binary"
+	serverHTTP "nstudio/app/server/http"
+)
+
+const (
+	messageSynthesize = "synthesize"
+	messageCancel     = "cancel"
+	messageProfile    = "profile"
+
+	eventReady     = "ready"
+	eventProfile   = "profile"
+	eventStart     = "start"
+	eventDone      = "done"
+	eventCancelled = "cancelled"
+	eventError     = "error"
+
+	chunkFlagFinal = 0x01
+)
+
+// clientMessage is a JSON text frame sent by the client. Synthesize requests carry the same fields as POST /tts;
+// "profile" may be omitted to use the connection's selected profile.
+type clientMessage struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	serverHTTP.ProfileTTSRequest
+}
+
+// serverEvent is a JSON text frame sent to the client to report request progress
+type serverEvent struct {
+	Type        string `json:"type"`
+	ID          string `json:"id,omitempty"`
+	Profile     string `json:"profile,omitempty"`
+	Format      string `json:"format,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Chunks      int    `json:"chunks,omitempty"`
+	Error       string `json:"error,omitempty"`
+	Code        int    `json:"code,omitempty"`
+}
+
+// encodeChunkFrame builds the binary frame for one audio chunk:
+//
+//	uint16 big-endian length of the request ID
+//	request ID bytes
+//	uint32 big-endian chunk sequence number, starting at 0 for each request
+//	uint8 flags, bit 0 set on the request's final chunk, which carries no audio unless it was a cache hit
+//	encoded audio in the requested format
+func encodeChunkFrame(requestID string, sequence int, final bool, data []byte) []byte {
+	frame := make([]byte, 0, 2+len(requestID)+5+len(data))
+	frame = binary.BigEndian.AppendUint16(frame, uint16(len(requestID)))
+	frame = append(frame, requestID...)
+	frame = binary.BigEndian.AppendUint32(frame, uint32(sequence))
+
+	var flags byte
+	if final {
+		flags |= chunkFlagFinal
+	}
+	frame = append(frame, flags)
+
+	return append(frame, data...)
+}
@@ -0,0 +1,115 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	customMiddleware "nstudio/app/server/http/middleware"
+	"nstudio/app/server/http/responses"
+	"nstudio/app/tts/profile"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	gorilla "github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+type ServerConfig struct {
+	Host string
+	Port int
+}
+
+var (
+	upgrader = gorilla.Upgrader{
+		// Access is controlled by the auth keys, matching the HTTP API's open CORS policy
+		CheckOrigin: func(request *http.Request) bool { return true },
+	}
+
+	connectionsMutex sync.Mutex
+	connections      = make(map[*connection]struct{})
+)
+
+// StartWebSocketServer serves streaming TTS on /ws. Authentication follows the HTTP API:
+// a Bearer header or ?auth= query parameter holding the user or admin key.
+func StartWebSocketServer(config ServerConfig) error {
+	echoServer := echo.New()
+
+	echoServer.HideBanner = true
+	echoServer.HidePort = false
+
+	echoServer.Use(middleware.Logger())
+	echoServer.Use(middleware.Recover())
+	echoServer.Use(middleware.RequestID())
+
+	echoServer.GET("/ws", handleConnection, customMiddleware.AuthMiddleware)
+
+	address := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	fmt.Printf("WebSocket server listening on ws://%s/ws\n", address)
+
+	go func() {
+		if err := echoServer.Start(address); err != nil {
+			echoServer.Logger.Info("Shutting down the server")
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	fmt.Println("\nShutting down server...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Hijacked connections are invisible to echo's shutdown, so close them explicitly
+	connectionsMutex.Lock()
+	for conn := range connections {
+		conn.shutdown()
+	}
+	connectionsMutex.Unlock()
+
+	if err := echoServer.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	fmt.Println("Server stopped")
+	return nil
+}
+
+// handleConnection upgrades the request; ?profile= selects the connection's default profile
+func handleConnection(context echo.Context) error {
+	profileID := context.QueryParam("profile")
+	if profileID != "" {
+		if _, err := profile.GetManager().GetProfile(profileID); err != nil {
+			return context.JSON(http.StatusNotFound, responses.ErrorResponse{
+				Success: false,
+				Error:   "Profile not found",
+				Code:    404,
+			})
+		}
+	}
+
+	socket, err := upgrader.Upgrade(context.Response(), context.Request(), nil)
+	if err != nil {
+		// The upgrader has already written the error response
+		return nil
+	}
+
+	conn := newConnection(socket, profileID)
+
+	connectionsMutex.Lock()
+	connections[conn] = struct{}{}
+	connectionsMutex.Unlock()
+
+	conn.serve()
+
+	connectionsMutex.Lock()
+	delete(connections, conn)
+	connectionsMutex.Unlock()
+
+	return nil
+}
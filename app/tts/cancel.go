@@ -0,0 +1,73 @@
+package tts
+
+import (
+	"context"
+	"sync"
+)
+
+// Generations started by a server are tracked under an ID prefixed with the surface that started them, so an ID
+// chosen through one surface, like an X-Request-ID, can't cancel a socket request, a job or a warm-up
+const (
+	HTTPPrefix   = "http:"
+	SocketPrefix = "socket:"
+	JobPrefix    = "job:"
+	WarmupPrefix = "warmup:"
+)
+
+// generation is a tracked generation call, cancelled through Cancel or CancelAll
+type generation struct {
+	id     string
+	cancel context.CancelFunc
+}
+
+var (
+	generationsMutex sync.Mutex
+	generations      = map[*generation]struct{}{}
+)
+
+// Track derives a context from parent that Cancel(id) and CancelAll cancel. The returned done function must be
+// called once the generation finished. Several generations can share an id, Cancel stops all of them.
+func Track(parent context.Context, id string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+	tracked := &generation{id: id, cancel: cancel}
+
+	generationsMutex.Lock()
+	generations[tracked] = struct{}{}
+	generationsMutex.Unlock()
+
+	return ctx, func() {
+		generationsMutex.Lock()
+		delete(generations, tracked)
+		generationsMutex.Unlock()
+		cancel()
+	}
+}
+
+// Cancel stops the generations tracked under id and reports whether there were any
+func Cancel(id string) bool {
+	generationsMutex.Lock()
+	defer generationsMutex.Unlock()
+
+	found := false
+	for tracked := range generations {
+		if tracked.id == id {
+			tracked.cancel()
+			delete(generations, tracked)
+			found = true
+		}
+	}
+	return found
+}
+
+// CancelAll stops every tracked generation and returns how many there were
+func CancelAll() int {
+	generationsMutex.Lock()
+	defer generationsMutex.Unlock()
+
+	count := len(generations)
+	for tracked := range generations {
+		tracked.cancel()
+		delete(generations, tracked)
+	}
+	return count
+}
@@ -2,6 +2,7 @@ package elevenlabs
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -44,7 +45,7 @@ func (labs *ElevenLabs) Stop(modelName string) error {
 	return nil
 }
 
-func (labs *ElevenLabs) Play(message util.CharacterMessage) error {
+func (labs *ElevenLabs) Play(ctx context.Context, message util.CharacterMessage) error {
 	response.Debug(util.MessageData{
 		Summary: "Elevenlabs playing:" + message.Character,
 		Detail:  message.Text,
@@ -59,7 +60,7 @@ func (labs *ElevenLabs) Play(message util.CharacterMessage) error {
 		},
 	}
 
-	audioClip, err := labs.sendRequest(message.Voice.Voice, input)
+	audioClip, err := labs.sendRequest(ctx, message.Voice.Voice, input)
 	if err != nil {
 		return response.Err(err)
 	}
@@ -76,7 +77,7 @@ func (labs *ElevenLabs) Play(message util.CharacterMessage) error {
 	return nil
 }
 
-func (labs *ElevenLabs) Save(messages []util.CharacterMessage, play bool) error {
+func (labs *ElevenLabs) Save(ctx context.Context, messages []util.CharacterMessage, play bool) error {
 	response.Debug(util.MessageData{
 		Summary: "Elevenlabs saving messages",
 	})
@@ -96,7 +97,7 @@ func (labs *ElevenLabs) Save(messages []util.CharacterMessage, play bool) error
 			},
 		}
 
-		audioClip, err := labs.sendRequest(message.Voice.Voice, input)
+		audioClip, err := labs.sendRequest(ctx, message.Voice.Voice, input)
 		if err != nil {
 			return response.Err(err)
 		}
@@ -123,13 +124,13 @@ func (labs *ElevenLabs) Save(messages []util.CharacterMessage, play bool) error
 	return nil
 }
 
-func (labs *ElevenLabs) Generate(model string, payload []byte) ([]byte, error) {
+func (labs *ElevenLabs) Generate(ctx context.Context, model string, payload []byte) ([]byte, error) {
 	var request ElevenLabsRequest
 	if err := json.Unmarshal(payload, &request); err != nil {
 		return nil, response.Err(err)
 	}
 
-	pcmData, err := labs.sendRequest(request.VoiceID, request)
+	pcmData, err := labs.sendRequest(ctx, request.VoiceID, request)
 	if err != nil {
 		return nil, response.Err(err)
 	}
@@ -137,8 +138,8 @@ func (labs *ElevenLabs) Generate(model string, payload []byte) ([]byte, error) {
 	return pcmData, nil
 }
 
-func (labs *ElevenLabs) GenerateAudio(model string, payload []byte) (*commonAudio.Audio, error) {
-	pcmData, err := labs.Generate(model, payload)
+func (labs *ElevenLabs) GenerateAudio(ctx context.Context, model string, payload []byte) (*commonAudio.Audio, error) {
+	pcmData, err := labs.Generate(ctx, model, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -180,7 +181,7 @@ func (labs *ElevenLabs) FetchModels() map[string]engine.Model {
 // </editor-fold>
 
 // <editor-fold desc="Other">
-func (labs *ElevenLabs) sendRequest(voiceID string, data ElevenLabsRequest) ([]byte, error) {
+func (labs *ElevenLabs) sendRequest(ctx context.Context, voiceID string, data ElevenLabsRequest) ([]byte, error) {
 	apiKey := config.GetEngine().Api.ElevenLabs.ApiKey
 	if apiKey == "" {
 		return nil, response.Err(fmt.Errorf("Elevenlabs API Key is not set"))
@@ -193,7 +194,7 @@ func (labs *ElevenLabs) sendRequest(voiceID string, data ElevenLabsRequest) ([]b
 
 	url := fmt.Sprintf("https://api.elevenlabs.io/v1/text-to-speech/%s?output_format=%s", voiceID, labs.outputType)
 
-	httpRequest, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	httpRequest, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, response.Err(fmt.Errorf("failed to create HTTP request: %v", err))
 	}
@@ -1,8 +1,10 @@
 package engine
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"nstudio/app/common/audio"
 	"nstudio/app/common/response"
 	"nstudio/app/common/util"
@@ -27,20 +29,29 @@ func (t EngineType) MarshalJSON() ([]byte, error) {
 	}
 }
 
+// Base is an engine instance. Cancelling the context of a generation call stops its request or process.
 type Base interface {
 	Initialize() error
 	Start(modelName string) error
 	Stop(modelName string) error
-	Play(message util.CharacterMessage) error
-	Save(messages []util.CharacterMessage, play bool) error
+	Play(ctx context.Context, message util.CharacterMessage) error
+	Save(ctx context.Context, messages []util.CharacterMessage, play bool) error
 
 	//TODO Maybe remove Generate() from Base? this is only used by Play and Save
-	Generate(model string, payload []byte) ([]byte, error)
-	GenerateAudio(model string, payload []byte) (*audio.Audio, error)
+	Generate(ctx context.Context, model string, payload []byte) ([]byte, error)
+	GenerateAudio(ctx context.Context, model string, payload []byte) (*audio.Audio, error)
 	GetVoices(model string) ([]Voice, error)
 	FetchModels() map[string]Model
 }
 
+// Streamer is implemented by engines that can hand back audio while the rest of a clip is still being generated.
+// Engines without it are streamed a sentence at a time by tts.GenerateAudioStream.
+type Streamer interface {
+	// GenerateAudioStream yields chunks in playback order. Breaking out of the loop or cancelling ctx stops
+	// generation.
+	GenerateAudioStream(ctx context.Context, model string, payload []byte) iter.Seq2[*audio.Audio, error]
+}
+
 type Engine struct {
 	Engine Base             `json:"-"`
 	ID     string           `json:"id"`
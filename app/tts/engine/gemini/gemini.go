@@ -1,6 +1,7 @@
 package gemini
 
 import (
+	"context"
 	"encoding/json"
 	"nstudio/app/common/audio"
 	"nstudio/app/common/response"
@@ -32,7 +33,7 @@ func (gemini *Gemini) Stop(modelName string) error {
 	return nil
 }
 
-func (gemini *Gemini) Play(message util.CharacterMessage) error {
+func (gemini *Gemini) Play(ctx context.Context, message util.CharacterMessage) error {
 	response.Debug(util.MessageData{
 		Summary: "Gemini playing:" + message.Character,
 		Detail:  message.Text,
@@ -55,7 +56,7 @@ func (gemini *Gemini) Play(message util.CharacterMessage) error {
 		},
 	}
 
-	audioClip, err := gemini.sendRequest(input, message.Voice.Model)
+	audioClip, err := gemini.sendRequest(ctx, input, message.Voice.Model)
 	if err != nil {
 		return response.Err(err)
 	}
@@ -72,7 +73,7 @@ func (gemini *Gemini) Play(message util.CharacterMessage) error {
 	})
 }
 
-func (gemini *Gemini) Save(messages []util.CharacterMessage, play bool) error {
+func (gemini *Gemini) Save(ctx context.Context, messages []util.CharacterMessage, play bool) error {
 	response.Debug(util.MessageData{
 		Summary: "Gemini saving messages",
 	})
@@ -100,7 +101,7 @@ func (gemini *Gemini) Save(messages []util.CharacterMessage, play bool) error {
 			},
 		}
 
-		audioClip, err := gemini.sendRequest(input, message.Voice.Model)
+		audioClip, err := gemini.sendRequest(ctx, input, message.Voice.Model)
 		if err != nil {
 			return response.Err(err)
 		}
@@ -127,13 +128,13 @@ func (gemini *Gemini) Save(messages []util.CharacterMessage, play bool) error {
 	return nil
 }
 
-func (gemini *Gemini) Generate(model string, payload []byte) ([]byte, error) {
+func (gemini *Gemini) Generate(ctx context.Context, model string, payload []byte) ([]byte, error) {
 	var request GeminiRequest
 	if err := json.Unmarshal(payload, &request); err != nil {
 		return nil, response.Err(err)
 	}
 
-	pcmData, err := gemini.sendRequest(request, model)
+	pcmData, err := gemini.sendRequest(ctx, request, model)
 	if err != nil {
 		return nil, response.Err(err)
 	}
@@ -141,8 +142,8 @@ func (gemini *Gemini) Generate(model string, payload []byte) ([]byte, error) {
 	return pcmData, nil
 }
 
-func (gemini *Gemini) GenerateAudio(model string, payload []byte) (*audio.Audio, error) {
-	pcmData, err := gemini.Generate(model, payload)
+func (gemini *Gemini) GenerateAudio(ctx context.Context, model string, payload []byte) (*audio.Audio, error) {
+	pcmData, err := gemini.Generate(ctx, model, payload)
 	if err != nil {
 		return nil, err
 	}
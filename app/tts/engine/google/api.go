@@ -14,8 +14,7 @@ import (
 	"google.golang.org/api/option"
 )
 
-func (google *Google) sendRequest(data GoogleRequest) ([]byte, error) {
-	ctx := context.Background()
+func (google *Google) sendRequest(ctx context.Context, data GoogleRequest) ([]byte, error) {
 	apiKey := config.GetEngine().Api.Google.ApiKey
 	if apiKey == "" {
 		return nil, response.Err(fmt.Errorf("Google Cloud API key is not set"))
@@ -38,12 +37,19 @@ func (google *Google) sendRequest(data GoogleRequest) ([]byte, error) {
 		audioEncoding = texttospeechpb.AudioEncoding_ALAW
 	}
 
-	request := &texttospeechpb.SynthesizeSpeechRequest{
-		Input: &texttospeechpb.SynthesisInput{
-			InputSource: &texttospeechpb.SynthesisInput_Text{
-				Text: data.Input.Text,
-			},
+	input := &texttospeechpb.SynthesisInput{
+		InputSource: &texttospeechpb.SynthesisInput_Text{
+			Text: data.Input.Text,
 		},
+	}
+	if data.Input.SSML != "" {
+		input.InputSource = &texttospeechpb.SynthesisInput_Ssml{
+			Ssml: data.Input.SSML,
+		}
+	}
+
+	request := &texttospeechpb.SynthesizeSpeechRequest{
+		Input: input,
 		Voice: &texttospeechpb.VoiceSelectionParams{
 			LanguageCode: data.Voice.LanguageCode,
 			Name:         data.Voice.Name,
@@ -1,6 +1,7 @@
 package google
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"nstudio/app/common/audio"
@@ -35,14 +36,14 @@ func (google *Google) Stop(modelName string) error {
 	return nil
 }
 
-func (google *Google) Play(message util.CharacterMessage) error {
+func (google *Google) Play(ctx context.Context, message util.CharacterMessage) error {
 	response.Debug(util.MessageData{
 		Summary: "Google playing:" + message.Character,
 		Detail:  message.Text,
 	})
 
 	input := GoogleRequest{
-		Input: Input{Text: message.Text},
+		Input: NewInput(message.Text),
 		Voice: VoiceSelectionParams{
 			Name:         message.Voice.Voice,
 			LanguageCode: "en-US", // Default
@@ -61,7 +62,7 @@ func (google *Google) Play(message util.CharacterMessage) error {
 		input.Voice.LanguageCode = parts[0] + "-" + parts[1]
 	}
 
-	audioClip, err := google.sendRequest(input)
+	audioClip, err := google.sendRequest(ctx, input)
 	if err != nil {
 		return response.Err(err)
 	}
@@ -76,7 +77,7 @@ func (google *Google) Play(message util.CharacterMessage) error {
 	})
 }
 
-func (google *Google) Save(messages []util.CharacterMessage, play bool) error {
+func (google *Google) Save(ctx context.Context, messages []util.CharacterMessage, play bool) error {
 	response.Debug(util.MessageData{
 		Summary: "Google saving messages",
 	})
@@ -88,7 +89,7 @@ func (google *Google) Save(messages []util.CharacterMessage, play bool) error {
 
 	for _, message := range messages {
 		input := GoogleRequest{
-			Input: Input{Text: message.Text},
+			Input: NewInput(message.Text),
 			Voice: VoiceSelectionParams{
 				Name:         message.Voice.Voice,
 				LanguageCode: "en-US",
@@ -106,7 +107,7 @@ func (google *Google) Save(messages []util.CharacterMessage, play bool) error {
 			input.Voice.LanguageCode = parts[0] + "-" + parts[1]
 		}
 
-		audioClip, err := google.sendRequest(input)
+		audioClip, err := google.sendRequest(ctx, input)
 		if err != nil {
 			return response.Err(err)
 		}
@@ -133,7 +134,7 @@ func (google *Google) Save(messages []util.CharacterMessage, play bool) error {
 	return nil
 }
 
-func (google *Google) Generate(model string, payload []byte) ([]byte, error) {
+func (google *Google) Generate(ctx context.Context, model string, payload []byte) ([]byte, error) {
 	fmt.Println(string(payload))
 	var request GoogleRequest
 	if err := json.Unmarshal(payload, &request); err != nil {
@@ -142,7 +143,7 @@ func (google *Google) Generate(model string, payload []byte) ([]byte, error) {
 
 	request.AudioConfig.AudioEncoding = "LINEAR16"
 
-	pcmData, err := google.sendRequest(request)
+	pcmData, err := google.sendRequest(ctx, request)
 	if err != nil {
 		return nil, response.Err(err)
 	}
@@ -150,8 +151,8 @@ func (google *Google) Generate(model string, payload []byte) ([]byte, error) {
 	return pcmData, nil
 }
 
-func (google *Google) GenerateAudio(model string, payload []byte) (*audio.Audio, error) {
-	pcmData, err := google.Generate(model, payload)
+func (google *Google) GenerateAudio(ctx context.Context, model string, payload []byte) (*audio.Audio, error) {
+	pcmData, err := google.Generate(ctx, model, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -1,13 +1,25 @@
 package google
 
+import "nstudio/app/common/ssml"
+
 type GoogleRequest struct {
 	Input       Input                `json:"input"`
 	Voice       VoiceSelectionParams `json:"voice"`
 	AudioConfig AudioConfig          `json:"audioConfig"`
 }
 
+// Input holds either plain text or an SSML document
 type Input struct {
-	Text string `json:"text"`
+	Text string `json:"text,omitempty"`
+	SSML string `json:"ssml,omitempty"`
+}
+
+// NewInput passes SSML documents through as SSML, everything else as text
+func NewInput(text string) Input {
+	if ssml.IsSSML(text) {
+		return Input{SSML: text}
+	}
+	return Input{Text: text}
 }
 
 type VoiceSelectionParams struct {
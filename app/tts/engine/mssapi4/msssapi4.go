@@ -2,6 +2,7 @@ package mssapi4
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"nstudio/app/common/audio"
@@ -32,7 +33,7 @@ func (sapi *MsSapi4) Stop(modelName string) error {
 	return nil
 }
 
-func (sapi *MsSapi4) Play(message util.CharacterMessage) error {
+func (sapi *MsSapi4) Play(ctx context.Context, message util.CharacterMessage) error {
 	response.Debug(util.MessageData{
 		Summary: "Ms Sapi 4 playing:" + message.Character,
 		Detail:  message.Text,
@@ -47,7 +48,7 @@ func (sapi *MsSapi4) Play(message util.CharacterMessage) error {
 		return response.Err(err)
 	}
 
-	audioClip, err := sapi.Generate(message.Voice.Model, jsonPayload)
+	audioClip, err := sapi.Generate(ctx, message.Voice.Model, jsonPayload)
 	if err != nil {
 		return response.Err(err)
 	}
@@ -60,7 +61,7 @@ func (sapi *MsSapi4) Play(message util.CharacterMessage) error {
 	return nil
 }
 
-func (sapi *MsSapi4) Save(messages []util.CharacterMessage, play bool) error {
+func (sapi *MsSapi4) Save(ctx context.Context, messages []util.CharacterMessage, play bool) error {
 	response.Debug(util.MessageData{
 		Summary: "Ms Sapi 4 saving messages",
 	})
@@ -90,7 +91,7 @@ func (sapi *MsSapi4) Save(messages []util.CharacterMessage, play bool) error {
 			return response.Err(err)
 		}
 
-		audioClip, err := sapi.Generate(message.Voice.Model, jsonPayload)
+		audioClip, err := sapi.Generate(ctx, message.Voice.Model, jsonPayload)
 		if err != nil {
 			return response.Err(err)
 		}
@@ -108,7 +109,7 @@ func (sapi *MsSapi4) Save(messages []util.CharacterMessage, play bool) error {
 	return nil
 }
 
-func (sapi *MsSapi4) Generate(model string, payload []byte) ([]byte, error) {
+func (sapi *MsSapi4) Generate(ctx context.Context, model string, payload []byte) ([]byte, error) {
 	log.Info("GENERATE CALLED")
 	log.Info(string(payload))
 	var ttsPayload MsSapi4Request
@@ -145,7 +146,8 @@ func (sapi *MsSapi4) Generate(model string, payload []byte) ([]byte, error) {
 		return nil, response.Err(fmt.Errorf("text field is required in payload"))
 	}
 
-	command := exec.Command(
+	command := exec.CommandContext(
+		ctx,
 		config.GetEngine().Local.MsSapi4.Location,
 		voiceName,
 		strconv.Itoa(config.GetEngine().Local.MsSapi4.Pitch),
@@ -213,8 +215,8 @@ func (sapi *MsSapi4) Generate(model string, payload []byte) ([]byte, error) {
 	return audioBytes, nil
 }
 
-func (sapi *MsSapi4) GenerateAudio(model string, payload []byte) (*audio.Audio, error) {
-	wavBytes, err := sapi.Generate(model, payload)
+func (sapi *MsSapi4) GenerateAudio(ctx context.Context, model string, payload []byte) (*audio.Audio, error) {
+	wavBytes, err := sapi.Generate(ctx, model, payload)
 	if err != nil {
 		return nil, err
 	}
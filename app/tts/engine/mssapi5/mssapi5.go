@@ -1,6 +1,7 @@
 package mssapi5
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"nstudio/app/common/audio"
@@ -30,7 +31,7 @@ func (sapi *MsSapi5) Stop(modelName string) error {
 	return nil
 }
 
-func (sapi *MsSapi5) Play(message util.CharacterMessage) error {
+func (sapi *MsSapi5) Play(ctx context.Context, message util.CharacterMessage) error {
 	response.Debug(util.MessageData{
 		Summary: "MS SAPI5 playing:" + message.Character,
 		Detail:  message.Text,
@@ -45,7 +46,7 @@ func (sapi *MsSapi5) Play(message util.CharacterMessage) error {
 		return response.Err(err)
 	}
 
-	audioClip, err := sapi.Generate(message.Voice.Model, jsonPayload)
+	audioClip, err := sapi.Generate(ctx, message.Voice.Model, jsonPayload)
 	if err != nil {
 		return response.Err(err)
 	}
@@ -54,7 +55,7 @@ func (sapi *MsSapi5) Play(message util.CharacterMessage) error {
 	return nil
 }
 
-func (sapi *MsSapi5) Save(messages []util.CharacterMessage, play bool) error {
+func (sapi *MsSapi5) Save(ctx context.Context, messages []util.CharacterMessage, play bool) error {
 	response.Debug(util.MessageData{
 		Summary: "MS SAPI5 saving messages",
 	})
@@ -84,7 +85,7 @@ func (sapi *MsSapi5) Save(messages []util.CharacterMessage, play bool) error {
 			return response.Err(err)
 		}
 
-		audioClip, err := sapi.Generate(message.Voice.Model, jsonPayload)
+		audioClip, err := sapi.Generate(ctx, message.Voice.Model, jsonPayload)
 		if err != nil {
 			return response.Err(err)
 		}
@@ -102,7 +103,7 @@ func (sapi *MsSapi5) Save(messages []util.CharacterMessage, play bool) error {
 	return nil
 }
 
-func (sapi *MsSapi5) Generate(model string, payload []byte) ([]byte, error) {
+func (sapi *MsSapi5) Generate(ctx context.Context, model string, payload []byte) ([]byte, error) {
 	var ttsPayload MsSapi5Request
 	if err := json.Unmarshal(payload, &ttsPayload); err != nil {
 		return nil, response.Err(fmt.Errorf("failed to unmarshal payload: %w", err))
@@ -116,17 +117,26 @@ func (sapi *MsSapi5) Generate(model string, payload []byte) ([]byte, error) {
 		return nil, response.Err(fmt.Errorf("text field is required in payload"))
 	}
 
+	// SAPI can't be interrupted once it speaks, so cancelling only takes effect before and after
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	cfg := config.GetEngine().Local.MsSapi5
-	wavBytes, err := synthesize(ttsPayload.Voice, ttsPayload.Text, cfg.Rate, cfg.Volume)
+	rate := min(max(cfg.Rate+ttsPayload.Rate, -10), 10)
+	wavBytes, err := synthesize(ttsPayload.Voice, ttsPayload.Text, rate, cfg.Volume)
 	if err != nil {
 		return nil, response.Err(err)
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	return wavBytes, nil
 }
 
-func (sapi *MsSapi5) GenerateAudio(model string, payload []byte) (*audio.Audio, error) {
-	wavBytes, err := sapi.Generate(model, payload)
+func (sapi *MsSapi5) GenerateAudio(ctx context.Context, model string, payload []byte) (*audio.Audio, error) {
+	wavBytes, err := sapi.Generate(ctx, model, payload)
 	if err != nil {
 		return nil, err
 	}
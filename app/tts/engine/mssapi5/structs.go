@@ -3,4 +3,7 @@ package mssapi5
 type MsSapi5Request struct {
 	Text  string `json:"text"`
 	Voice string `json:"voice"`
+
+	// Rate is added to the configured rate, the result is kept within SAPI's -10 to 10
+	Rate int `json:"rate,omitempty"`
 }
@@ -1,6 +1,7 @@
 package openai
 
 import (
+	"context"
 	"encoding/json"
 	"nstudio/app/common/audio"
 	"nstudio/app/common/response"
@@ -41,7 +42,7 @@ func (openAI *OpenAI) Stop(modelName string) error {
 	return nil
 }
 
-func (openAI *OpenAI) Play(message util.CharacterMessage) error {
+func (openAI *OpenAI) Play(ctx context.Context, message util.CharacterMessage) error {
 	response.Debug(util.MessageData{
 		Summary: "OpenAI playing:" + message.Character,
 		Detail:  message.Text,
@@ -55,7 +56,7 @@ func (openAI *OpenAI) Play(message util.CharacterMessage) error {
 		Speed:          1,
 	}
 
-	audioClip, err := openAI.sendRequest(input)
+	audioClip, err := openAI.sendRequest(ctx, input)
 	if err != nil {
 		return response.Err(err)
 	}
@@ -70,7 +71,7 @@ func (openAI *OpenAI) Play(message util.CharacterMessage) error {
 	})
 }
 
-func (openAI *OpenAI) Save(messages []util.CharacterMessage, play bool) error {
+func (openAI *OpenAI) Save(ctx context.Context, messages []util.CharacterMessage, play bool) error {
 	response.Debug(util.MessageData{
 		Summary: "Openai saving messages",
 	})
@@ -89,7 +90,7 @@ func (openAI *OpenAI) Save(messages []util.CharacterMessage, play bool) error {
 			Speed:          1,
 		}
 
-		audioClip, err := openAI.sendRequest(input)
+		audioClip, err := openAI.sendRequest(ctx, input)
 		if err != nil {
 			return response.Err(err)
 		}
@@ -116,7 +117,7 @@ func (openAI *OpenAI) Save(messages []util.CharacterMessage, play bool) error {
 	return nil
 }
 
-func (openAI *OpenAI) Generate(model string, payload []byte) ([]byte, error) {
+func (openAI *OpenAI) Generate(ctx context.Context, model string, payload []byte) ([]byte, error) {
 	var request OpenAIRequest
 	if err := json.Unmarshal(payload, &request); err != nil {
 		return nil, response.Err(err)
@@ -124,9 +125,11 @@ func (openAI *OpenAI) Generate(model string, payload []byte) ([]byte, error) {
 
 	request.Model = model
 	request.ResponseFormat = openAI.outputType
-	request.Speed = 1
+	if request.Speed == 0 {
+		request.Speed = 1
+	}
 
-	flacData, err := openAI.sendRequest(request)
+	flacData, err := openAI.sendRequest(ctx, request)
 	if err != nil {
 		return nil, response.Err(err)
 	}
@@ -134,8 +137,8 @@ func (openAI *OpenAI) Generate(model string, payload []byte) ([]byte, error) {
 	return flacData, nil
 }
 
-func (openAI *OpenAI) GenerateAudio(model string, payload []byte) (*audio.Audio, error) {
-	flacData, err := openAI.Generate(model, payload)
+func (openAI *OpenAI) GenerateAudio(ctx context.Context, model string, payload []byte) (*audio.Audio, error) {
+	flacData, err := openAI.Generate(ctx, model, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -256,6 +256,24 @@ func (s *Synthesizer) DefaultOptions() SynthesizeOptions {
 }
 
 func (s *Synthesizer) Synthesize(text string, opts *SynthesizeOptions) ([]byte, int, error) {
+	var pcmBuf []byte
+	sampleRate := 0
+
+	err := s.SynthesizeStream(text, opts, func(pcm []byte, chunkSampleRate int) bool {
+		pcmBuf = append(pcmBuf, pcm...)
+		sampleRate = chunkSampleRate
+		return true
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return pcmBuf, sampleRate, nil
+}
+
+// SynthesizeStream runs text-to-speech and passes each chunk piper produces to yield as int16 PCM bytes.
+// When yield returns false the remaining chunks are still drained, so the synthesizer is ready for the next call.
+func (s *Synthesizer) SynthesizeStream(text string, opts *SynthesizeOptions, yield func(pcm []byte, sampleRate int) bool) error {
 	cText := C.CString(text)
 	defer C.free(unsafe.Pointer(cText))
 
@@ -273,23 +291,22 @@ func (s *Synthesizer) Synthesize(text string, opts *SynthesizeOptions) ([]byte,
 
 	rc := C.call_synth_start(s.synth, cText, cOptsPtr)
 	if int(rc) != piperOK {
-		return nil, 0, fmt.Errorf("piper_synthesize_start failed with code %d", int(rc))
+		return fmt.Errorf("piper_synthesize_start failed with code %d", int(rc))
 	}
 
-	var pcmBuf []byte
-	sampleRate := 0
+	stopped := false
 
 	for {
 		var chunk C.piper_audio_chunk_t
 		rc = C.call_synth_next(s.synth, &chunk)
 
 		if int(rc) < 0 {
-			return nil, 0, fmt.Errorf("piper_synthesize_next failed with code %d", int(rc))
+			return fmt.Errorf("piper_synthesize_next failed with code %d", int(rc))
 		}
 
-		if chunk.num_samples > 0 {
-			sampleRate = int(chunk.sample_rate)
+		if chunk.num_samples > 0 && !stopped {
 			floats := unsafe.Slice((*float32)(unsafe.Pointer(chunk.samples)), int(chunk.num_samples))
+			pcmBuf := make([]byte, 0, len(floats)*2)
 
 			for _, f := range floats {
 				if f > 1.0 {
@@ -302,6 +319,8 @@ func (s *Synthesizer) Synthesize(text string, opts *SynthesizeOptions) ([]byte,
 				binary.LittleEndian.PutUint16(b[:], uint16(sample))
 				pcmBuf = append(pcmBuf, b[:]...)
 			}
+
+			stopped = !yield(pcmBuf, int(chunk.sample_rate))
 		}
 
 		if int(rc) == piperDone {
@@ -309,5 +328,5 @@ func (s *Synthesizer) Synthesize(text string, opts *SynthesizeOptions) ([]byte,
 		}
 	}
 
-	return pcmBuf, sampleRate, nil
+	return nil
 }
@@ -34,3 +34,7 @@ func (s *Synthesizer) DefaultOptions() SynthesizeOptions {
 func (s *Synthesizer) Synthesize(text string, opts *SynthesizeOptions) ([]byte, int, error) {
 	return nil, 0, fmt.Errorf("piper-native is only supported on Windows")
 }
+
+func (s *Synthesizer) SynthesizeStream(text string, opts *SynthesizeOptions, yield func(pcm []byte, sampleRate int) bool) error {
+	return fmt.Errorf("piper-native is only supported on Windows")
+}
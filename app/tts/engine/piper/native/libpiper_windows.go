@@ -184,6 +184,24 @@ func (s *Synthesizer) DefaultOptions() SynthesizeOptions {
 
 // Synthesize runs text-to-speech and returns int16 PCM bytes and the sample rate.
 func (s *Synthesizer) Synthesize(text string, opts *SynthesizeOptions) ([]byte, int, error) {
+	var pcmBuf []byte
+	sampleRate := 0
+
+	err := s.SynthesizeStream(text, opts, func(pcm []byte, chunkSampleRate int) bool {
+		pcmBuf = append(pcmBuf, pcm...)
+		sampleRate = chunkSampleRate
+		return true
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return pcmBuf, sampleRate, nil
+}
+
+// SynthesizeStream runs text-to-speech and passes each chunk piper produces to yield as int16 PCM bytes.
+// When yield returns false the remaining chunks are still drained, so the synthesizer is ready for the next call.
+func (s *Synthesizer) SynthesizeStream(text string, opts *SynthesizeOptions, yield func(pcm []byte, sampleRate int) bool) error {
 	cText, _ := syscall.BytePtrFromString(text)
 
 	var optsPtr uintptr
@@ -204,11 +222,10 @@ func (s *Synthesizer) Synthesize(text string, opts *SynthesizeOptions) ([]byte,
 		optsPtr,
 	)
 	if int32(rc) != piperOK {
-		return nil, 0, fmt.Errorf("piper_synthesize_start failed with code %d", int32(rc))
+		return fmt.Errorf("piper_synthesize_start failed with code %d", int32(rc))
 	}
 
-	var pcmBuf []byte
-	sampleRate := 0
+	stopped := false
 
 	nextProc := s.usedDL.NewProc("piper_synthesize_next")
 	for {
@@ -219,13 +236,12 @@ func (s *Synthesizer) Synthesize(text string, opts *SynthesizeOptions) ([]byte,
 		)
 
 		if int32(rc) < 0 {
-			return nil, 0, fmt.Errorf("piper_synthesize_next failed with code %d", int32(rc))
+			return fmt.Errorf("piper_synthesize_next failed with code %d", int32(rc))
 		}
 
-		if chunk.NumSamples > 0 {
-			sampleRate = int(chunk.SampleRate)
-
+		if chunk.NumSamples > 0 && !stopped {
 			floats := unsafe.Slice((*float32)(unsafe.Pointer(chunk.Samples)), int(chunk.NumSamples))
+			pcmBuf := make([]byte, 0, len(floats)*2)
 
 			for _, f := range floats {
 				if f > 1.0 {
@@ -239,6 +255,8 @@ func (s *Synthesizer) Synthesize(text string, opts *SynthesizeOptions) ([]byte,
 				binary.LittleEndian.PutUint16(b[:], uint16(sample))
 				pcmBuf = append(pcmBuf, b[:]...)
 			}
+
+			stopped = !yield(pcmBuf, int(chunk.SampleRate))
 		}
 
 		if int32(rc) == piperDone {
@@ -246,5 +264,5 @@ func (s *Synthesizer) Synthesize(text string, opts *SynthesizeOptions) ([]byte,
 		}
 	}
 
-	return pcmBuf, sampleRate, nil
+	return nil
 }
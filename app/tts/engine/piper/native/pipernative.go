@@ -1,8 +1,10 @@
 package native
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"nstudio/app/common/audio"
 	"nstudio/app/common/response"
 	"nstudio/app/common/util"
@@ -165,7 +167,7 @@ func (piper *Piper) Stop(modelName string) error {
 	return nil
 }
 
-func (piper *Piper) Play(message util.CharacterMessage) error {
+func (piper *Piper) Play(ctx context.Context, message util.CharacterMessage) error {
 	response.Debug(util.MessageData{
 		Summary: "piper playing: " + message.Character,
 		Detail:  message.Text,
@@ -183,7 +185,7 @@ func (piper *Piper) Play(message util.CharacterMessage) error {
 		return response.Err(err)
 	}
 
-	audioClip, err := piper.Generate(message.Voice.Model, jsonBytes)
+	audioClip, err := piper.Generate(ctx, message.Voice.Model, jsonBytes)
 	if err != nil {
 		return response.Err(err)
 	}
@@ -196,7 +198,7 @@ func (piper *Piper) Play(message util.CharacterMessage) error {
 	return nil
 }
 
-func (piper *Piper) Save(messages []util.CharacterMessage, play bool) error {
+func (piper *Piper) Save(ctx context.Context, messages []util.CharacterMessage, play bool) error {
 	response.Debug(util.MessageData{
 		Summary: "piper saving messages",
 	})
@@ -225,7 +227,7 @@ func (piper *Piper) Save(messages []util.CharacterMessage, play bool) error {
 			return response.Err(err)
 		}
 
-		audioObj, err := piper.GenerateAudio(message.Voice.Model, jsonBytes)
+		audioObj, err := piper.GenerateAudio(ctx, message.Voice.Model, jsonBytes)
 		if err != nil {
 			return response.Err(err)
 		}
@@ -247,28 +249,106 @@ func (piper *Piper) Save(messages []util.CharacterMessage, play bool) error {
 	return nil
 }
 
-func (piper *Piper) Generate(model string, payload []byte) ([]byte, error) {
+func (piper *Piper) Generate(ctx context.Context, model string, payload []byte) ([]byte, error) {
 	log.Info("generating in piper")
 
+	instance, input, err := piper.prepare(model, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	instance.mu.Lock()
+	defer instance.mu.Unlock()
+
+	opts := instance.synth.DefaultOptions()
+	opts.SpeakerID = input.SpeakerID
+	if input.LengthScale > 0 {
+		opts.LengthScale *= input.LengthScale
+	}
+
+	// Chunks are collected until ctx is cancelled, piper still finishes the sentence it is on
+	var pcmBytes []byte
+	err = instance.synth.SynthesizeStream(input.Text, &opts, func(pcm []byte, sampleRate int) bool {
+		pcmBytes = append(pcmBytes, pcm...)
+		return ctx.Err() == nil
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err != nil {
+		return nil, response.Err(err)
+	}
+
+	return pcmBytes, nil
+}
+
+func (piper *Piper) GenerateAudio(ctx context.Context, model string, payload []byte) (*audio.Audio, error) {
+	rawBytes, err := piper.Generate(ctx, model, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return audio.NewAudioFromPCM(rawBytes, 22050, 1, 16), nil
+}
+
+// GenerateAudioStream yields each chunk as piper_synthesize_next produces it, which is roughly a sentence at a time
+func (piper *Piper) GenerateAudioStream(ctx context.Context, model string, payload []byte) iter.Seq2[*audio.Audio, error] {
+	return func(yield func(*audio.Audio, error) bool) {
+		instance, input, err := piper.prepare(model, payload)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		instance.mu.Lock()
+		defer instance.mu.Unlock()
+
+		opts := instance.synth.DefaultOptions()
+		opts.SpeakerID = input.SpeakerID
+		if input.LengthScale > 0 {
+			opts.LengthScale *= input.LengthScale
+		}
+
+		stopped := false
+		err = instance.synth.SynthesizeStream(input.Text, &opts, func(pcm []byte, sampleRate int) bool {
+			if sampleRate == 0 {
+				sampleRate = 22050
+			}
+			stopped = ctx.Err() != nil || !yield(audio.NewAudioFromPCM(pcm, sampleRate, 1, 16), nil)
+			return !stopped
+		})
+		if err := ctx.Err(); err != nil {
+			yield(nil, err)
+			return
+		}
+		if err != nil && !stopped {
+			yield(nil, response.Err(err))
+		}
+	}
+}
+
+// prepare returns the model's instance, starting it when needed, and the decoded payload
+func (piper *Piper) prepare(model string, payload []byte) (*PiperNativeInstance, PiperInput, error) {
+	var input PiperInput
+
 	instance, exists := piper.models[model]
 	if !exists {
 		if !config.GetEngineToggles()["piper"][model] {
-			return nil, response.Err(fmt.Errorf("model is not enabled: piper:%s", model))
+			return nil, input, response.Err(fmt.Errorf("model is not enabled: piper:%s", model))
 		}
 
 		response.NewWarn("piper model is not running: " + model)
 
 		err := piper.Start(model)
 		if err != nil {
-			return nil, response.Err(fmt.Errorf("failed to start piper model %s: %v", model, err))
+			return nil, input, response.Err(fmt.Errorf("failed to start piper model %s: %v", model, err))
 		}
 
 		instance = piper.models[model]
 	}
 
-	var input PiperInput
 	if err := json.Unmarshal(payload, &input); err != nil {
-		return nil, response.Err(err)
+		return nil, input, response.Err(err)
 	}
 
 	response.Debug(util.MessageData{
@@ -276,27 +356,7 @@ func (piper *Piper) Generate(model string, payload []byte) ([]byte, error) {
 		Detail:  input.Text,
 	})
 
-	instance.mu.Lock()
-	defer instance.mu.Unlock()
-
-	opts := instance.synth.DefaultOptions()
-	opts.SpeakerID = input.SpeakerID
-
-	pcmBytes, _, err := instance.synth.Synthesize(input.Text, &opts)
-	if err != nil {
-		return nil, response.Err(err)
-	}
-
-	return pcmBytes, nil
-}
-
-func (piper *Piper) GenerateAudio(model string, payload []byte) (*audio.Audio, error) {
-	rawBytes, err := piper.Generate(model, payload)
-	if err != nil {
-		return nil, err
-	}
-
-	return audio.NewAudioFromPCM(rawBytes, 22050, 1, 16), nil
+	return instance, input, nil
 }
 
 func (piper *Piper) GetVoices(model string) ([]engine.Voice, error) {
@@ -12,8 +12,9 @@ type PiperNativeInstance struct {
 }
 
 type PiperInput struct {
-	Text      string `json:"text"`
-	SpeakerID int    `json:"speaker_id"`
+	Text        string  `json:"text"`
+	SpeakerID   int     `json:"speaker_id"`
+	LengthScale float32 `json:"length_scale,omitempty"` // multiplies the model's own length scale
 }
 
 type Piper struct {
@@ -3,6 +3,7 @@ package piper
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -268,9 +269,9 @@ func (piper *Piper) Stop(modelName string) error {
 	return nil
 }
 
-func (piper *Piper) Play(message util.CharacterMessage) error {
+func (piper *Piper) Play(ctx context.Context, message util.CharacterMessage) error {
 	if piper.isNativeMode() {
-		return piper.getNative().Play(message)
+		return piper.getNative().Play(ctx, message)
 	}
 
 	response.Debug(util.MessageData{
@@ -291,7 +292,7 @@ func (piper *Piper) Play(message util.CharacterMessage) error {
 	}
 	jsonBytes = append(jsonBytes, '\n')
 
-	audioClip, err := piper.Generate(message.Voice.Model, jsonBytes)
+	audioClip, err := piper.Generate(ctx, message.Voice.Model, jsonBytes)
 	if err != nil {
 		return response.Err(err)
 	}
@@ -304,9 +305,9 @@ func (piper *Piper) Play(message util.CharacterMessage) error {
 	return nil
 }
 
-func (piper *Piper) Save(messages []util.CharacterMessage, play bool) error {
+func (piper *Piper) Save(ctx context.Context, messages []util.CharacterMessage, play bool) error {
 	if piper.isNativeMode() {
-		return piper.getNative().Save(messages, play)
+		return piper.getNative().Save(ctx, messages, play)
 	}
 
 	response.Debug(util.MessageData{
@@ -339,7 +340,7 @@ func (piper *Piper) Save(messages []util.CharacterMessage, play bool) error {
 		}
 		jsonBytes = append(jsonBytes, '\n')
 
-		audioClip, err := piper.Generate(message.Voice.Model, jsonBytes)
+		audioClip, err := piper.Generate(ctx, message.Voice.Model, jsonBytes)
 		if err != nil {
 			return response.Err(err)
 		}
@@ -352,9 +353,9 @@ func (piper *Piper) Save(messages []util.CharacterMessage, play bool) error {
 	return nil
 }
 
-func (piper *Piper) Generate(model string, payload []byte) ([]byte, error) {
+func (piper *Piper) Generate(ctx context.Context, model string, payload []byte) ([]byte, error) {
 	if piper.isNativeMode() {
-		return piper.getNative().Generate(model, payload)
+		return piper.getNative().Generate(ctx, model, payload)
 	}
 
 	log.Info("generating in piper")
@@ -379,13 +380,14 @@ func (piper *Piper) Generate(model string, payload []byte) ([]byte, error) {
 	response.Debug(util.MessageData{
 		Summary: fmt.Sprintf("Sending to piper model: %s payload: %s", model, string(payload)),
 	})
-	if _, err := piper.models[model].stdin.Write(payload); err != nil {
+	instance := piper.models[model]
+	if _, err := instance.stdin.Write(payload); err != nil {
 		return nil, response.Err(err)
 	}
 
-	endSignal := make(chan bool)
+	endSignal := make(chan bool, 1)
 	go func() {
-		scanner := bufio.NewScanner(piper.models[model].stderr)
+		scanner := bufio.NewScanner(instance.stderr)
 		log.Info("scanning output")
 		for scanner.Scan() {
 			text := scanner.Text()
@@ -396,26 +398,41 @@ func (piper *Piper) Generate(model string, payload []byte) ([]byte, error) {
 				return
 			}
 		}
+		// stderr closed, the process exited before finishing
+		endSignal <- false
 	}()
-	<-endSignal
+
+	select {
+	case finished := <-endSignal:
+		if !finished {
+			return nil, response.Err(fmt.Errorf("Piper model %s exited while generating", model))
+		}
+	case <-ctx.Done():
+		// Piper can't be told to drop a sentence, and what it still writes would end up in the next request's
+		// audio. The process is stopped instead, the next request starts it again with an empty buffer.
+		if err := piper.Stop(model); err != nil {
+			response.Warn("Failed to stop Piper after cancelling: %v\n", err)
+		}
+		return nil, ctx.Err()
+	}
 
 	log.Info("past end signal")
 
-	audioBytes := piper.models[model].audioData.buffer.Bytes()
+	audioBytes := instance.audioData.buffer.Bytes()
 	audioClip := make([]byte, len(audioBytes))
 	copy(audioClip, audioBytes)
 
-	piper.models[model].audioData.Reset()
+	instance.audioData.Reset()
 
 	return audioClip, nil
 }
 
-func (piper *Piper) GenerateAudio(model string, payload []byte) (*audio.Audio, error) {
+func (piper *Piper) GenerateAudio(ctx context.Context, model string, payload []byte) (*audio.Audio, error) {
 	if piper.isNativeMode() {
-		return piper.getNative().GenerateAudio(model, payload)
+		return piper.getNative().GenerateAudio(ctx, model, payload)
 	}
 
-	rawBytes, err := piper.Generate(model, payload)
+	rawBytes, err := piper.Generate(ctx, model, payload)
 	if err != nil {
 		return nil, err
 	}
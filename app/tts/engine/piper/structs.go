@@ -26,6 +26,9 @@ type PiperInput struct {
 type PiperInputLite struct {
 	Text      string `json:"text"`
 	SpeakerID int    `json:"speaker_id"`
+
+	// LengthScale multiplies the model's phoneme durations, above 1 is slower. Only native mode applies it.
+	LengthScale float32 `json:"length_scale,omitempty"`
 }
 
 type Piper struct {
@@ -0,0 +1,243 @@
+package tts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"nstudio/app/cache"
+	"nstudio/app/common/eventManager"
+	"nstudio/app/common/response"
+	"nstudio/app/common/script"
+	"nstudio/app/common/status"
+	"nstudio/app/common/util"
+	"nstudio/app/common/util/fileIndex"
+	"nstudio/app/config"
+	"nstudio/app/tts/profile"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// linesDirectory keeps the audio of every rendered line, named by its LineKey, inside the output directory
+const linesDirectory = ".lines"
+
+// LinePlan is what rendering does with one entry of a script
+type LinePlan struct {
+	Line      int    `json:"line"`
+	Character string `json:"character"`
+	Text      string `json:"text"`
+	Voice     string `json:"voice"`
+	Key       string `json:"key"`
+	Reuse     bool   `json:"reuse"`
+}
+
+type RenderPlan struct {
+	Generated int        `json:"generated"`
+	Reused    int        `json:"reused"`
+	Lines     []LinePlan `json:"lines"`
+}
+
+// LineKey identifies the audio of an entry: its text as util.NormalizeText normalizes it, the voice speaking it, the
+// engine settings of that voice and its directives. Entries with the same key sound the same, so their audio can be
+// reused.
+func LineKey(entry *script.Entry, voice *util.CharacterVoice) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s\x00%s\x00%s", util.NormalizeText(entry.Text), voice.Key(), cache.Parameters(voice.Key()))
+	for _, directive := range entry.Directives {
+		fmt.Fprintf(&builder, "\x00%s:%d:%g:%g:%g:%s",
+			directive.Kind, directive.Offset, directive.Seconds, directive.Rate, directive.Decibels, directive.Voice)
+	}
+
+	hash := sha256.Sum256([]byte(builder.String()))
+	return hex.EncodeToString(hash[:])
+}
+
+// EntryVoice returns the voice an entry is spoken with: its voice override, or the one of its character
+func EntryVoice(profileID string, entry *script.Entry) (*util.CharacterVoice, error) {
+	if entry.HasVoiceOverride() {
+		return &entry.Voice, nil
+	}
+
+	voice, err := profile.GetManager().GetOrAllocateVoice(profileID, entry.Character)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get voice allocation: %w", err)
+	}
+	return voice, nil
+}
+
+// PlanScript reports which entries would be generated and which would reuse the audio of an earlier render,
+// without generating anything
+func PlanScript(entries []*script.Entry, profileID string) (*RenderPlan, error) {
+	plan, _, err := planScript(entries, profileID)
+	return plan, err
+}
+
+func planScript(entries []*script.Entry, profileID string) (*RenderPlan, []*util.CharacterVoice, error) {
+	linesPath, err := linesPath(profileID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plan := &RenderPlan{Lines: make([]LinePlan, len(entries))}
+	voices := make([]*util.CharacterVoice, len(entries))
+	for index, entry := range entries {
+		voice, err := EntryVoice(profileID, entry)
+		if err != nil {
+			return nil, nil, response.Err(err)
+		}
+		voices[index] = voice
+
+		key := LineKey(entry, voice)
+		_, err = os.Stat(filepath.Join(linesPath, key+".wav"))
+
+		plan.Lines[index] = LinePlan{
+			Line:      entry.Line,
+			Character: entry.Character,
+			Text:      entry.Text,
+			Voice:     voice.Key(),
+			Key:       key,
+			Reuse:     err == nil,
+		}
+	}
+	plan.count()
+
+	return plan, voices, nil
+}
+
+func (plan *RenderPlan) count() {
+	plan.Generated, plan.Reused = 0, 0
+	for _, line := range plan.Lines {
+		if line.Reuse {
+			plan.Reused++
+		} else {
+			plan.Generated++
+		}
+	}
+}
+
+// RenderScript saves every entry like GenerateScript, but only generates the entries that changed since they were
+// last rendered; the others are copied from the earlier render. Lines are generated concurrently, one per engine
+// instance, and written in script order. Cancelling ctx stops the lines that haven't started. Once every line is
+// written, kept lines the script doesn't use anymore are removed. fileIndex must have been reset.
+func RenderScript(ctx context.Context, entries []*script.Entry, profileID string) (*RenderPlan, error) {
+	plan, voices, err := planScript(entries, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	linesPath, err := linesPath(profileID)
+	if err != nil {
+		return nil, err
+	}
+	err, expandedPath := util.ExpandPath(config.GetSettings().OutputPath)
+	if err != nil {
+		return nil, response.Err(err)
+	}
+
+	render := func(ctx context.Context, index int) ([]byte, error) {
+		line := &plan.Lines[index]
+		if line.Reuse {
+			if wavData, err := os.ReadFile(filepath.Join(linesPath, line.Key+".wav")); err == nil {
+				return wavData, nil
+			}
+			// The earlier audio went away since planning, the line is generated after all
+			line.Reuse = false
+		}
+
+		rendered, err := renderEntry(ctx, profileID, entries[index], voices[index])
+		if err != nil {
+			return nil, fmt.Errorf("line %d (%s): %w", entries[index].Line, entries[index].Character, err)
+		}
+		return rendered.ToWAV()
+	}
+
+	done := 0
+	write := func(index int, wavData []byte) error {
+		entry, line := entries[index], &plan.Lines[index]
+
+		outputFile := util.GenerateFilename(entry.CharacterMessage, fileIndex.Get(), expandedPath)
+		if err := os.WriteFile(outputFile, wavData, 0644); err != nil {
+			return response.Err(err)
+		}
+		if !line.Reuse {
+			storeLine(linesPath, line, wavData)
+		}
+
+		done++
+		status.Set(status.Generating, fmt.Sprintf("Line %d of %d", done, len(entries)))
+		eventManager.GetInstance().EmitEvent(ScriptProgressEvent, ScriptProgress{
+			Index:     index,
+			Line:      entry.Line,
+			Character: entry.Character,
+			Reused:    line.Reuse,
+			Done:      done,
+			Total:     len(entries),
+		})
+		return nil
+	}
+
+	status.Set(status.Generating, fmt.Sprintf("Line 0 of %d", len(entries)))
+	if err := renderOrdered(ctx, len(entries), workerCount(voices), render, write); err != nil {
+		return nil, err
+	}
+
+	plan.count()
+	pruneLines(linesPath, plan)
+	return plan, nil
+}
+
+// pruneLines removes the kept lines the plan of the last render doesn't use, so lines of earlier versions of the
+// script don't pile up
+func pruneLines(linesPath string, plan *RenderPlan) {
+	used := make(map[string]bool, len(plan.Lines))
+	for _, line := range plan.Lines {
+		used[line.Key+".wav"] = true
+	}
+
+	entries, err := os.ReadDir(linesPath)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || used[entry.Name()] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(linesPath, entry.Name())); err != nil {
+			response.Warn("Failed to remove a line of an earlier render: %v\n", err)
+		}
+	}
+}
+
+// storeLine keeps a generated line for the next render. A line that can't be stored is only generated again
+// next time.
+func storeLine(linesPath string, line *LinePlan, wavData []byte) {
+	if err := os.MkdirAll(linesPath, 0755); err != nil {
+		response.Warn("Failed to create the rendered lines directory: %v\n", err)
+		return
+	}
+	if err := writeFile(filepath.Join(linesPath, line.Key+".wav"), wavData); err != nil {
+		response.Warn("Failed to keep a line for the next render: %v\n", fmt.Errorf("line %d: %w", line.Line, err))
+	}
+}
+
+func linesPath(profileID string) (string, error) {
+	err, expandedPath := util.ExpandPath(config.GetSettings().OutputPath)
+	if err != nil {
+		return "", response.Err(err)
+	}
+	return filepath.Join(expandedPath, linesDirectory, profileID), nil
+}
+
+// writeFile writes through a temporary file, so an interrupted write never looks like a complete one
+func writeFile(destination string, data []byte) error {
+	temporary := destination + ".tmp"
+	if err := os.WriteFile(temporary, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(temporary, destination); err != nil {
+		os.Remove(temporary)
+		return err
+	}
+	return nil
+}
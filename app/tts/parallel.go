@@ -0,0 +1,125 @@
+package tts
+
+import (
+	"context"
+	"nstudio/app/common/audio"
+	"nstudio/app/common/script"
+	"nstudio/app/common/util"
+	"nstudio/app/tts/modelManager"
+	"sync"
+)
+
+// ScriptProgressEvent is emitted with a ScriptProgress after every line RenderScript writes
+const ScriptProgressEvent = "script.progress"
+
+type ScriptProgress struct {
+	Index     int    `json:"index"` // position in the script, from 0
+	Line      int    `json:"line"`  // line number in the script
+	Character string `json:"character"`
+	Reused    bool   `json:"reused"`
+	Done      int    `json:"done"`
+	Total     int    `json:"total"`
+}
+
+// renderOrdered calls render for the indexes 0 to count-1 on up to workers goroutines and passes the results to
+// write in index order. Workers stay at most 2*workers lines ahead of write, so finished lines don't pile up behind
+// a slow one. The first error from render or write, or ctx being cancelled, stops the lines that haven't started
+// and is returned.
+func renderOrdered(ctx context.Context, count, workers int, render func(ctx context.Context, index int) ([]byte, error), write func(index int, data []byte) error) error {
+	type result struct {
+		data []byte
+		err  error
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wait sync.WaitGroup
+	defer func() {
+		cancel()
+		wait.Wait()
+	}()
+
+	results := make([]chan result, count)
+	for index := range results {
+		results[index] = make(chan result, 1)
+	}
+
+	window := make(chan struct{}, 2*workers)
+	jobs := make(chan int)
+
+	go func() {
+		defer close(jobs)
+		for index := range count {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- index:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for range workers {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for index := range jobs {
+				if err := ctx.Err(); err != nil {
+					results[index] <- result{err: err}
+					continue
+				}
+				data, err := render(ctx, index)
+				results[index] <- result{data: data, err: err}
+			}
+		}()
+	}
+
+	for index := range count {
+		var line result
+		select {
+		case line = <-results[index]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if line.err != nil {
+			return line.err
+		}
+		if err := write(index, line.data); err != nil {
+			return err
+		}
+		<-window
+	}
+
+	return nil
+}
+
+// workerCount is how many lines can be generated at once: one per engine instance of the models the voices use.
+// Each engine instance still serves one line at a time, GetEngineInstance waits for a free one.
+func workerCount(voices []*util.CharacterVoice) int {
+	models := map[string]bool{}
+	workers := 0
+	for _, voice := range voices {
+		key := voice.Engine + ":" + voice.Model
+		if models[key] {
+			continue
+		}
+		models[key] = true
+		workers += max(modelManager.GetInstanceCount(voice.Engine, voice.Model), 1)
+	}
+	return min(max(workers, 1), max(len(voices), 1))
+}
+
+// renderEntry generates an entry with its directives applied. Entries without directives go through the cache.
+func renderEntry(ctx context.Context, profileID string, entry *script.Entry, voice *util.CharacterVoice) (*audio.Audio, error) {
+	if entry.HasDirectives() {
+		return script.Render(entry, func(text string) (*audio.Audio, error) {
+			return GenerateAudio(ctx, voice, text)
+		})
+	}
+
+	audioObj, _, err := GenerateCachedAudio(ctx, profileID, entry.Character, voice, entry.Text)
+	return audioObj, err
+}
@@ -1,14 +1,22 @@
 package tts
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
+	"math"
 	"nstudio/app/cache"
 	"nstudio/app/common/audio"
 	"nstudio/app/common/response"
+	"nstudio/app/common/script"
+	"nstudio/app/common/ssml"
 	"nstudio/app/common/status"
 	"nstudio/app/common/util"
+	"nstudio/app/common/util/fileIndex"
+	"nstudio/app/config"
 	"nstudio/app/enums/Engines"
+	"nstudio/app/tts/engine"
 	"nstudio/app/tts/engine/elevenlabs"
 	"nstudio/app/tts/engine/gemini"
 	"nstudio/app/tts/engine/google"
@@ -18,37 +26,23 @@ import (
 	"nstudio/app/tts/engine/piper"
 	"nstudio/app/tts/modelManager"
 	"nstudio/app/tts/profile"
+	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
-func GenerateSpeech(messages []util.CharacterMessage, saveOutput bool, profileID string) error {
+// GenerateSpeech plays or saves the messages. Cancelling ctx stops the message being generated and skips the rest.
+func GenerateSpeech(ctx context.Context, messages []util.CharacterMessage, saveOutput bool, profileID string) error {
 	profileManager := profile.GetManager()
 	cacheManager := cache.GetManager()
+	cacheEnabled := cacheManager.IsEnabledFor(profileID)
 
 	status.Set(status.Generating, "Generating Audio")
 
 	for _, message := range messages {
-		var rawAudio []byte
-		var foundInCache bool
-
-		if !saveOutput && cacheManager.IsEnabled() {
-			rawAudio, foundInCache = cacheManager.GetCachedAudio(profileID, message.Character, message.Text)
-			if foundInCache {
-				status.Set(status.Playing, "Using cached audio")
-
-				if len(rawAudio) == 0 {
-					return response.NewWarn(fmt.Sprintf("Cached audio is empty for character: %s for message: %s",
-						message.Character,
-						message.Text,
-					))
-				}
-
-				status.Set(status.Playing, "")
-				audio.PlayRawAudioBytes(rawAudio)
-				status.Set(status.Ready, "")
-				return nil
-			}
+		if err := ctx.Err(); err != nil {
+			return err
 		}
 
 		voice, err := profileManager.GetOrAllocateVoice(profileID, message.Character)
@@ -58,46 +52,117 @@ func GenerateSpeech(messages []util.CharacterMessage, saveOutput bool, profileID
 
 		message.Voice = *voice
 
-		selectedEngineInstance, releaseFunc, ok := modelManager.GetEngineInstance(voice.Engine, voice.Model)
-		if !ok {
-			return response.Err(fmt.Errorf("Failed to retrieve engine instance: %s/%s", voice.Engine, voice.Model))
+		if cacheEnabled {
+			if cachedAudio, found := cacheManager.GetCachedAudio(profileID, message.Character, message.Text, voice.Key()); found {
+				if saveOutput {
+					err = saveRendered(message, cachedAudio)
+				} else {
+					status.Set(status.Playing, "Using cached audio")
+					err = playRendered(cachedAudio)
+				}
+				if err != nil {
+					return response.Err(err)
+				}
+				continue
+			}
 		}
 
-		if saveOutput {
-			err := selectedEngineInstance.Save([]util.CharacterMessage{message}, false)
-			releaseFunc()
+		if saveOutput && (cacheEnabled || ssml.IsSSML(message.Text) && voice.Engine != string(Engines.Google)) {
+			// The engines' Save writes the file itself, so audio that has to be cached, or SSML that has to be
+			// rendered, is generated here instead
+			audioObj, err := GenerateAudio(ctx, voice, message.Text)
 			if err != nil {
+				return err
+			}
+			if err := saveRendered(message, audioObj); err != nil {
 				return response.Err(err)
 			}
+			if cacheEnabled {
+				if err := cacheManager.CacheAudio(profileID, message.Character, message.Text, voice.Key(), audioObj); err != nil {
+					response.Warn("Failed to cache audio: %v\n", err)
+				}
+			}
+		} else if saveOutput {
+			selectedEngineInstance, releaseFunc, ok := modelManager.GetEngineInstance(voice.Engine, voice.Model)
+			if !ok {
+				return response.Err(fmt.Errorf("Failed to retrieve engine instance: %s/%s", voice.Engine, voice.Model))
+			}
 
-			// Cache the generated audio if enabled
-			// Note: For Save mode, we'd need to read back the saved file to cache it
-			// For now, caching only works in play mode
+			err := selectedEngineInstance.Save(ctx, []util.CharacterMessage{message}, false)
+			releaseFunc()
+			if err != nil {
+				return response.Err(err)
+			}
 		} else {
 			status.Set(status.Playing, "")
-			message.Voice = *voice
 
-			payload, err := preparePayload(message)
+			chunks, err := playAudioStream(ctx, voice, message.Text)
 			if err != nil {
 				return response.Err(err)
 			}
 
-			rawAudio, err = selectedEngineInstance.Generate(message.Voice.Model, payload)
-			releaseFunc()
+			if cacheEnabled {
+				// The cache keeps the audio as the engine generated it, not the copy resampled for playback
+				go func(character, text string) {
+					generated, err := audio.Concatenate(chunks...)
+					if err == nil {
+						err = cacheManager.CacheAudio(profileID, character, text, voice.Key(), generated)
+					}
+					if err != nil {
+						response.Warn("Background caching failed: %v\n", err)
+					}
+				}(message.Character, message.Text)
+			}
+		}
+	}
+
+	status.Set(status.Ready, "")
+	return nil
+}
+
+// GenerateScript plays or saves the entries of a parsed script. Plain entries go through GenerateSpeech, entries
+// with directives are rendered here so pauses, rate, volume and voice overrides are applied.
+func GenerateScript(ctx context.Context, entries []*script.Entry, saveOutput bool, profileID string) error {
+	profileManager := profile.GetManager()
+
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if !entry.HasDirectives() {
+			if err := GenerateSpeech(ctx, []util.CharacterMessage{entry.CharacterMessage}, saveOutput, profileID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		status.Set(status.Generating, "Generating Audio")
+
+		voice := &entry.Voice
+		if !entry.HasVoiceOverride() {
+			allocated, err := profileManager.GetOrAllocateVoice(profileID, entry.Character)
 			if err != nil {
 				return response.Err(err)
 			}
+			voice = allocated
+		}
 
-			audio.PlayRawAudioBytes(rawAudio)
+		rendered, err := script.Render(entry, func(text string) (*audio.Audio, error) {
+			return GenerateAudio(ctx, voice, text)
+		})
+		if err != nil {
+			return response.Err(err)
+		}
 
-			if cacheManager != nil && cacheManager.IsEnabled() {
-				go func(data []byte) {
-					voiceKey := fmt.Sprintf("%s:%s:%s", voice.Engine, voice.Model, voice.Voice)
-					if err := cacheManager.CacheAudio(profileID, message.Character, message.Text, voiceKey, data); err != nil {
-						response.Warn("Background caching failed: %v\n", err)
-					}
-				}(rawAudio)
-			}
+		if saveOutput {
+			err = saveRendered(entry.CharacterMessage, rendered)
+		} else {
+			status.Set(status.Playing, "")
+			err = playRendered(rendered)
+		}
+		if err != nil {
+			return response.Err(err)
 		}
 	}
 
@@ -105,7 +170,64 @@ func GenerateSpeech(messages []util.CharacterMessage, saveOutput bool, profileID
 	return nil
 }
 
-func GenerateAudio(voice *util.CharacterVoice, text string) (*audio.Audio, error) {
+// saveRendered writes the audio where the engines' Save would have put it, so the combine step picks it up
+func saveRendered(message util.CharacterMessage, rendered *audio.Audio) error {
+	err, expandedPath := util.ExpandPath(config.GetSettings().OutputPath)
+	if err != nil {
+		return err
+	}
+
+	wavData, err := rendered.ToWAV()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(util.GenerateFilename(message, fileIndex.Get(), expandedPath), wavData, 0644)
+}
+
+func playRendered(rendered *audio.Audio) error {
+	if err := rendered.Resample(22050); err != nil {
+		return err
+	}
+	if err := rendered.ChangeChannels(1); err != nil {
+		return err
+	}
+
+	pcmData, err := rendered.ToPCM()
+	if err != nil {
+		return err
+	}
+
+	audio.PlayRawAudioBytes(pcmData)
+	return nil
+}
+
+// GenerateCachedAudio generates a character's line with the voice given, going through the cache when the profile
+// uses it. cached reports whether the audio came from the cache.
+func GenerateCachedAudio(ctx context.Context, profileID, character string, voice *util.CharacterVoice, text string) (audioObj *audio.Audio, cached bool, err error) {
+	cacheManager := cache.GetManager()
+	cacheEnabled := cacheManager.IsEnabledFor(profileID)
+
+	if cacheEnabled {
+		if cachedAudio, found := cacheManager.GetCachedAudio(profileID, character, text, voice.Key()); found {
+			return cachedAudio, true, nil
+		}
+	}
+
+	audioObj, err = GenerateAudio(ctx, voice, text)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if cacheEnabled {
+		if err := cacheManager.CacheAudio(profileID, character, text, voice.Key(), audioObj); err != nil {
+			response.Warn("Failed to cache audio: %v\n", err)
+		}
+	}
+	return audioObj, false, nil
+}
+
+func GenerateAudio(ctx context.Context, voice *util.CharacterVoice, text string) (*audio.Audio, error) {
 	engineInstance, releaseFunc, ok := modelManager.GetEngineInstance(voice.Engine, voice.Model)
 	if !ok {
 		return nil, response.Err(fmt.Errorf("failed to get engine instance: %s/%s", voice.Engine, voice.Model))
@@ -119,12 +241,23 @@ func GenerateAudio(voice *util.CharacterVoice, text string) (*audio.Audio, error
 		Save:      false,
 	}
 
-	payload, err := preparePayload(message)
+	if ssml.IsSSML(text) {
+		var parts []*audio.Audio
+		for part, err := range generateSSML(ctx, engineInstance, voice, text) {
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, part)
+		}
+		return audio.Concatenate(parts...)
+	}
+
+	payload, err := preparePayload(message, 1)
 	if err != nil {
 		return nil, response.Err(err)
 	}
 
-	audioObj, err := engineInstance.GenerateAudio(message.Voice.Model, payload)
+	audioObj, err := engineInstance.GenerateAudio(ctx, message.Voice.Model, payload)
 	if err != nil {
 		return nil, response.Err(err)
 	}
@@ -132,9 +265,216 @@ func GenerateAudio(voice *util.CharacterVoice, text string) (*audio.Audio, error
 	return audioObj, nil
 }
 
-func GenerateRawAudio(voice *util.CharacterVoice, text string) ([]byte, error) {
+// GenerateAudioStream yields the audio for text as it is generated. Engines implementing engine.Streamer produce
+// their own chunks, every other engine is called once per sentence. The engine instance is held until the loop ends.
+func GenerateAudioStream(ctx context.Context, voice *util.CharacterVoice, text string) iter.Seq2[*audio.Audio, error] {
+	return func(yield func(*audio.Audio, error) bool) {
+		engineInstance, releaseFunc, ok := modelManager.GetEngineInstance(voice.Engine, voice.Model)
+		if !ok {
+			yield(nil, response.Err(fmt.Errorf("failed to get engine instance: %s/%s", voice.Engine, voice.Model)))
+			return
+		}
+		defer releaseFunc()
+
+		message := util.CharacterMessage{
+			Character: voice.Name,
+			Voice:     *voice,
+		}
+
+		if ssml.IsSSML(text) {
+			for part, err := range generateSSML(ctx, engineInstance, voice, text) {
+				if !yield(part, err) || err != nil {
+					return
+				}
+			}
+			return
+		}
+
+		if streamer, ok := engineInstance.(engine.Streamer); ok {
+			message.Text = text
+			payload, err := preparePayload(message, 1)
+			if err != nil {
+				yield(nil, response.Err(err))
+				return
+			}
+
+			for chunk, err := range streamer.GenerateAudioStream(ctx, voice.Model, payload) {
+				if !yield(chunk, err) || err != nil {
+					return
+				}
+			}
+			return
+		}
+
+		for _, sentence := range util.SplitSentences(text) {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			message.Text = sentence
+			payload, err := preparePayload(message, 1)
+			if err != nil {
+				yield(nil, response.Err(err))
+				return
+			}
+
+			audioObj, err := engineInstance.GenerateAudio(ctx, voice.Model, payload)
+			if err != nil {
+				yield(nil, response.Err(err))
+				return
+			}
+
+			if !yield(audioObj, nil) {
+				return
+			}
+		}
+	}
+}
+
+// playAudioStream plays each chunk while the next one is generated, resampled to the 22050 Hz mono
+// PlayRawAudioBytes expects. It returns the chunks that were played in the format the engine generated them in.
+// Cancelling ctx drops the chunks that haven't started playing.
+func playAudioStream(ctx context.Context, voice *util.CharacterVoice, text string) ([]*audio.Audio, error) {
+	queue := make(chan []byte, 8)
+	played := make(chan struct{})
+
+	go func() {
+		defer close(played)
+		for pcmData := range queue {
+			if ctx.Err() == nil {
+				audio.PlayRawAudioBytes(pcmData)
+			}
+		}
+	}()
+
+	var generated []*audio.Audio
+	var streamErr error
+
+	for chunk, err := range GenerateAudioStream(ctx, voice, text) {
+		if err == nil {
+			// Resampling and downmixing replace Data rather than write into it, so the copy keeps the original
+			generated = append(generated, &audio.Audio{Data: chunk.Data, Metadata: chunk.Metadata})
+			err = chunk.Resample(22050)
+		}
+		if err == nil {
+			err = chunk.ChangeChannels(1)
+		}
+
+		var pcmData []byte
+		if err == nil {
+			pcmData, err = chunk.ToPCM()
+		}
+		if err != nil {
+			streamErr = err
+			break
+		}
+
+		queue <- pcmData
+	}
+
+	close(queue)
+	<-played
+
+	return generated, streamErr
+}
+
+// generateSSML yields the audio of an SSML document. Google reads the document itself, every other engine gets one
+// request per segment: breaks are inserted as silence, and prosody goes through the engine's rate option or is
+// applied to the audio when there is none. Pitch is only honoured by Google.
+func generateSSML(ctx context.Context, engineInstance engine.Base, voice *util.CharacterVoice, document string) iter.Seq2[*audio.Audio, error] {
+	return func(yield func(*audio.Audio, error) bool) {
+		message := util.CharacterMessage{
+			Character: voice.Name,
+			Voice:     *voice,
+		}
+
+		if voice.Engine == string(Engines.Google) {
+			message.Text = document
+			payload, err := preparePayload(message, 1)
+			if err != nil {
+				yield(nil, response.Err(err))
+				return
+			}
+			yield(engineInstance.GenerateAudio(ctx, voice.Model, payload))
+			return
+		}
+
+		segments, err := ssml.Parse(document)
+		if err != nil {
+			yield(nil, response.Err(err))
+			return
+		}
+
+		// Silence takes the format of the speech, so breaks before the first segment wait for it
+		var leading time.Duration
+		var format *audio.AudioMetadata
+
+		for _, segment := range segments {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if segment.Text == "" {
+				if format == nil {
+					leading += segment.Break
+				} else if !yield(audio.NewSilence(segment.Break, format.SampleRate, format.Channels, format.BitDepth), nil) {
+					return
+				}
+				continue
+			}
+
+			message.Text = segment.Text
+			payload, err := preparePayload(message, segment.Rate)
+			if err != nil {
+				yield(nil, response.Err(err))
+				return
+			}
+
+			audioObj, err := engineInstance.GenerateAudio(ctx, voice.Model, payload)
+			if err == nil {
+				_, err = audioObj.ToPCM()
+			}
+			if err == nil && !hasRateOption(voice.Engine) {
+				err = audioObj.ChangeTempo(segment.Rate)
+			}
+			if err == nil {
+				err = audioObj.ApplyGain(segment.Volume)
+			}
+			if err != nil {
+				yield(nil, response.Err(err))
+				return
+			}
+
+			if format == nil {
+				format = &audioObj.Metadata
+				if leading > 0 && !yield(audio.NewSilence(leading, format.SampleRate, format.Channels, format.BitDepth), nil) {
+					return
+				}
+			}
+
+			if !yield(audioObj, nil) {
+				return
+			}
+		}
+	}
+}
+
+// hasRateOption reports whether preparePayload can pass a speaking rate to the engine
+func hasRateOption(engineID string) bool {
+	switch engineID {
+	case string(Engines.Piper):
+		return !config.GetEngine().Local.Piper.UseExecutable
+	case string(Engines.OpenAI), string(Engines.MsSapi5), string(Engines.Google):
+		return true
+	}
+	return false
+}
+
+func GenerateRawAudio(ctx context.Context, voice *util.CharacterVoice, text string) ([]byte, error) {
 	// Use new GenerateAudio function and convert to raw PCM for backward compatibility
-	audioObj, err := GenerateAudio(voice, text)
+	audioObj, err := GenerateAudio(ctx, voice, text)
 	if err != nil {
 		return nil, err
 	}
@@ -142,7 +482,9 @@ func GenerateRawAudio(voice *util.CharacterVoice, text string) ([]byte, error) {
 	return audioObj.ToPCM()
 }
 
-func preparePayload(message util.CharacterMessage) ([]byte, error) {
+// preparePayload builds the engine's request for the message. rate is the speaking rate, 1 leaves the engine's
+// default, and is only passed to engines for which hasRateOption is true.
+func preparePayload(message util.CharacterMessage, rate float64) ([]byte, error) {
 	switch message.Voice.Engine {
 	case string(Engines.Piper):
 		speakerID, _ := strconv.Atoi(message.Voice.Voice)
@@ -150,6 +492,9 @@ func preparePayload(message util.CharacterMessage) ([]byte, error) {
 			Text:      message.Text,
 			SpeakerID: speakerID,
 		}
+		if rate != 1 {
+			payload.LengthScale = float32(1 / rate)
+		}
 		result, err := json.Marshal(payload)
 		if err != nil {
 			return nil, err
@@ -163,6 +508,9 @@ func preparePayload(message util.CharacterMessage) ([]byte, error) {
 			Input: message.Text,
 			Voice: message.Voice.Voice,
 		}
+		if rate != 1 {
+			payload.Speed = min(max(rate, 0.25), 4)
+		}
 		return json.Marshal(payload)
 
 	case string(Engines.MsSapi4):
@@ -177,6 +525,10 @@ func preparePayload(message util.CharacterMessage) ([]byte, error) {
 			Text:  message.Text,
 			Voice: message.Voice.Voice,
 		}
+		if rate != 1 {
+			// Each SAPI rate step is about a tenth of the way to three times faster or slower
+			payload.Rate = int(math.Round(10 * math.Log(rate) / math.Log(3)))
+		}
 		return json.Marshal(payload)
 
 	case string(Engines.ElevenLabs):
@@ -189,7 +541,7 @@ func preparePayload(message util.CharacterMessage) ([]byte, error) {
 
 	case string(Engines.Google):
 		payload := google.GoogleRequest{
-			Input: google.Input{Text: message.Text},
+			Input: google.NewInput(message.Text),
 			Voice: google.VoiceSelectionParams{
 				Name:         message.Voice.Voice,
 				LanguageCode: "en-US",
@@ -197,7 +549,7 @@ func preparePayload(message util.CharacterMessage) ([]byte, error) {
 			},
 			AudioConfig: google.AudioConfig{
 				AudioEncoding: "WAV",
-				SpeakingRate:  1,
+				SpeakingRate:  min(max(rate, 0.25), 4),
 				Pitch:         0,
 			},
 		}
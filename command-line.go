@@ -4,13 +4,19 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
+	"nstudio/app/cache"
+	"nstudio/app/cache/warmup"
 	"nstudio/app/common/audio/player"
 	"nstudio/app/common/daemon"
 	"nstudio/app/common/util"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/log"
@@ -21,10 +27,19 @@ type commandLineArguments struct {
 	Port       int
 	Host       string
 	ConfigFile string
+	Socket     string
+	SocketMode os.FileMode
+	Inbox      string
+	Outbox     string
+	Poll       time.Duration
 	Status     bool
 	Stop       bool
 	Logs       bool
 	Play       string
+	Cache      string
+	Profile    string
+	Lines      string
+	Bundle     string
 	Help       bool
 }
 
@@ -33,10 +48,19 @@ func processCommandLine() commandLineArguments {
 	port := flag.Int("port", 8989, "Server port (for applicable modes)")
 	host := flag.String("host", "localhost", "Server host (for applicable modes)")
 	configFile := flag.String("config", "", "Path to custom config JSON file")
+	socket := flag.String("socket", daemon.GetSocketFilePath(), "Unix socket path (namedpipe mode)")
+	socketMode := flag.String("socket-mode", "0600", "Unix socket file permissions in octal (namedpipe mode)")
+	inbox := flag.String("inbox", "inbox", "Directory watched for .txt and .json request files (filesystem mode)")
+	outbox := flag.String("outbox", "outbox", "Directory generated audio and result files are written to (filesystem mode)")
+	poll := flag.Duration("poll-interval", time.Second, "How often the inbox is checked for new files (filesystem mode)")
 	status := flag.Bool("status", false, "Check server status")
 	stop := flag.Bool("stop", false, "Stop running server")
 	logs := flag.Bool("logs", false, "Show server log file location")
 	play := flag.String("play", "", "Play an audio file (supports WAV, FLAC, OGG, MP3)")
+	cacheCommand := flag.String("cache", "", "Audio cache command: verify, repair, warm, export or import")
+	profileID := flag.String("profile", "", "Profile to warm, export or import (cache warm, export and import)")
+	lines := flag.String("lines", "", "Script, or JSON list of character and text pairs, to warm (cache warm)")
+	bundle := flag.String("bundle", "", "Cache bundle to write (cache warm and export) or read (cache import)")
 	help := flag.Bool("help", false, "Show help")
 
 	flag.Parse()
@@ -46,13 +70,28 @@ func processCommandLine() commandLineArguments {
 		Port:       *port,
 		Host:       *host,
 		ConfigFile: *configFile,
+		Socket:     *socket,
+		Inbox:      *inbox,
+		Outbox:     *outbox,
+		Poll:       *poll,
 		Status:     *status,
 		Stop:       *stop,
 		Logs:       *logs,
 		Play:       *play,
+		Cache:      *cacheCommand,
+		Profile:    *profileID,
+		Lines:      *lines,
+		Bundle:     *bundle,
 		Help:       *help,
 	}
 
+	parsedMode, err := strconv.ParseUint(*socketMode, 8, 32)
+	if err != nil || parsedMode > 0777 {
+		fmt.Printf("Invalid --socket-mode %q, expected octal permissions such as 0600 or 0660\n", *socketMode)
+		os.Exit(1)
+	}
+	arguments.SocketMode = os.FileMode(parsedMode)
+
 	if arguments.Status {
 		handleStatus()
 		os.Exit(0)
@@ -73,6 +112,28 @@ func processCommandLine() commandLineArguments {
 		os.Exit(0)
 	}
 
+	switch arguments.Cache {
+	case "", "verify", "repair":
+	case "warm":
+		if arguments.Profile == "" || arguments.Lines == "" {
+			fmt.Println("--cache=warm needs --profile and --lines")
+			os.Exit(1)
+		}
+	case "export":
+		if arguments.Profile == "" || arguments.Bundle == "" {
+			fmt.Println("--cache=export needs --profile and --bundle")
+			os.Exit(1)
+		}
+	case "import":
+		if arguments.Bundle == "" {
+			fmt.Println("--cache=import needs --bundle")
+			os.Exit(1)
+		}
+	default:
+		fmt.Printf("Invalid --cache %q, expected verify, repair, warm, export or import\n", arguments.Cache)
+		os.Exit(1)
+	}
+
 	if arguments.Help {
 		log.Info(helpText)
 		os.Exit(0)
@@ -90,6 +151,7 @@ func handleStatus() {
 
 	if !running {
 		fmt.Println("🔴 Server is not running")
+		cleanupStaleSocket()
 		os.Exit(1)
 	}
 
@@ -106,6 +168,35 @@ func handleStatus() {
 	fmt.Printf("Version:            %s\n", statusInfo.Version)
 	fmt.Printf("Uptime:             %s\n", util.FormatDuration(uptime))
 	fmt.Printf("Processed Messages: %d\n", statusInfo.ProcessedMessages)
+	if statusInfo.QueuedJobs > 0 || statusInfo.RunningJobs > 0 {
+		fmt.Printf("Jobs:               %d running, %d queued\n", statusInfo.RunningJobs, statusInfo.QueuedJobs)
+	}
+
+	if statusInfo.SocketPath != "" {
+		state := "accepting connections"
+		if !daemon.SocketInUse(statusInfo.SocketPath) {
+			state = "not responding"
+		}
+		fmt.Printf("Socket:             %s (%s)\n", statusInfo.SocketPath, state)
+	}
+}
+
+// cleanupStaleSocket removes the socket left behind by a server that exited without shutting down
+func cleanupStaleSocket() {
+	statusInfo, err := daemon.ReadDaemonStatus()
+	if err != nil || statusInfo.SocketPath == "" {
+		return
+	}
+
+	removed, err := daemon.RemoveStaleSocket(statusInfo.SocketPath)
+	if err != nil {
+		fmt.Printf("Could not remove stale socket: %v\n", err)
+		return
+	}
+	if removed {
+		fmt.Printf("Removed stale socket: %s\n", statusInfo.SocketPath)
+	}
+	os.Remove(daemon.GetStatusFilePath())
 }
 
 func handleStop() {
@@ -117,9 +208,15 @@ func handleStop() {
 
 	if !running {
 		fmt.Println("Server is not running")
+		cleanupStaleSocket()
 		os.Exit(1)
 	}
 
+	socketPath := ""
+	if statusInfo, err := daemon.ReadDaemonStatus(); err == nil {
+		socketPath = statusInfo.SocketPath
+	}
+
 	fmt.Printf("Stopping server (PID: %d)...\n", pid)
 	err = daemon.StopDaemon()
 	if err != nil {
@@ -127,6 +224,22 @@ func handleStop() {
 		os.Exit(1)
 	}
 
+	if socketPath != "" {
+		// Give the server a moment to close its listener, which removes the socket itself
+		for attempt := 0; attempt < 30; attempt++ {
+			if _, err := os.Lstat(socketPath); os.IsNotExist(err) {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		if removed, err := daemon.RemoveStaleSocket(socketPath); err != nil {
+			fmt.Printf("Warning: could not remove socket: %v\n", err)
+		} else if removed {
+			fmt.Printf("Removed socket: %s\n", socketPath)
+		}
+	}
+
 	fmt.Println("Server stopped successfully")
 }
 
@@ -188,3 +301,138 @@ func handlePlay(filePath string) {
 		os.Exit(1)
 	}
 }
+
+// handleCache runs a --cache command other than warm. It needs the app initialized, for the cache's location.
+func handleCache(arguments commandLineArguments) {
+	switch arguments.Cache {
+	case "export":
+		handleCacheExport(arguments.Profile, arguments.Bundle)
+	case "import":
+		handleCacheImport(arguments.Profile, arguments.Bundle)
+	default:
+		handleCacheVerify(arguments.Cache == "repair")
+	}
+}
+
+// handleCacheVerify verifies or repairs the audio cache. Verifying exits with 1 when it finds problems.
+func handleCacheVerify(repair bool) {
+
+	report, err := cache.GetManager().Verify(repair)
+	if err != nil {
+		fmt.Printf("Error checking cache: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, problem := range report.Problems {
+		location := problem.Path
+		if problem.Profile != "" && problem.Character != "" {
+			location = fmt.Sprintf("%s/%s %s", problem.Profile, problem.Character, problem.Key)
+		}
+		fmt.Printf("%-8s %s: %s\n", problem.Kind, location, problem.Detail)
+	}
+
+	fmt.Printf("Checked %d audio files and %d references, found %d problems\n", report.Checked, report.References, len(report.Problems))
+	if report.Repaired && len(report.Problems) > 0 {
+		fmt.Println("Repaired the cache")
+	} else if len(report.Problems) > 0 {
+		fmt.Println("Use --cache=repair to fix them")
+		os.Exit(1)
+	}
+}
+
+// handleWarmup warms a profile's cache with the lines of a script or a .json list of character and text pairs,
+// then writes what it warmed to --bundle when it is set. It needs the engines registered.
+func handleWarmup(arguments commandLineArguments) {
+	data, err := os.ReadFile(arguments.Lines)
+	if err != nil {
+		fmt.Printf("Error reading lines: %v\n", err)
+		os.Exit(1)
+	}
+
+	request := warmup.Request{Profile: arguments.Profile}
+	if strings.EqualFold(filepath.Ext(arguments.Lines), ".json") {
+		if err := json.Unmarshal(data, &request.Lines); err != nil {
+			fmt.Printf("Error reading lines: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		request.Script = string(data)
+	}
+
+	warmed, err := warmup.GetManager().Run(request, func(progress *warmup.Warmup) {
+		fmt.Printf("\rWarming %d/%d lines", progress.Done, progress.Total)
+	})
+	if err != nil {
+		fmt.Printf("Error warming cache: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println()
+
+	for _, lineError := range warmed.Errors {
+		fmt.Printf("Line %d (%s): %s\n", lineError.Index+1, lineError.Character, lineError.Error)
+	}
+	fmt.Printf("Warm-up %s: %d generated, %d cached already, %d skipped, %d failed\n",
+		warmed.State, warmed.Generated, warmed.Cached, warmed.Skipped, len(warmed.Errors))
+	if warmed.State != warmup.Completed {
+		os.Exit(1)
+	}
+
+	if arguments.Bundle != "" {
+		writeBundleFile(arguments.Bundle, func(writer io.Writer) (*cache.BundleSummary, error) {
+			return warmup.GetManager().ExportBundle(warmed.ID, writer)
+		})
+	}
+}
+
+func handleCacheExport(profileID, bundlePath string) {
+	writeBundleFile(bundlePath, func(writer io.Writer) (*cache.BundleSummary, error) {
+		return cache.GetManager().ExportBundle(writer, profileID, nil)
+	})
+}
+
+func writeBundleFile(bundlePath string, export func(writer io.Writer) (*cache.BundleSummary, error)) {
+	file, err := os.Create(bundlePath)
+	if err != nil {
+		fmt.Printf("Error creating bundle: %v\n", err)
+		os.Exit(1)
+	}
+
+	summary, err := export(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(bundlePath)
+		fmt.Printf("Error exporting cache: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Exported %d lines of %d characters (%d bytes) to %s\n", summary.Entries, summary.Characters, summary.Bytes, bundlePath)
+}
+
+// handleCacheImport imports a bundle into profileID, or into the profile it was exported from when it is empty
+func handleCacheImport(profileID, bundlePath string) {
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		fmt.Printf("Error opening bundle: %v\n", err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		fmt.Printf("Error opening bundle: %v\n", err)
+		os.Exit(1)
+	}
+
+	summary, err := cache.GetManager().ImportBundle(file, info.Size(), profileID)
+	if err != nil {
+		fmt.Printf("Error importing cache: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Imported %d lines of %d characters (%d bytes) into profile '%s'\n", summary.Entries, summary.Characters, summary.Bytes, summary.Profile)
+	if summary.Skipped > 0 {
+		fmt.Printf("Skipped %d lines whose audio was missing or damaged\n", summary.Skipped)
+	}
+}
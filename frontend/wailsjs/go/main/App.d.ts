@@ -2,8 +2,12 @@
 // This file is automatically generated. DO NOT EDIT
 import {config} from '../models';
 
+export function CancelGeneration():Promise<void>;
+
 export function CreateProfile(arg1:string,arg2:string,arg3:string):Promise<string>;
 
+export function CreateProject(arg1:string,arg2:string,arg3:string):Promise<string>;
+
 export function DeleteProfile(arg1:string):Promise<void>;
 
 export function EventSubscribe(arg1:string,arg2:any):Promise<void>;
@@ -42,14 +46,20 @@ export function GetSettings():Promise<config.Base>;
 
 export function GetStatus():Promise<string>;
 
+export function ImportScript(arg1:string,arg2:string):Promise<void>;
+
 export function IsPiperGPUAvailable():Promise<boolean>;
 
+export function OpenProject(arg1:string):Promise<string>;
+
 export function PiperDeleteModel(arg1:string):Promise<string>;
 
 export function PiperDownloadModel(arg1:string):Promise<string>;
 
 export function PiperFetchAvailableModels():Promise<string>;
 
+export function PlanScript(arg1:string,arg2:string):Promise<string>;
+
 export function Play(arg1:string,arg2:boolean,arg3:string,arg4:string):Promise<void>;
 
 export function ProcessScript(arg1:string,arg2:string):Promise<void>;
@@ -58,16 +68,22 @@ export function RefreshModels():Promise<void>;
 
 export function ReloadVoicePacks():Promise<void>;
 
+export function RenderProject(arg1:string,arg2:Array<string>,arg3:boolean,arg4:boolean):Promise<string>;
+
 export function SaveProfileSettings(arg1:string,arg2:string):Promise<void>;
 
 export function SaveProfileVoices(arg1:string,arg2:string):Promise<void>;
 
+export function SaveProject(arg1:string,arg2:string,arg3:string):Promise<string>;
+
 export function SaveSettings(arg1:config.Base):Promise<void>;
 
 export function SelectDirectory(arg1:string):Promise<string>;
 
 export function SelectFile(arg1:string):Promise<string>;
 
+export function SelectProjectTake(arg1:string,arg2:string,arg3:number):Promise<string>;
+
 export function StartDaemonServer(arg1:string,arg2:number,arg3:string,arg4:string):Promise<string>;
 
 export function StopDaemonServer():Promise<string>;
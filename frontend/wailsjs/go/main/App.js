@@ -2,10 +2,18 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function CancelGeneration() {
+  return window['go']['main']['App']['CancelGeneration']();
+}
+
 export function CreateProfile(arg1, arg2, arg3) {
   return window['go']['main']['App']['CreateProfile'](arg1, arg2, arg3);
 }
 
+export function CreateProject(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CreateProject'](arg1, arg2, arg3);
+}
+
 export function DeleteProfile(arg1) {
   return window['go']['main']['App']['DeleteProfile'](arg1);
 }
@@ -82,10 +90,18 @@ export function GetStatus() {
   return window['go']['main']['App']['GetStatus']();
 }
 
+export function ImportScript(arg1, arg2) {
+  return window['go']['main']['App']['ImportScript'](arg1, arg2);
+}
+
 export function IsPiperGPUAvailable() {
   return window['go']['main']['App']['IsPiperGPUAvailable']();
 }
 
+export function OpenProject(arg1) {
+  return window['go']['main']['App']['OpenProject'](arg1);
+}
+
 export function PiperDeleteModel(arg1) {
   return window['go']['main']['App']['PiperDeleteModel'](arg1);
 }
@@ -98,6 +114,10 @@ export function PiperFetchAvailableModels() {
   return window['go']['main']['App']['PiperFetchAvailableModels']();
 }
 
+export function PlanScript(arg1, arg2) {
+  return window['go']['main']['App']['PlanScript'](arg1, arg2);
+}
+
 export function Play(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['Play'](arg1, arg2, arg3, arg4);
 }
@@ -114,6 +134,10 @@ export function ReloadVoicePacks() {
   return window['go']['main']['App']['ReloadVoicePacks']();
 }
 
+export function RenderProject(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['RenderProject'](arg1, arg2, arg3, arg4);
+}
+
 export function SaveProfileSettings(arg1, arg2) {
   return window['go']['main']['App']['SaveProfileSettings'](arg1, arg2);
 }
@@ -122,6 +146,10 @@ export function SaveProfileVoices(arg1, arg2) {
   return window['go']['main']['App']['SaveProfileVoices'](arg1, arg2);
 }
 
+export function SaveProject(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SaveProject'](arg1, arg2, arg3);
+}
+
 export function SaveSettings(arg1) {
   return window['go']['main']['App']['SaveSettings'](arg1);
 }
@@ -134,6 +162,10 @@ export function SelectFile(arg1) {
   return window['go']['main']['App']['SelectFile'](arg1);
 }
 
+export function SelectProjectTake(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SelectProjectTake'](arg1, arg2, arg3);
+}
+
 export function StartDaemonServer(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['StartDaemonServer'](arg1, arg2, arg3, arg4);
 }
@@ -116,6 +116,20 @@ export namespace config {
 	        this.adminKey = source["adminKey"];
 	    }
 	}
+	export class CueAlignmentSettings {
+	    enabled: boolean;
+	    maxSpeedup: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CueAlignmentSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.maxSpeedup = source["maxSpeedup"];
+	    }
+	}
 	export class Info {
 	    name: string;
 	    version: string;
@@ -339,8 +353,10 @@ export namespace config {
 	export class Settings {
 	    outputType: number;
 	    outputPath: string;
+	    projectsPath?: string;
 	    debug: boolean;
 	    audioCache?: AudioCacheSettings;
+	    cueAlignment?: CueAlignmentSettings;
 	    server?: ServerSettings;
 	
 	    static createFrom(source: any = {}) {
@@ -351,8 +367,10 @@ export namespace config {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.outputType = source["outputType"];
 	        this.outputPath = source["outputPath"];
+	        this.projectsPath = source["projectsPath"];
 	        this.debug = source["debug"];
 	        this.audioCache = this.convertValues(source["audioCache"], AudioCacheSettings);
+	        this.cueAlignment = this.convertValues(source["cueAlignment"], CueAlignmentSettings);
 	        this.server = this.convertValues(source["server"], ServerSettings);
 	    }
 	
@@ -24,9 +24,19 @@ func main() {
 		issue.Panic("Failed to initialize app", err)
 	}
 
+	if arguments.Cache != "" && arguments.Cache != "warm" {
+		handleCache(arguments)
+		return
+	}
+
 	modelManager.Initialize(false)
 	registerEngines()
 
+	if arguments.Cache == "warm" {
+		handleWarmup(arguments)
+		return
+	}
+
 	if arguments.Mode == "gui" {
 		fmt.Println("Error: GUI mode not supported in CLI build.")
 		os.Exit(1)
@@ -57,6 +67,12 @@ func startCmdServer(options commandLineArguments) {
 		Port:       options.Port,
 		Host:       options.Host,
 		ConfigFile: options.ConfigFile,
+		SocketPath: options.Socket,
+		SocketMode: options.SocketMode,
+
+		Inbox:        options.Inbox,
+		Outbox:       options.Outbox,
+		PollInterval: options.Poll,
 	}
 
 	pidFile := daemon.GetPidFilePath()
@@ -80,7 +96,13 @@ func startCmdServer(options commandLineArguments) {
 		os.Remove(daemon.GetStatusFilePath())
 	}()
 
-	fmt.Printf("Starting %s server on %s:%d (PID: %d)\n", serverConfig.Mode, options.Host, options.Port, os.Getpid())
+	if serverConfig.Mode == server.ModeNamedPipe {
+		fmt.Printf("Starting %s server on %s (PID: %d)\n", serverConfig.Mode, options.Socket, os.Getpid())
+	} else if serverConfig.Mode == server.ModeFileSystem {
+		fmt.Printf("Starting %s server on %s (PID: %d)\n", serverConfig.Mode, options.Inbox, os.Getpid())
+	} else {
+		fmt.Printf("Starting %s server on %s:%d (PID: %d)\n", serverConfig.Mode, options.Host, options.Port, os.Getpid())
+	}
 
 	err := server.StartServer(serverConfig)
 	if err != nil {
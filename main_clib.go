@@ -4,20 +4,39 @@ package main
 
 /*
 #include <stdlib.h>
+
+// NStudioChunkCallback receives each streamed chunk. data and metaJSON are only valid during the call.
+// Returning non-zero stops generation.
+typedef int (*NStudioChunkCallback)(const char *data, int len, const char *metaJSON, void *userData);
+
+static int callChunkCallback(NStudioChunkCallback callback, const char *data, int len, const char *metaJSON, void *userData) {
+	return callback(data, len, metaJSON, userData);
+}
 */
 import "C"
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"nstudio/app/cache"
+	"nstudio/app/cache/warmup"
 	"nstudio/app/common/audio"
+	"nstudio/app/common/response"
+	"nstudio/app/common/script"
+	"nstudio/app/common/ssml"
 	"nstudio/app/common/util"
 	"nstudio/app/config"
+	"nstudio/app/project"
 	"nstudio/app/tts"
 	ttsEngine "nstudio/app/tts/engine"
 	"nstudio/app/tts/modelManager"
 	"nstudio/app/tts/profile"
+	"os"
 	"sync"
+	"time"
 	"unsafe"
 )
 
@@ -49,6 +68,18 @@ func checkInit() bool {
 	return true
 }
 
+// checkSSML rejects malformed SSML as a bad request instead of a generation failure.
+func checkSSML(text string) bool {
+	if !ssml.IsSSML(text) {
+		return true
+	}
+	if _, err := ssml.Parse(text); err != nil {
+		setLastError(-2, err.Error())
+		return false
+	}
+	return true
+}
+
 // returnJSON marshals v to JSON, copies to C-heap, writes to *out.
 func returnJSON(v interface{}, out **C.char) C.int {
 	data, err := json.Marshal(v)
@@ -155,11 +186,12 @@ func NStudioGenerate(requestJSON *C.char, outData **C.char, outLen *C.int, outMe
 	}
 
 	type genRequest struct {
-		Engine string `json:"engine"`
-		Model  string `json:"model"`
-		Voice  string `json:"voice"`
-		Text   string `json:"text"`
-		Format string `json:"format"`
+		Engine  string                 `json:"engine"`
+		Model   string                 `json:"model"`
+		Voice   string                 `json:"voice"`
+		Text    string                 `json:"text"`
+		Format  string                 `json:"format"`
+		Options map[string]interface{} `json:"options"`
 	}
 
 	var req genRequest
@@ -172,11 +204,20 @@ func NStudioGenerate(requestJSON *C.char, outData **C.char, outLen *C.int, outMe
 		setLastError(-2, "engine, model, voice, and text are required")
 		return -2
 	}
+	if !checkSSML(req.Text) {
+		return -2
+	}
 
 	if req.Format == "" {
 		req.Format = "wav"
 	}
 
+	encodeOptions, err := audio.ParseEncodeOptions(req.Options)
+	if err != nil {
+		setLastError(-2, fmt.Sprintf("invalid encoding options: %v", err))
+		return -2
+	}
+
 	voice := &util.CharacterVoice{
 		Name:   "nstudio",
 		Engine: req.Engine,
@@ -184,13 +225,16 @@ func NStudioGenerate(requestJSON *C.char, outData **C.char, outLen *C.int, outMe
 		Voice:  req.Voice,
 	}
 
-	audioObj, err := tts.GenerateAudio(voice, req.Text)
+	ctx, done := tts.Track(context.Background(), "")
+	defer done()
+
+	audioObj, err := tts.GenerateAudio(ctx, voice, req.Text)
 	if err != nil {
 		setLastError(-4, fmt.Sprintf("generation failed: %v", err))
 		return -4
 	}
 
-	outputBytes, err := audioObj.ToFormat(req.Format)
+	outputBytes, err := audioObj.ToFormatWithOptions(req.Format, encodeOptions)
 	if err != nil {
 		setLastError(-4, fmt.Sprintf("format conversion failed: %v", err))
 		return -4
@@ -233,10 +277,11 @@ func NStudioGenerateForProfile(requestJSON *C.char, outData **C.char, outLen *C.
 	}
 
 	type profileRequest struct {
-		Profile   string `json:"profile"`
-		Character string `json:"character"`
-		Text      string `json:"text"`
-		Format    string `json:"format"`
+		Profile   string                 `json:"profile"`
+		Character string                 `json:"character"`
+		Text      string                 `json:"text"`
+		Format    string                 `json:"format"`
+		Options   map[string]interface{} `json:"options"`
 	}
 
 	var req profileRequest
@@ -249,11 +294,20 @@ func NStudioGenerateForProfile(requestJSON *C.char, outData **C.char, outLen *C.
 		setLastError(-2, "profile, character, and text are required")
 		return -2
 	}
+	if !checkSSML(req.Text) {
+		return -2
+	}
 
 	if req.Format == "" {
 		req.Format = "wav"
 	}
 
+	encodeOptions, err := audio.ParseEncodeOptions(req.Options)
+	if err != nil {
+		setLastError(-2, fmt.Sprintf("invalid encoding options: %v", err))
+		return -2
+	}
+
 	manager := profile.GetManager()
 	voice, err := manager.GetOrAllocateVoice(req.Profile, req.Character)
 	if err != nil {
@@ -261,13 +315,16 @@ func NStudioGenerateForProfile(requestJSON *C.char, outData **C.char, outLen *C.
 		return -5
 	}
 
-	audioObj, err := tts.GenerateAudio(voice, req.Text)
+	ctx, done := tts.Track(context.Background(), "")
+	defer done()
+
+	audioObj, _, err := tts.GenerateCachedAudio(ctx, req.Profile, req.Character, voice, req.Text)
 	if err != nil {
 		setLastError(-4, fmt.Sprintf("generation failed: %v", err))
 		return -4
 	}
 
-	outputBytes, err := audioObj.ToFormat(req.Format)
+	outputBytes, err := audioObj.ToFormatWithOptions(req.Format, encodeOptions)
 	if err != nil {
 		setLastError(-4, fmt.Sprintf("format conversion failed: %v", err))
 		return -4
@@ -294,6 +351,212 @@ func NStudioGenerateForProfile(requestJSON *C.char, outData **C.char, outLen *C.
 	return 0
 }
 
+// NStudioCancelGeneration stops every generation, streaming or not, that is running on another thread. The
+// cancelled calls return -4. Returns how many were cancelled.
+//
+//export NStudioCancelGeneration
+func NStudioCancelGeneration() C.int {
+	return C.int(tts.CancelAll())
+}
+
+// ---------------------------------------------------------------------------
+// Streaming TTS Generation
+// ---------------------------------------------------------------------------
+
+// NStudioGenerateStream takes the same request as NStudioGenerate and passes 16-bit PCM to callback as each
+// chunk is generated, followed by an empty chunk whose metadata has "final": true.
+//
+//export NStudioGenerateStream
+func NStudioGenerateStream(requestJSON *C.char, callback C.NStudioChunkCallback, userData unsafe.Pointer) (errCode C.int) {
+	defer func() {
+		if r := recover(); r != nil {
+			setLastError(-99, fmt.Sprintf("panic: %v", r))
+			errCode = -99
+		}
+	}()
+
+	if !checkInit() {
+		return -1
+	}
+
+	type genRequest struct {
+		Engine string `json:"engine"`
+		Model  string `json:"model"`
+		Voice  string `json:"voice"`
+		Text   string `json:"text"`
+		Format string `json:"format"`
+	}
+
+	var req genRequest
+	if err := json.Unmarshal([]byte(C.GoString(requestJSON)), &req); err != nil {
+		setLastError(-2, fmt.Sprintf("invalid request JSON: %v", err))
+		return -2
+	}
+
+	if req.Engine == "" || req.Model == "" || req.Voice == "" || req.Text == "" {
+		setLastError(-2, "engine, model, voice, and text are required")
+		return -2
+	}
+	if !checkSSML(req.Text) {
+		return -2
+	}
+
+	voice := &util.CharacterVoice{
+		Name:   "nstudio",
+		Engine: req.Engine,
+		Model:  req.Model,
+		Voice:  req.Voice,
+	}
+
+	return streamChunks(voice, "", "", req.Text, req.Format, callback, userData)
+}
+
+// NStudioGenerateStreamForProfile takes the same request as NStudioGenerateForProfile and streams like
+// NStudioGenerateStream.
+//
+//export NStudioGenerateStreamForProfile
+func NStudioGenerateStreamForProfile(requestJSON *C.char, callback C.NStudioChunkCallback, userData unsafe.Pointer) (errCode C.int) {
+	defer func() {
+		if r := recover(); r != nil {
+			setLastError(-99, fmt.Sprintf("panic: %v", r))
+			errCode = -99
+		}
+	}()
+
+	if !checkInit() {
+		return -1
+	}
+
+	type profileRequest struct {
+		Profile   string `json:"profile"`
+		Character string `json:"character"`
+		Text      string `json:"text"`
+		Format    string `json:"format"`
+	}
+
+	var req profileRequest
+	if err := json.Unmarshal([]byte(C.GoString(requestJSON)), &req); err != nil {
+		setLastError(-2, fmt.Sprintf("invalid request JSON: %v", err))
+		return -2
+	}
+
+	if req.Profile == "" || req.Character == "" || req.Text == "" {
+		setLastError(-2, "profile, character, and text are required")
+		return -2
+	}
+	if !checkSSML(req.Text) {
+		return -2
+	}
+
+	manager := profile.GetManager()
+	voice, err := manager.GetOrAllocateVoice(req.Profile, req.Character)
+	if err != nil {
+		setLastError(-5, fmt.Sprintf("profile voice allocation failed: %v", err))
+		return -5
+	}
+
+	return streamChunks(voice, req.Profile, req.Character, req.Text, req.Format, callback, userData)
+}
+
+// streamChunks runs tts.GenerateAudioStream and hands each chunk to the C callback. A callback that returns
+// non-zero stops generation without it counting as an error. With a profile, a cached line is sent as a single
+// chunk and a generated one is cached once it was streamed completely.
+func streamChunks(voice *util.CharacterVoice, profileID, character, text string, format string, callback C.NStudioChunkCallback, userData unsafe.Pointer) C.int {
+	if callback == nil {
+		setLastError(-2, "callback is required")
+		return -2
+	}
+
+	if format != "" && format != "pcm" && format != "pcm_s16le" {
+		setLastError(-2, "streaming only supports pcm output")
+		return -2
+	}
+
+	type chunkMeta struct {
+		Sequence   int    `json:"sequence"`
+		SampleRate int    `json:"sampleRate"`
+		Channels   int    `json:"channels"`
+		BitDepth   int    `json:"bitDepth"`
+		Format     string `json:"format"`
+		Final      bool   `json:"final"`
+	}
+
+	send := func(data []byte, meta chunkMeta) bool {
+		metaJSON, _ := json.Marshal(meta)
+		cMeta := C.CString(string(metaJSON))
+		defer C.free(unsafe.Pointer(cMeta))
+
+		var cData unsafe.Pointer
+		if len(data) > 0 {
+			cData = C.CBytes(data)
+			defer C.free(cData)
+		}
+
+		return C.callChunkCallback(callback, (*C.char)(cData), C.int(len(data)), cMeta, userData) == 0
+	}
+
+	meta := chunkMeta{Format: "pcm"}
+
+	cacheManager := cache.GetManager()
+	cacheEnabled := profileID != "" && cacheManager.IsEnabledFor(profileID)
+
+	if cacheEnabled {
+		if cachedAudio, found := cacheManager.GetCachedAudio(profileID, character, text, voice.Key()); found {
+			meta.SampleRate = cachedAudio.Metadata.SampleRate
+			meta.Channels = cachedAudio.Metadata.Channels
+			meta.BitDepth = cachedAudio.Metadata.BitDepth
+
+			if send(cachedAudio.Data, meta) {
+				meta.Sequence++
+				meta.Final = true
+				send(nil, meta)
+			}
+			return 0
+		}
+	}
+
+	ctx, done := tts.Track(context.Background(), "")
+	defer done()
+
+	var generated bytes.Buffer
+	for chunk, err := range tts.GenerateAudioStream(ctx, voice, text) {
+		if err != nil {
+			setLastError(-4, fmt.Sprintf("generation failed: %v", err))
+			return -4
+		}
+
+		pcmData, err := chunk.ToPCM()
+		if err != nil {
+			setLastError(-4, fmt.Sprintf("format conversion failed: %v", err))
+			return -4
+		}
+
+		meta.SampleRate = chunk.Metadata.SampleRate
+		meta.Channels = chunk.Metadata.Channels
+		meta.BitDepth = chunk.Metadata.BitDepth
+
+		if cacheEnabled {
+			generated.Write(pcmData)
+		}
+
+		if !send(pcmData, meta) {
+			return 0
+		}
+		meta.Sequence++
+	}
+
+	meta.Final = true
+	send(nil, meta)
+
+	if cacheEnabled {
+		generatedAudio := audio.NewAudioFromPCM(generated.Bytes(), meta.SampleRate, meta.Channels, meta.BitDepth)
+		if err := cacheManager.CacheAudio(profileID, character, text, voice.Key(), generatedAudio); err != nil {
+			response.Warn("Failed to cache audio: %v\n", err)
+		}
+	}
+	return 0
+}
+
 // ---------------------------------------------------------------------------
 // Engine / Voice Discovery
 // ---------------------------------------------------------------------------
@@ -401,6 +664,76 @@ func NStudioGetAllVoices(outJSON **C.char) C.int {
 	return returnJSON(result, outJSON)
 }
 
+// ---------------------------------------------------------------------------
+// Scripts
+// ---------------------------------------------------------------------------
+
+// NStudioParseScript parses a script into {success, entries, script, errors}. It does not need NStudioInit.
+// When the script has errors it returns -2, and outJSON still holds every error with its line.
+//
+//export NStudioParseScript
+func NStudioParseScript(scriptText *C.char, outJSON **C.char) (errCode C.int) {
+	defer func() {
+		if r := recover(); r != nil {
+			setLastError(-99, fmt.Sprintf("panic: %v", r))
+			errCode = -99
+		}
+	}()
+
+	parsed, err := script.Parse(C.GoString(scriptText))
+
+	var scriptErrors script.Errors
+	if err != nil && !errors.As(err, &scriptErrors) {
+		setLastError(-2, fmt.Sprintf("parse failed: %v", err))
+		return -2
+	}
+	if scriptErrors == nil {
+		scriptErrors = script.Errors{}
+	}
+
+	result := struct {
+		Success bool           `json:"success"`
+		Entries int            `json:"entries"`
+		Script  *script.Script `json:"script"`
+		Errors  []script.Error `json:"errors"`
+	}{
+		Success: len(scriptErrors) == 0,
+		Entries: len(parsed.Entries()),
+		Script:  parsed,
+		Errors:  scriptErrors,
+	}
+
+	if code := returnJSON(result, outJSON); code != 0 {
+		return code
+	}
+	if !result.Success {
+		setLastError(-2, scriptErrors.Error())
+		return -2
+	}
+	return 0
+}
+
+// NStudioImportScript converts a Fountain, SRT, WebVTT or CSV file into a JSON array of messages, each with its cue
+// ID and timing. format is a file name or extension. It does not need NStudioInit.
+//
+//export NStudioImportScript
+func NStudioImportScript(format *C.char, content *C.char, outJSON **C.char) (errCode C.int) {
+	defer func() {
+		if r := recover(); r != nil {
+			setLastError(-99, fmt.Sprintf("panic: %v", r))
+			errCode = -99
+		}
+	}()
+
+	messages, err := script.Import(C.GoString(format), []byte(C.GoString(content)), script.ImportOptions{})
+	if err != nil {
+		setLastError(-2, fmt.Sprintf("import failed: %v", err))
+		return -2
+	}
+
+	return returnJSON(messages, outJSON)
+}
+
 // ---------------------------------------------------------------------------
 // Configuration & Settings Schema
 // ---------------------------------------------------------------------------
@@ -465,6 +798,157 @@ func NStudioSetConfigValue(path *C.char, valueJSON *C.char) C.int {
 	return 0
 }
 
+// ---------------------------------------------------------------------------
+// Projects
+// ---------------------------------------------------------------------------
+
+// NStudioCreateProject creates a project in an empty or missing directory and returns it as JSON. It does not need
+// NStudioInit.
+//
+//export NStudioCreateProject
+func NStudioCreateProject(path *C.char, name *C.char, profileID *C.char, outJSON **C.char) (errCode C.int) {
+	defer func() {
+		if r := recover(); r != nil {
+			setLastError(-99, fmt.Sprintf("panic: %v", r))
+			errCode = -99
+		}
+	}()
+
+	created, err := project.Create(C.GoString(path), C.GoString(name), C.GoString(profileID))
+	if err != nil {
+		setLastError(-2, fmt.Sprintf("create project failed: %v", err))
+		return -2
+	}
+
+	return returnJSON(created, outJSON)
+}
+
+// NStudioOpenProject returns the project in a directory as JSON: its path, script and manifest. It does not need
+// NStudioInit.
+//
+//export NStudioOpenProject
+func NStudioOpenProject(path *C.char, outJSON **C.char) (errCode C.int) {
+	defer func() {
+		if r := recover(); r != nil {
+			setLastError(-99, fmt.Sprintf("panic: %v", r))
+			errCode = -99
+		}
+	}()
+
+	opened, err := project.Open(C.GoString(path))
+	if err != nil {
+		setLastError(-2, fmt.Sprintf("open project failed: %v", err))
+		return -2
+	}
+
+	return returnJSON(opened, outJSON)
+}
+
+// NStudioSaveProject replaces the script of a project, and its profile when profileID isn't empty. It does not need
+// NStudioInit.
+//
+//export NStudioSaveProject
+func NStudioSaveProject(path *C.char, scriptText *C.char, profileID *C.char, outJSON **C.char) (errCode C.int) {
+	defer func() {
+		if r := recover(); r != nil {
+			setLastError(-99, fmt.Sprintf("panic: %v", r))
+			errCode = -99
+		}
+	}()
+
+	opened, err := project.Open(C.GoString(path))
+	if err != nil {
+		setLastError(-2, fmt.Sprintf("open project failed: %v", err))
+		return -2
+	}
+
+	opened.Script = C.GoString(scriptText)
+	if id := C.GoString(profileID); id != "" {
+		opened.Manifest.Profile = id
+	}
+
+	if err := opened.Save(); err != nil {
+		setLastError(-2, fmt.Sprintf("save project failed: %v", err))
+		return -2
+	}
+
+	return returnJSON(opened, outJSON)
+}
+
+// NStudioRenderProject generates the lines that changed since the last render and rebuilds the combined file.
+// optionsJSON is {"retake": [line IDs], "force": bool, "dryRun": bool} and may be NULL. A dry run only reports
+// which lines would be generated. Script errors return -2.
+//
+//export NStudioRenderProject
+func NStudioRenderProject(path *C.char, optionsJSON *C.char, outJSON **C.char) (errCode C.int) {
+	defer func() {
+		if r := recover(); r != nil {
+			setLastError(-99, fmt.Sprintf("panic: %v", r))
+			errCode = -99
+		}
+	}()
+
+	if !checkInit() {
+		return -1
+	}
+
+	var options project.RenderOptions
+	if optionsJSON != nil {
+		if err := json.Unmarshal([]byte(C.GoString(optionsJSON)), &options); err != nil {
+			setLastError(-2, fmt.Sprintf("invalid options JSON: %v", err))
+			return -2
+		}
+	}
+
+	opened, err := project.Open(C.GoString(path))
+	if err != nil {
+		setLastError(-2, fmt.Sprintf("open project failed: %v", err))
+		return -2
+	}
+
+	ctx, done := tts.Track(context.Background(), "")
+	defer done()
+
+	result, err := opened.Render(ctx, options)
+	if err != nil {
+		var scriptErrors script.Errors
+		if errors.As(err, &scriptErrors) {
+			setLastError(-2, scriptErrors.Error())
+			return -2
+		}
+		setLastError(-4, fmt.Sprintf("render project failed: %v", err))
+		return -4
+	}
+
+	return returnJSON(result, outJSON)
+}
+
+// NStudioSelectProjectTake makes an earlier take of a line the one used in the combined file. It does not need
+// NStudioInit.
+//
+//export NStudioSelectProjectTake
+func NStudioSelectProjectTake(path *C.char, lineID *C.char, take C.int, outJSON **C.char) (errCode C.int) {
+	defer func() {
+		if r := recover(); r != nil {
+			setLastError(-99, fmt.Sprintf("panic: %v", r))
+			errCode = -99
+		}
+	}()
+
+	opened, err := project.Open(C.GoString(path))
+	if err != nil {
+		setLastError(-2, fmt.Sprintf("open project failed: %v", err))
+		return -2
+	}
+
+	if err := opened.SelectTake(C.GoString(lineID), int(take)); err != nil {
+		setLastError(-2, fmt.Sprintf("select take failed: %v", err))
+		return -2
+	}
+
+	return returnJSON(opened, outJSON)
+}
+
 // ---------------------------------------------------------------------------
 // Profiles
 // ---------------------------------------------------------------------------
@@ -577,6 +1061,240 @@ func NStudioSetProfileVoice(profileID *C.char, character *C.char, voiceJSON *C.c
 	return 0
 }
 
+// ---------------------------------------------------------------------------
+// Audio Cache
+// ---------------------------------------------------------------------------
+
+// NStudioGetCacheStats returns the entries, size and hit ratio of every profile's cache, or of one profile when
+// profileID isn't NULL or empty.
+//
+//export NStudioGetCacheStats
+func NStudioGetCacheStats(profileID *C.char, outJSON **C.char) C.int {
+	if !checkInit() {
+		return -1
+	}
+
+	var id string
+	if profileID != nil {
+		id = C.GoString(profileID)
+	}
+
+	statistics, err := cache.GetManager().Stats(id)
+	if err != nil {
+		setLastError(-7, fmt.Sprintf("get cache stats failed: %v", err))
+		return -7
+	}
+
+	return returnJSON(statistics, outJSON)
+}
+
+// NStudioClearCache removes cached lines and returns {entries, bytes} removed. requestJSON is
+// {"profile": "", "character": "", "olderThanDays": 0}, empty fields match every line, and may be NULL to clear
+// the whole cache.
+//
+//export NStudioClearCache
+func NStudioClearCache(requestJSON *C.char, outJSON **C.char) C.int {
+	if !checkInit() {
+		return -1
+	}
+
+	type clearRequest struct {
+		Profile       string  `json:"profile"`
+		Character     string  `json:"character"`
+		OlderThanDays float64 `json:"olderThanDays"`
+	}
+
+	var req clearRequest
+	if requestJSON != nil {
+		if err := json.Unmarshal([]byte(C.GoString(requestJSON)), &req); err != nil {
+			setLastError(-2, fmt.Sprintf("invalid request JSON: %v", err))
+			return -2
+		}
+	}
+	if req.OlderThanDays < 0 {
+		setLastError(-2, "olderThanDays must be a positive number of days")
+		return -2
+	}
+
+	removed, err := cache.GetManager().Clear(cache.ClearOptions{
+		Profile:   req.Profile,
+		Character: req.Character,
+		OlderThan: time.Duration(req.OlderThanDays * float64(24*time.Hour)),
+	})
+	if err != nil {
+		setLastError(-7, fmt.Sprintf("clear cache failed: %v", err))
+		return -7
+	}
+
+	return returnJSON(removed, outJSON)
+}
+
+// NStudioEvictCache applies the cache's maximum size and age right away and returns {entries, bytes} removed.
+//
+//export NStudioEvictCache
+func NStudioEvictCache(outJSON **C.char) C.int {
+	if !checkInit() {
+		return -1
+	}
+
+	removed, err := cache.GetManager().Evict()
+	if err != nil {
+		setLastError(-7, fmt.Sprintf("evict cache failed: %v", err))
+		return -7
+	}
+
+	return returnJSON(removed, outJSON)
+}
+
+// NStudioWarmCache starts generating the lines of a profile that aren't cached yet, in the background. requestJSON
+// is {"profile": "", "script": ""} or {"profile": "", "lines": [{"character": "", "text": ""}]}. Returns the
+// warm-up, poll its progress with NStudioGetWarmup. Returns -7 when the profile doesn't use the cache.
+//
+//export NStudioWarmCache
+func NStudioWarmCache(requestJSON *C.char, outJSON **C.char) C.int {
+	if !checkInit() {
+		return -1
+	}
+
+	var req warmup.Request
+	if err := json.Unmarshal([]byte(C.GoString(requestJSON)), &req); err != nil {
+		setLastError(-2, fmt.Sprintf("invalid request JSON: %v", err))
+		return -2
+	}
+
+	started, err := warmup.GetManager().Start(req, nil)
+	if errors.Is(err, cache.ErrDisabled) {
+		setLastError(-7, "the profile doesn't use the audio cache")
+		return -7
+	}
+	if err != nil {
+		setLastError(-2, fmt.Sprintf("warm cache failed: %v", err))
+		return -2
+	}
+
+	return returnJSON(started, outJSON)
+}
+
+// NStudioGetWarmup returns the progress of a warm-up: its state, done and total lines, and the lines that failed.
+//
+//export NStudioGetWarmup
+func NStudioGetWarmup(warmupID *C.char, outJSON **C.char) C.int {
+	if !checkInit() {
+		return -1
+	}
+
+	warmed := warmup.GetManager().Get(C.GoString(warmupID))
+	if warmed == nil {
+		setLastError(-2, "warm-up not found")
+		return -2
+	}
+
+	return returnJSON(warmed, outJSON)
+}
+
+// NStudioCancelWarmup stops a running warm-up and returns it. The lines it already warmed stay in the cache.
+//
+//export NStudioCancelWarmup
+func NStudioCancelWarmup(warmupID *C.char, outJSON **C.char) C.int {
+	if !checkInit() {
+		return -1
+	}
+
+	warmed := warmup.GetManager().Cancel(C.GoString(warmupID))
+	if warmed == nil {
+		setLastError(-2, "warm-up not found")
+		return -2
+	}
+
+	return returnJSON(warmed, outJSON)
+}
+
+// NStudioExportCacheBundle writes cached lines to a bundle file another install can import, and returns what it
+// holds. requestJSON is {"path": "", "profile": ""} to export every line of a profile, or {"path": "", "warmup": ""}
+// to export the lines of a finished warm-up.
+//
+//export NStudioExportCacheBundle
+func NStudioExportCacheBundle(requestJSON *C.char, outJSON **C.char) C.int {
+	if !checkInit() {
+		return -1
+	}
+
+	type exportRequest struct {
+		Path    string `json:"path"`
+		Profile string `json:"profile"`
+		Warmup  string `json:"warmup"`
+	}
+
+	var req exportRequest
+	if err := json.Unmarshal([]byte(C.GoString(requestJSON)), &req); err != nil {
+		setLastError(-2, fmt.Sprintf("invalid request JSON: %v", err))
+		return -2
+	}
+	if req.Path == "" || (req.Profile == "") == (req.Warmup == "") {
+		setLastError(-2, "path and either profile or warmup are required")
+		return -2
+	}
+
+	file, err := os.Create(req.Path)
+	if err != nil {
+		setLastError(-7, fmt.Sprintf("create cache bundle failed: %v", err))
+		return -7
+	}
+
+	var summary *cache.BundleSummary
+	if req.Warmup != "" {
+		summary, err = warmup.GetManager().ExportBundle(req.Warmup, file)
+	} else {
+		summary, err = cache.GetManager().ExportBundle(file, req.Profile, nil)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(req.Path)
+		setLastError(-7, fmt.Sprintf("export cache bundle failed: %v", err))
+		return -7
+	}
+
+	return returnJSON(summary, outJSON)
+}
+
+// NStudioImportCacheBundle adds the lines of a bundle file to the cache and returns what was imported. They are
+// referenced from profileID, or from the profile the bundle was exported from when profileID is NULL or empty.
+//
+//export NStudioImportCacheBundle
+func NStudioImportCacheBundle(path *C.char, profileID *C.char, outJSON **C.char) C.int {
+	if !checkInit() {
+		return -1
+	}
+
+	var id string
+	if profileID != nil {
+		id = C.GoString(profileID)
+	}
+
+	file, err := os.Open(C.GoString(path))
+	if err != nil {
+		setLastError(-2, fmt.Sprintf("open cache bundle failed: %v", err))
+		return -2
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		setLastError(-2, fmt.Sprintf("open cache bundle failed: %v", err))
+		return -2
+	}
+
+	summary, err := cache.GetManager().ImportBundle(file, info.Size(), id)
+	if err != nil {
+		setLastError(-7, fmt.Sprintf("import cache bundle failed: %v", err))
+		return -7
+	}
+
+	return returnJSON(summary, outJSON)
+}
+
 // ---------------------------------------------------------------------------
 // Model Management
 // ---------------------------------------------------------------------------